@@ -0,0 +1,48 @@
+package main
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/repository"
+	logger "cerberus/internal/tools/logger"
+	"cerberus/pkg/config"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// main runs the one-off email collision check.
+//
+// It connects to PostgreSQL using the same configuration as the server and lists every
+// email address shared by more than one user when compared case-insensitively. Those
+// accounts must be merged or renamed before the lower(email) unique index can be created.
+//
+// The process exits with status 1 if collisions are found or the check fails, 0 otherwise.
+func main() {
+	cfg, err := config.LoadEnvFile(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		cfg = &config.DefaultCfg
+	}
+
+	db, err := database.ConnectPostgres(cfg)
+	if err != nil {
+		logger.Log(fmt.Sprintf("Something went wrong! %s", err.Error()), logger.ERROR)
+		os.Exit(1)
+	}
+
+	collisions, err := repository.FindEmailCollisions(db)
+	if err != nil {
+		logger.Log(fmt.Sprintf("Failed to check email collisions - %s", err.Error()), logger.ERROR)
+		os.Exit(1)
+	}
+
+	if len(collisions) == 0 {
+		logger.Log("✅ No case-insensitive email collisions found", logger.INFO)
+		return
+	}
+
+	for _, c := range collisions {
+		logger.Log(fmt.Sprintf("Email collision: %s -> [%s]", c.Email, strings.Join(c.UserIds, ", ")), logger.WARN)
+	}
+	logger.Log(fmt.Sprintf("💥 Found %d colliding email addresses", len(collisions)), logger.ERROR)
+	os.Exit(1)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/models"
+	"cerberus/internal/services"
+	logger "cerberus/internal/tools/logger"
+	"cerberus/pkg/config"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// main grants or revokes a role for an existing user.
+//
+// It is mainly used to bootstrap the first administrator, since the administration API
+// itself requires the admin role:
+//
+//	go run cmd/grant_role/main.go -email admin@example.com
+//	go run cmd/grant_role/main.go -email admin@example.com -revoke
+func main() {
+	mail := flag.String("email", "", "email address of the target user")
+	role := flag.String("role", models.RoleAdmin, "role to grant or revoke")
+	revoke := flag.Bool("revoke", false, "revoke the role instead of granting it")
+	flag.Parse()
+
+	if *mail == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadEnvFile(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		cfg = &config.DefaultCfg
+	}
+
+	db, err := database.ConnectPostgres(cfg)
+	if err != nil {
+		logger.Log(fmt.Sprintf("Something went wrong! %s", err.Error()), logger.ERROR)
+		os.Exit(1)
+	}
+
+	usr, err := services.SetUserRole(db, *mail, *role, !*revoke)
+	if err != nil {
+		logger.Log(fmt.Sprintf("Failed to update roles of %s - %s", *mail, err.Error()), logger.ERROR)
+		os.Exit(1)
+	}
+
+	logger.Log(fmt.Sprintf("✅ Roles of %s: %v", usr.Email, usr.Roles), logger.INFO)
+}
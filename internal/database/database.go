@@ -3,6 +3,10 @@ package database
 import (
 	"cerberus/internal/tools/jwt"
 	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/mailer"
+	"cerberus/internal/tools/oauth"
+	"cerberus/internal/tools/otp"
+	"cerberus/internal/tools/samlsp"
 	"cerberus/pkg/config"
 	"fmt"
 
@@ -16,6 +20,10 @@ type DataRefs struct {
 
 	Redis  *RedisPack
 	JWTGen *jwt.JWTGenerator
+	Mailer mailer.Mailer
+	OAuth  map[string]oauth.Provider
+	SAML   map[string]*samlsp.Provider
+	OTP    map[string]otp.Sender
 
 	ConfigData *config.ConfigData
 }
@@ -50,11 +58,28 @@ func InitDatabases(p_config *config.ConfigData) (*DataRefs, error) {
 		return nil, err
 	}
 
+	gen, err := jwt.NewJWTGenerator(p_config)
+	if err != nil {
+		logger.Log(err.Error(), logger.ERROR)
+		return nil, err
+	}
+
+	samlProviders, err := samlsp.NewProviders(&p_config.SAMLData)
+	if err != nil {
+		logger.Log(err.Error(), logger.ERROR)
+		return nil, err
+	}
+
+	mail := mailer.NewMailer(p_config)
 	return &DataRefs{
 		Postgres: pdb,
 
 		Redis:  rdb,
-		JWTGen: jwt.NewJWTGenerator(p_config),
+		JWTGen: gen,
+		Mailer: mail,
+		OAuth:  oauth.NewProviders(&p_config.OAuthData),
+		SAML:   samlProviders,
+		OTP:    otp.NewSenders(p_config, mail),
 
 		ConfigData: p_config,
 	}, nil
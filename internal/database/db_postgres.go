@@ -42,11 +42,22 @@ func ConnectPostgres(p_cfg *config.ConfigData) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	if err := db.AutoMigrate(&models.User{}); err != nil {
+	if err := db.AutoMigrate(&models.User{}, &models.AuditEvent{}, &models.WebhookSubscription{},
+		&models.WebhookDelivery{}, &models.Identity{}, &models.PersonalToken{}); err != nil {
 		logger.Log(fmt.Sprintf("AutoMigration failed - %s", err.Error()), logger.ERROR)
 		return nil, err
 	}
 
+	if err := migrateAuditImmutability(db); err != nil {
+		logger.Log(fmt.Sprintf("Failed to protect the audit log - %s", err.Error()), logger.ERROR)
+		return nil, err
+	}
+
+	if err := migrateEmailIndex(db); err != nil {
+		logger.Log(fmt.Sprintf("Failed to create case-insensitive email index, "+
+			"run the email collision check - %s", err.Error()), logger.WARN)
+	}
+
 	logger.Log("🌲 PostgresSQL migrations completed", logger.INFO)
 	return db, nil
 }
@@ -68,4 +79,75 @@ func migrate(p_db *gorm.DB) error {
 	return p_db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"").Error
 }
 
+// migrateEmailIndex replaces the case-sensitive unique constraint on users.email with a
+// unique index on lower(email), so addresses differing only by case cannot be registered twice.
+// The index is partial: soft-deleted accounts keep their address until they are purged, and
+// must not keep it from being registered again.
+//
+// The index creation fails if the table already holds case-insensitive duplicates. In that
+// case the previous constraint is kept and the error is returned so the caller can report it;
+// the collisions can be listed with the cmd/email_collisions job.
+//
+// Parameters:
+//   - p_db: A pointer to a gorm.DB instance representing the database connection.
+//
+// Returns:
+//   - error: An error if any statement fails, or nil if successful.
+func migrateEmailIndex(p_db *gorm.DB) error {
+	return p_db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower_active ON users (lower(email)) " +
+			"WHERE deleted_at IS NULL").Error; err != nil {
+			return err
+		}
+		// Created by previous versions, and covering soft-deleted accounts too.
+		if err := tx.Exec("DROP INDEX IF EXISTS idx_users_email_lower").Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("ALTER TABLE users DROP CONSTRAINT IF EXISTS uni_users_email").Error; err != nil {
+			return err
+		}
+		return tx.Exec("DROP INDEX IF EXISTS idx_users_email").Error
+	})
+}
+
+// migrateAuditImmutability installs a trigger that rejects any UPDATE or DELETE on the
+// audit_events table, making the audit log append-only at the database level.
+//
+// The only exception is the pseudonymisation of the events of purged accounts (see
+// repository.PurgeDeletedUsers): a transaction setting "cerberus.audit_pseudonymise" may clear
+// the IP address, User-Agent and details of an event, and change nothing else.
+//
+// Parameters:
+//   - p_db: A pointer to a gorm.DB instance representing the database connection.
+//
+// Returns:
+//   - error: An error if any statement fails, or nil if successful.
+func migrateAuditImmutability(p_db *gorm.DB) error {
+	return p_db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`CREATE OR REPLACE FUNCTION audit_events_immutable() RETURNS trigger AS $$
+			BEGIN
+				IF TG_OP = 'UPDATE' AND current_setting('cerberus.audit_pseudonymise', true) = 'on'
+					AND NEW.id = OLD.id AND NEW.created_at = OLD.created_at AND NEW.type = OLD.type
+					AND NEW.actor_id IS NOT DISTINCT FROM OLD.actor_id
+					AND NEW.target_id IS NOT DISTINCT FROM OLD.target_id
+					AND NEW.outcome = OLD.outcome
+					AND NEW.ip = '' AND NEW.user_agent = '' AND NEW.details IS NULL THEN
+					RETURN NEW;
+				END IF;
+				RAISE EXCEPTION 'audit_events is append-only';
+			END;
+			$$ LANGUAGE plpgsql`).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Exec("DROP TRIGGER IF EXISTS audit_events_immutable ON audit_events").Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`CREATE TRIGGER audit_events_immutable BEFORE UPDATE OR DELETE ON audit_events
+			FOR EACH ROW EXECUTE FUNCTION audit_events_immutable()`).Error
+	})
+}
+
 // endregion Private
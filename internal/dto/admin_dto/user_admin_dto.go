@@ -0,0 +1,95 @@
+package admin_dto
+
+import (
+	"cerberus/internal/models"
+	"time"
+)
+
+// UserSummary represents a user as seen by an administrator.
+//
+// Fields:
+//   - UserId: The unique identifier of the user.
+//   - Name: The display name of the user.
+//   - Email: The canonical email address of the user.
+//   - Status: The account status.
+//   - StatusReason: Why the status was last changed.
+//   - StatusChangedAt: When the status was last changed, omitted if it never changed.
+//   - Roles: The roles granted to the user.
+//   - PasswordResetRequired: Whether the user must change their password before logging in.
+//   - CreatedAt: When the account was created.
+type UserSummary struct {
+	UserId                string     `json:"user_id"`
+	Name                  string     `json:"name"`
+	Email                 string     `json:"email"`
+	Status                string     `json:"status"`
+	StatusReason          string     `json:"status_reason"`
+	StatusChangedAt       *time.Time `json:"status_changed_at,omitempty"`
+	Roles                 []string   `json:"roles"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+// NewUserSummary maps a user model to its administration representation.
+//
+// Parameters:
+//   - p_usr: A pointer to the user model.
+//
+// Returns:
+//   - UserSummary: The administration view of the user.
+func NewUserSummary(p_usr *models.User) UserSummary {
+	roles := p_usr.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+
+	return UserSummary{
+		UserId:                p_usr.ID.String(),
+		Name:                  p_usr.Name,
+		Email:                 p_usr.Email,
+		Status:                p_usr.Status,
+		StatusReason:          p_usr.StatusReason,
+		StatusChangedAt:       p_usr.StatusChangedAt,
+		Roles:                 roles,
+		PasswordResetRequired: p_usr.PasswordResetRequired,
+		CreatedAt:             p_usr.CreatedAt,
+	}
+}
+
+// ListUsersResponse represents a page of users.
+//
+// Fields:
+//   - Users: The users of the page.
+//   - Page: The 1-based page number.
+//   - PageSize: The maximum number of users per page.
+//   - Total: The total number of users matching the search.
+type ListUsersResponse struct {
+	Users    []UserSummary `json:"users"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"page_size"`
+	Total    int64         `json:"total"`
+}
+
+// ChangeStatusRequest represents a request to change the status of a user.
+//
+// Fields:
+//   - Status: The new status. It can be omitted on routes targeting a fixed status.
+//   - Reason: Why the status is being changed.
+type ChangeStatusRequest struct {
+	Status string `json:"status" validate:"oneof=active disabled locked pending"`
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// AdminMessageResponse represents a simple message response for administration operations.
+type AdminMessageResponse struct {
+	Message string `json:"message"`
+}
+
+// AdminDeleteUserResponse represents the response to a user deletion by an administrator.
+//
+// Fields:
+//   - Message: A confirmation message.
+//   - PurgeAt: When the account data will be permanently removed.
+type AdminDeleteUserResponse struct {
+	Message string    `json:"message"`
+	PurgeAt time.Time `json:"purge_at"`
+}
@@ -0,0 +1,95 @@
+package audit_dto
+
+import (
+	"cerberus/internal/models"
+	"time"
+)
+
+// AuditEventResponse represents a single entry of the security audit log.
+//
+// Fields:
+//   - EventId: The unique identifier of the event.
+//   - Type: The kind of event (e.g., "login_success").
+//   - ActorId: The user who performed the action, omitted for anonymous requests.
+//   - TargetId: The user affected by the action, omitted if unknown.
+//   - IP: The client IP address of the request.
+//   - UserAgent: The User-Agent header of the request.
+//   - Outcome: Either "success" or "failure".
+//   - Details: Additional event-specific information.
+//   - CreatedAt: When the event was recorded.
+type AuditEventResponse struct {
+	EventId   string            `json:"event_id"`
+	Type      string            `json:"type"`
+	ActorId   string            `json:"actor_id,omitempty"`
+	TargetId  string            `json:"target_id,omitempty"`
+	IP        string            `json:"ip"`
+	UserAgent string            `json:"user_agent"`
+	Outcome   string            `json:"outcome"`
+	Details   map[string]string `json:"details,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// NewAuditEventResponse maps an audit event model to its API representation.
+//
+// Parameters:
+//   - p_event: A pointer to the audit event model.
+//
+// Returns:
+//   - AuditEventResponse: The API view of the event.
+func NewAuditEventResponse(p_event *models.AuditEvent) AuditEventResponse {
+	res := AuditEventResponse{
+		EventId:   p_event.ID.String(),
+		Type:      p_event.Type,
+		IP:        p_event.IP,
+		UserAgent: p_event.UserAgent,
+		Outcome:   p_event.Outcome,
+		Details:   p_event.Details,
+		CreatedAt: p_event.CreatedAt,
+	}
+	if p_event.ActorID != nil {
+		res.ActorId = p_event.ActorID.String()
+	}
+	if p_event.TargetID != nil {
+		res.TargetId = p_event.TargetID.String()
+	}
+
+	return res
+}
+
+// ListAuditEventsResponse represents a page of audit events.
+//
+// Fields:
+//   - Events: The events of the page, newest first.
+//   - Page: The 1-based page number.
+//   - PageSize: The maximum number of events per page.
+//   - Total: The total number of events matching the filter.
+type ListAuditEventsResponse struct {
+	Events   []AuditEventResponse `json:"events"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"page_size"`
+	Total    int64                `json:"total"`
+}
+
+// NewListAuditEventsResponse maps a page of audit events to its API representation.
+//
+// Parameters:
+//   - p_events: The events of the page.
+//   - p_page: The 1-based page number.
+//   - p_pageSize: The maximum number of events per page.
+//   - p_total: The total number of events matching the filter.
+//
+// Returns:
+//   - ListAuditEventsResponse: The API view of the page.
+func NewListAuditEventsResponse(p_events []models.AuditEvent, p_page int, p_pageSize int, p_total int64) ListAuditEventsResponse {
+	res := ListAuditEventsResponse{
+		Events:   make([]AuditEventResponse, 0, len(p_events)),
+		Page:     p_page,
+		PageSize: p_pageSize,
+		Total:    p_total,
+	}
+	for i := range p_events {
+		res.Events = append(res.Events, NewAuditEventResponse(&p_events[i]))
+	}
+
+	return res
+}
@@ -2,9 +2,9 @@ package auth_dto
 
 // ChangePasswordRequest represents the data structure for a password change request.
 type ChangePasswordRequest struct {
-	Email           string `json:"email"`
-	CurrentPassword string `json:"current_password"`
-	NewPassword     string `json:"new_password"`
+	Email           string `json:"email" validate:"required,email,max=254"`
+	CurrentPassword string `json:"current_password" validate:"required,maxbytes=72"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,maxbytes=72"`
 }
 
 // ChangePasswordResponse represents the data structure for a password change response.
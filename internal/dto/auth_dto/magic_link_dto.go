@@ -0,0 +1,15 @@
+package auth_dto
+
+// MagicLink represents a login link sent by email and not consumed yet. It is stored in Redis
+// under the token of the link.
+//
+// Fields:
+//   - UserID: The ID of the user the link logs in.
+//   - Nonce: The value set in a cookie of the browser that requested the link, which must
+//     consume it.
+//   - UseCookies: Whether the session is a browser session, set in cookies.
+type MagicLink struct {
+	UserID     string `json:"user_id"`
+	Nonce      string `json:"nonce"`
+	UseCookies bool   `json:"use_cookies"`
+}
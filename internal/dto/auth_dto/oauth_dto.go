@@ -0,0 +1,16 @@
+package auth_dto
+
+// OAuthState represents a federated login started at an identity provider and waiting for
+// its callback. It is stored in Redis under the state sent to the provider.
+//
+// Fields:
+//   - Provider: The name of the provider the login was started with.
+//   - Nonce: The nonce bound to the ID token of OpenID Connect providers.
+//   - Verifier: The PKCE code verifier.
+//   - UseCookies: Whether the session is a browser session, set in cookies.
+type OAuthState struct {
+	Provider   string `json:"provider"`
+	Nonce      string `json:"nonce"`
+	Verifier   string `json:"verifier"`
+	UseCookies bool   `json:"use_cookies"`
+}
@@ -16,8 +16,10 @@ type RegisterResponse struct {
 //   - Email: A string containing the email address of the user to be registered.
 //   - Name: A string containing the name of the user to be registered.
 //   - Password: A string containing the password for the new user account.
+//
+// The password is capped at 72 bytes, the maximum input length accepted by bcrypt.
 type RegisterRequest struct {
-	Email    string `json:"email"`
-	Name     string `json:"name"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,email,max=254"`
+	Name     string `json:"name" validate:"required,max=100"`
+	Password string `json:"password" validate:"required,min=8,maxbytes=72"`
 }
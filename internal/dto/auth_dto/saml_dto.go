@@ -0,0 +1,24 @@
+package auth_dto
+
+// SAMLRequest represents a SAML login whose authentication request was sent to an identity
+// provider, waiting for its response. It is stored in Redis under the relay state.
+//
+// Fields:
+//   - Provider: The name of the provider the login was started with.
+//   - RequestID: The ID of the authentication request, which the response must answer.
+//   - UseCookies: Whether the session is a browser session, set in cookies.
+//   - LinkUserID: The user linking the identity to their account, empty for a login.
+type SAMLRequest struct {
+	Provider   string `json:"provider"`
+	RequestID  string `json:"request_id"`
+	UseCookies bool   `json:"use_cookies"`
+	LinkUserID string `json:"link_user_id,omitempty"`
+}
+
+// SAMLLinkResponse represents the response to the start of an identity link.
+//
+// Fields:
+//   - RedirectURL: The URL of the identity provider the browser must be sent to.
+type SAMLLinkResponse struct {
+	RedirectURL string `json:"redirect_url"`
+}
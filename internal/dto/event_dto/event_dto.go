@@ -0,0 +1,18 @@
+package event_dto
+
+import "time"
+
+// Event is the JSON representation of an identity event, shared by the Redis Stream entries
+// and the webhook deliveries. See docs/events.md for the schema of every event type.
+//
+// Fields:
+//   - EventId: The unique identifier of the event, used by consumers for deduplication.
+//   - Type: The type of the event (e.g., "user.registered").
+//   - OccurredAt: When the event happened, in UTC.
+//   - Data: Event-specific information, always including the "user_id".
+type Event struct {
+	EventId    string            `json:"event_id"`
+	Type       string            `json:"type"`
+	OccurredAt time.Time         `json:"occurred_at"`
+	Data       map[string]string `json:"data"`
+}
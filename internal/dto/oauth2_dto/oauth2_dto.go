@@ -0,0 +1,92 @@
+package oauth2_dto
+
+import "time"
+
+// DeviceAuthorizationResponse represents the answer to a device authorization request
+// (RFC 8628, section 3.2).
+//
+// Fields:
+//   - DeviceCode: The code the device polls the token endpoint with. It is never shown.
+//   - UserCode: The code the user types on another device.
+//   - VerificationURI: The page where the user types the code.
+//   - VerificationURIComplete: The page with the code filled in, e.g. for a QR code.
+//   - ExpiresIn: Seconds before the codes expire.
+//   - Interval: Seconds the device must wait between two polls.
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// TokenResponse represents a successful answer of the token endpoint (RFC 6749, section 5.1).
+//
+// Fields:
+//   - AccessToken: The issued token.
+//   - TokenType: Always "Bearer".
+//   - ExpiresIn: Seconds before the token expires.
+//   - Scope: The space separated scopes granted to the token.
+//   - IssuedTokenType: The type of the issued token, only set by the token exchange grant.
+type TokenResponse struct {
+	AccessToken     string `json:"access_token"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int    `json:"expires_in"`
+	Scope           string `json:"scope,omitempty"`
+	IssuedTokenType string `json:"issued_token_type,omitempty"`
+}
+
+// TokenExchangeRequest represents a token exchange request (RFC 8693, section 2.1), read from
+// the form parameters of the token endpoint.
+//
+// Fields:
+//   - SubjectToken: The token to exchange, or the ID of the impersonated user.
+//   - SubjectTokenType: The type of SubjectToken.
+//   - ActorToken: The access token of the admin impersonating the user, empty for delegation.
+//   - ActorTokenType: The type of ActorToken.
+//   - RequestedTokenType: The type of token requested, an access token if empty.
+//   - Scope: The space separated scopes requested.
+//   - Audience: The audiences requested.
+type TokenExchangeRequest struct {
+	SubjectToken       string
+	SubjectTokenType   string
+	ActorToken         string
+	ActorTokenType     string
+	RequestedTokenType string
+	Scope              string
+	Audience           []string
+}
+
+// ErrorResponse represents an error of the OAuth 2.0 endpoints (RFC 6749, section 5.2).
+//
+// Fields:
+//   - Error: The error code (e.g., "authorization_pending").
+//   - ErrorDescription: A human readable explanation.
+type ErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// DeviceRequestResponse describes a pending device authorization request to the user asked to
+// approve it.
+//
+// Fields:
+//   - UserCode: The user code of the request.
+//   - ClientId: The client the device runs.
+//   - Scope: The space separated scopes requested.
+//   - Status: "pending", "approved" or "denied".
+//   - ExpiresAt: When the request expires.
+type DeviceRequestResponse struct {
+	UserCode  string    `json:"user_code"`
+	ClientId  string    `json:"client_id"`
+	Scope     string    `json:"scope"`
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// DeviceDecisionRequest represents the decision of the user on a device authorization request.
+type DeviceDecisionRequest struct {
+	UserCode string `json:"user_code" validate:"required,max=16"`
+	Action   string `json:"action" validate:"required,oneof=approve deny"`
+}
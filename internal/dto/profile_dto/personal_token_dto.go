@@ -0,0 +1,68 @@
+package profile_dto
+
+import (
+	"cerberus/internal/models"
+	"time"
+)
+
+// CreatePersonalTokenRequest represents a request to create a personal access token.
+//
+// Fields:
+//   - Name: A name identifying the token, unique among the tokens of the user.
+//   - Scopes: The scopes granted to the token (at least one).
+//   - ExpiresAt: When the token stops being accepted. The token never expires when omitted.
+type CreatePersonalTokenRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// PersonalTokenResponse represents a personal access token.
+//
+// Fields:
+//   - TokenId: The unique identifier of the token.
+//   - Name: The name of the token.
+//   - Prefix: The public start of the token, to recognize it.
+//   - Scopes: The scopes granted to the token.
+//   - Token: The token itself, only returned when it is created.
+//   - ExpiresAt: When the token expires, omitted if it never does.
+//   - LastUsedAt: When the token was last used, omitted if never.
+//   - CreatedAt: When the token was created.
+type PersonalTokenResponse struct {
+	TokenId    string     `json:"token_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Token      string     `json:"token,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewPersonalTokenResponse maps a personal access token model to its API representation,
+// without the token itself.
+//
+// Parameters:
+//   - p_tkn: A pointer to the token model.
+//
+// Returns:
+//   - PersonalTokenResponse: The API view of the token.
+func NewPersonalTokenResponse(p_tkn *models.PersonalToken) PersonalTokenResponse {
+	return PersonalTokenResponse{
+		TokenId:    p_tkn.ID.String(),
+		Name:       p_tkn.Name,
+		Prefix:     p_tkn.Prefix,
+		Scopes:     p_tkn.Scopes,
+		ExpiresAt:  p_tkn.ExpiresAt,
+		LastUsedAt: p_tkn.LastUsedAt,
+		CreatedAt:  p_tkn.CreatedAt,
+	}
+}
+
+// ListPersonalTokensResponse represents the personal access tokens of the authenticated user.
+//
+// Fields:
+//   - Tokens: The tokens, oldest first.
+type ListPersonalTokensResponse struct {
+	Tokens []PersonalTokenResponse `json:"tokens"`
+}
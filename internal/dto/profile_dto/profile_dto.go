@@ -0,0 +1,136 @@
+package profile_dto
+
+import (
+	"cerberus/internal/dto/audit_dto"
+	"cerberus/internal/models"
+	"time"
+)
+
+// ProfileResponse represents the profile of the authenticated user.
+//
+// Fields:
+//   - UserId: The unique identifier of the user.
+//   - Name: The display name of the user.
+//   - Email: The canonical email address of the user.
+//   - Phone: The verified phone number of the user, omitted if none.
+//   - Attributes: Free-form profile attributes set by the user.
+//   - CreatedAt: When the account was created.
+type ProfileResponse struct {
+	UserId     string            `json:"user_id"`
+	Name       string            `json:"name"`
+	Email      string            `json:"email"`
+	Phone      string            `json:"phone,omitempty"`
+	Attributes map[string]string `json:"attributes"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// NewProfileResponse maps a user model to its public profile representation.
+//
+// Parameters:
+//   - p_usr: A pointer to the user model.
+//
+// Returns:
+//   - ProfileResponse: The profile of the user, with an empty attribute map if none are set.
+func NewProfileResponse(p_usr *models.User) ProfileResponse {
+	attrs := p_usr.Attributes
+	if attrs == nil {
+		attrs = map[string]string{}
+	}
+
+	return ProfileResponse{
+		UserId:     p_usr.ID.String(),
+		Name:       p_usr.Name,
+		Email:      p_usr.Email,
+		Phone:      p_usr.Phone,
+		Attributes: attrs,
+		CreatedAt:  p_usr.CreatedAt,
+	}
+}
+
+// UpdateProfileRequest represents a partial update of the authenticated user's profile.
+//
+// Fields:
+//   - Name: The new display name, left unchanged when omitted.
+//   - Attributes: Attributes to merge into the profile. A null value removes the attribute.
+type UpdateProfileRequest struct {
+	Name       *string            `json:"name,omitempty" validate:"max=100"`
+	Attributes map[string]*string `json:"attributes,omitempty"`
+}
+
+// ChangeEmailRequest represents a request to change the email address of the authenticated user.
+// The current password is required to confirm the request.
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" validate:"required,email,max=254"`
+	Password string `json:"password" validate:"required,maxbytes=72"`
+}
+
+// ConfirmEmailRequest represents the confirmation of a pending email change using the
+// token sent to the new address.
+type ConfirmEmailRequest struct {
+	Token string `json:"token" validate:"required,max=128"`
+}
+
+// ChangePhoneRequest represents a request to set the phone number of the authenticated user.
+// A verification code is sent to the number by SMS.
+type ChangePhoneRequest struct {
+	Phone string `json:"phone" validate:"required,e164"`
+}
+
+// ConfirmPhoneRequest represents the confirmation of a pending phone number with the code sent
+// to it.
+type ConfirmPhoneRequest struct {
+	Code string `json:"code" validate:"required,numeric,max=10"`
+}
+
+// SetPasswordRequest represents a request to replace the password of the authenticated user.
+// No current password is asked for: the user must have reauthenticated recently instead.
+type SetPasswordRequest struct {
+	NewPassword string `json:"new_password" validate:"required,min=8,maxbytes=72"`
+}
+
+// ProfileMessageResponse represents a simple message response for profile operations.
+type ProfileMessageResponse struct {
+	Message string `json:"message"`
+}
+
+// DeleteAccountRequest represents a request to delete the authenticated user's account.
+// The current password is required to confirm the deletion, unless the account has none.
+type DeleteAccountRequest struct {
+	Password string `json:"password,omitempty" validate:"maxbytes=72"`
+}
+
+// DeleteAccountResponse represents the response to an account deletion.
+//
+// Fields:
+//   - Message: A confirmation message.
+//   - PurgeAt: When the account data will be permanently removed.
+type DeleteAccountResponse struct {
+	Message string    `json:"message"`
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// SessionExport describes a session token currently stored for the user.
+//
+// Fields:
+//   - Type: The kind of token ("access" or "refresh").
+//   - ExpiresAt: When the stored token expires.
+type SessionExport struct {
+	Type      string    `json:"type"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ExportResponse represents every piece of data stored about the authenticated user.
+//
+// Fields:
+//   - ExportedAt: When the export was generated.
+//   - Profile: The profile of the user.
+//   - Sessions: The session tokens currently stored for the user.
+//   - PersonalTokens: The personal access tokens of the user, without the tokens themselves.
+//   - SecurityEvents: The audit events performed by or affecting the user, newest first.
+type ExportResponse struct {
+	ExportedAt     time.Time                      `json:"exported_at"`
+	Profile        ProfileResponse                `json:"profile"`
+	Sessions       []SessionExport                `json:"sessions"`
+	PersonalTokens []PersonalTokenResponse        `json:"personal_tokens"`
+	SecurityEvents []audit_dto.AuditEventResponse `json:"security_events"`
+}
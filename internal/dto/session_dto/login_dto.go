@@ -5,9 +5,12 @@ package session_dto
 // Fields:
 //   - Email: A string containing the user's email address. It is mapped to the "email" JSON field.
 //   - Password: A string containing the user's password. It is mapped to the "password" JSON field.
+//   - UseCookies: Starts a browser session, with the tokens set in HttpOnly cookies instead of
+//     being returned in the response body. It is mapped to the "use_cookies" JSON field.
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email      string `json:"email" validate:"required,email,max=254"`
+	Password   string `json:"password" validate:"required,maxbytes=72"`
+	UseCookies bool   `json:"use_cookies"`
 }
 
 // LoginData represents the authentication tokens returned after a successful login.
@@ -21,8 +24,10 @@ type LoginData struct {
 }
 
 // LoginResponse represents the structure of a response to a login request.
+// Browser sessions receive the CSRF token of the session instead of the tokens.
 type LoginResponse struct {
 	Message      string `json:"message"`
-	Token        string `json:"token"`
-	RefreshToken string `json:"refresh_token"`
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	CSRFToken    string `json:"csrf_token,omitempty"`
 }
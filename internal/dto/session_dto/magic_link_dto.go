@@ -0,0 +1,23 @@
+package session_dto
+
+// MagicLinkRequest represents a request for a login link sent by email.
+//
+// Fields:
+//   - Email: The email address of the account.
+//   - UseCookies: Starts a browser session when the link is consumed, with the tokens set in
+//     HttpOnly cookies instead of being returned in the response body.
+type MagicLinkRequest struct {
+	Email      string `json:"email" validate:"required,email,max=254"`
+	UseCookies bool   `json:"use_cookies"`
+}
+
+// MagicLinkResponse represents the response to a login link request. It is the same whether
+// the account exists or not.
+type MagicLinkResponse struct {
+	Message string `json:"message"`
+}
+
+// ConsumeMagicLinkRequest represents the request logging in with the token of a login link.
+type ConsumeMagicLinkRequest struct {
+	Token string `json:"token" validate:"required,max=128"`
+}
@@ -0,0 +1,51 @@
+package session_dto
+
+// OTPLoginRequest represents a request for a one-time login code.
+//
+// Fields:
+//   - Email: The email address of the account.
+//   - Channel: Where the code is sent, "email" (default) or "sms" to the verified phone number.
+//   - UseCookies: Starts a browser session when the code is verified, with the tokens set in
+//     HttpOnly cookies instead of being returned in the response body.
+type OTPLoginRequest struct {
+	Email      string `json:"email" validate:"required,email,max=254"`
+	Channel    string `json:"channel,omitempty" validate:"oneof=email sms"`
+	UseCookies bool   `json:"use_cookies"`
+}
+
+// OTPVerifyRequest represents the request logging in with a one-time code.
+type OTPVerifyRequest struct {
+	Email string `json:"email" validate:"required,email,max=254"`
+	Code  string `json:"code" validate:"required,numeric,max=10"`
+}
+
+// OTPLoginData represents a verified one-time login code.
+//
+// Fields:
+//   - UseCookies: Whether the code was requested for a browser session.
+//   - Methods: The authentication methods proven by the code (e.g., "otp", "sms").
+type OTPLoginData struct {
+	UseCookies bool
+	Methods    []string
+}
+
+// OTPChallengeRequest represents a request for a code sent to the authenticated user, to
+// reauthenticate with.
+//
+// Fields:
+//   - Channel: Where the code is sent, "email" (default) or "sms" to the verified phone number.
+type OTPChallengeRequest struct {
+	Channel string `json:"channel,omitempty" validate:"oneof=email sms"`
+}
+
+// OTPSentResponse represents the response to a one-time code request.
+//
+// Fields:
+//   - Message: A human readable message.
+//   - Channel: The channel the code was sent on, omitted on login requests.
+//   - SentTo: The masked recipient of the code, omitted on login requests.
+type OTPSentResponse struct {
+	Message string `json:"message"`
+	Channel string `json:"channel,omitempty"`
+	SentTo  string `json:"sent_to,omitempty"`
+}
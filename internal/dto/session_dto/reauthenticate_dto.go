@@ -0,0 +1,36 @@
+package session_dto
+
+import "time"
+
+// ReauthenticateRequest represents a request proving the identity of the authenticated user
+// again, with either their password or a code sent with the reauthentication challenge.
+type ReauthenticateRequest struct {
+	Password string `json:"password,omitempty" validate:"maxbytes=72"`
+	Code     string `json:"code,omitempty" validate:"numeric,max=10"`
+}
+
+// ReauthenticateData represents the outcome of a reauthentication.
+//
+// Fields:
+//   - AccessToken: The new access token of the session, carrying the refreshed claims.
+//   - AuthTime: When the user authenticated, now.
+//   - AMR: The authentication methods used in the session.
+//   - ACR: The authentication context class reached in the session.
+type ReauthenticateData struct {
+	AccessToken string
+	AuthTime    time.Time
+	AMR         []string
+	ACR         string
+}
+
+// ReauthenticateResponse represents the response to a reauthentication. The session and its
+// refresh token are kept; only the access token is replaced. Browser sessions receive the new
+// CSRF token instead of the access token.
+type ReauthenticateResponse struct {
+	Message   string    `json:"message"`
+	Token     string    `json:"token,omitempty"`
+	CSRFToken string    `json:"csrf_token,omitempty"`
+	AuthTime  time.Time `json:"auth_time"`
+	AMR       []string  `json:"amr"`
+	ACR       string    `json:"acr"`
+}
@@ -1,9 +1,10 @@
 package session_dto
 
 // RefreshRequest represents the request payload for refreshing an access token.
-// It contains the refresh token used to generate a new access token.
+// It contains the refresh token used to generate a new access token. Browser sessions send no
+// body, their refresh token is read from the refresh cookie.
 type RefreshRequest struct {
-	RefreshToken string `json:"refresh_token"`
+	RefreshToken string `json:"refresh_token" validate:"required,max=128"`
 }
 
 // RefreshData holds the data generated during the token refresh process.
@@ -15,8 +16,10 @@ type RefreshData struct {
 
 // RefreshResponse represents the response payload returned after a successful token refresh.
 // It includes a success message, the new access token, and the new refresh token.
+// Browser sessions receive the new CSRF token of the session instead of the tokens.
 type RefreshResponse struct {
 	Message      string `json:"message"`
-	Token        string `json:"token"`
-	RefreshToken string `json:"refresh_token"`
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	CSRFToken    string `json:"csrf_token,omitempty"`
 }
@@ -0,0 +1,25 @@
+package session_dto
+
+import "time"
+
+// Revocation describes an access token revoked before its expiry.
+//
+// Fields:
+//   - TokenId: The "jti" claim of the revoked token.
+//   - ExpiresAt: When the token expires; verifiers can forget it afterwards.
+//   - RevokedAt: When the token was revoked.
+type Revocation struct {
+	TokenId   string    `json:"jti"`
+	ExpiresAt time.Time `json:"expires_at"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// RevocationsResponse represents a page of the token revocation feed.
+//
+// Fields:
+//   - Revocations: The revoked tokens, oldest revocation first.
+//   - NextSince: The value to send as "since" on the next poll.
+type RevocationsResponse struct {
+	Revocations []Revocation `json:"revocations"`
+	NextSince   time.Time    `json:"next_since"`
+}
@@ -0,0 +1,37 @@
+package session_dto
+
+import (
+	"cerberus/internal/tools/jwt"
+	"time"
+)
+
+// ValidateResponse represents the claims of an access token confirmed as active by the
+// validate endpoint. Services that cannot verify tokens locally use it for remote introspection.
+//
+// Fields:
+//   - Active: Always true; inactive tokens are answered with 401.
+//   - UserId: The user the token was issued to.
+//   - TokenId: The "jti" claim of the token.
+//   - Issuer: The "iss" claim of the token.
+//   - Audience: The "aud" claim of the token.
+//   - Scope: The space separated scopes of the token, omitted for session tokens.
+//   - AuthTime: When the user last authenticated in the session, omitted if unknown.
+//   - AMR: The authentication methods used in the session.
+//   - ACR: The authentication context class reached in the session.
+//   - Act: The admin acting as the user, for impersonation tokens.
+//   - IssuedAt: When the token was issued.
+//   - ExpiresAt: When the token expires.
+type ValidateResponse struct {
+	Active    bool       `json:"active"`
+	UserId    string     `json:"user_id"`
+	TokenId   string     `json:"jti"`
+	Issuer    string     `json:"iss"`
+	Audience  []string   `json:"aud"`
+	Scope     string     `json:"scope,omitempty"`
+	AuthTime  *time.Time `json:"auth_time,omitempty"`
+	AMR       []string   `json:"amr,omitempty"`
+	ACR       string     `json:"acr,omitempty"`
+	Act       *jwt.Actor `json:"act,omitempty"`
+	IssuedAt  time.Time  `json:"iat"`
+	ExpiresAt time.Time  `json:"exp"`
+}
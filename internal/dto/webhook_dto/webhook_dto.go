@@ -0,0 +1,146 @@
+package webhook_dto
+
+import (
+	"cerberus/internal/models"
+	"encoding/json"
+	"time"
+)
+
+// CreateWebhookRequest represents the payload used to subscribe an endpoint to identity events.
+//
+// Fields:
+//   - URL: The endpoint receiving the deliveries.
+//   - Secret: The signing key, generated by the server when omitted.
+//   - EventTypes: The event types sent to the endpoint (at least one).
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" validate:"required,url,max=2048"`
+	Secret     string   `json:"secret" validate:"min=16,max=256"`
+	EventTypes []string `json:"event_types"`
+}
+
+// UpdateWebhookRequest represents a partial update of a webhook subscription.
+// Omitted fields are left unchanged.
+//
+// Fields:
+//   - URL: The new endpoint.
+//   - EventTypes: The new event types, replacing the previous ones.
+//   - Active: Whether new events are delivered to the endpoint.
+//   - RotateSecret: When true, a new signing key is generated and returned.
+type UpdateWebhookRequest struct {
+	URL          *string  `json:"url" validate:"url,max=2048"`
+	EventTypes   []string `json:"event_types"`
+	Active       *bool    `json:"active"`
+	RotateSecret bool     `json:"rotate_secret"`
+}
+
+// WebhookResponse represents a webhook subscription.
+//
+// Fields:
+//   - WebhookId: The unique identifier of the subscription.
+//   - URL: The endpoint receiving the deliveries.
+//   - EventTypes: The event types sent to the endpoint.
+//   - Active: Whether new events are delivered to the endpoint.
+//   - Secret: The signing key, only returned when it was created or rotated.
+//   - CreatedAt: When the subscription was created.
+//   - UpdatedAt: When the subscription was last modified.
+type WebhookResponse struct {
+	WebhookId  string    `json:"webhook_id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	Secret     string    `json:"secret,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// NewWebhookResponse maps a subscription model to its API representation, without its secret.
+//
+// Parameters:
+//   - p_sub: A pointer to the subscription model.
+//
+// Returns:
+//   - WebhookResponse: The API view of the subscription.
+func NewWebhookResponse(p_sub *models.WebhookSubscription) WebhookResponse {
+	return WebhookResponse{
+		WebhookId:  p_sub.ID.String(),
+		URL:        p_sub.URL,
+		EventTypes: p_sub.EventTypes,
+		Active:     p_sub.Active,
+		CreatedAt:  p_sub.CreatedAt,
+		UpdatedAt:  p_sub.UpdatedAt,
+	}
+}
+
+// ListWebhooksResponse represents every webhook subscription.
+//
+// Fields:
+//   - Webhooks: The subscriptions, oldest first.
+type ListWebhooksResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+// DeliveryResponse represents an entry of the delivery log of a subscription.
+//
+// Fields:
+//   - DeliveryId: The unique identifier of the delivery, sent in the X-Cerberus-Delivery header.
+//   - EventType: The type of the delivered event.
+//   - Payload: The JSON body sent to the receiver.
+//   - Status: "pending", "succeeded" or "failed".
+//   - Attempts: How many times the delivery was attempted.
+//   - ResponseStatus: The HTTP status of the last attempt, omitted if no response was received.
+//   - LastError: Why the last attempt failed, omitted on success.
+//   - NextAttemptAt: When the next attempt is scheduled, omitted once the delivery is settled.
+//   - DeliveredAt: When the receiver acknowledged the delivery.
+//   - CreatedAt: When the event was queued.
+type DeliveryResponse struct {
+	DeliveryId     string          `json:"delivery_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// NewDeliveryResponse maps a delivery model to its API representation.
+//
+// Parameters:
+//   - p_delivery: A pointer to the delivery model.
+//
+// Returns:
+//   - DeliveryResponse: The API view of the delivery.
+func NewDeliveryResponse(p_delivery *models.WebhookDelivery) DeliveryResponse {
+	res := DeliveryResponse{
+		DeliveryId:     p_delivery.ID.String(),
+		EventType:      p_delivery.EventType,
+		Payload:        json.RawMessage(p_delivery.Payload),
+		Status:         p_delivery.Status,
+		Attempts:       p_delivery.Attempts,
+		ResponseStatus: p_delivery.ResponseStatus,
+		LastError:      p_delivery.LastError,
+		DeliveredAt:    p_delivery.DeliveredAt,
+		CreatedAt:      p_delivery.CreatedAt,
+	}
+	if p_delivery.Status == models.DeliveryPending {
+		res.NextAttemptAt = &p_delivery.NextAttemptAt
+	}
+
+	return res
+}
+
+// ListDeliveriesResponse represents a page of the delivery log of a subscription.
+//
+// Fields:
+//   - Deliveries: The deliveries of the page, newest first.
+//   - Page: The 1-based page number.
+//   - PageSize: The maximum number of deliveries per page.
+//   - Total: The total number of matching deliveries.
+type ListDeliveriesResponse struct {
+	Deliveries []DeliveryResponse `json:"deliveries"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"page_size"`
+	Total      int64              `json:"total"`
+}
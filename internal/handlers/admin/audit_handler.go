@@ -0,0 +1,51 @@
+package admin_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/audit_dto"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/pagination"
+	"cerberus/internal/tools/validator"
+	"encoding/json"
+	"net/http"
+)
+
+// CreateListAuditEventsHandler returns an HTTP handler that lists the security audit log
+// page by page, newest first.
+//
+// Query parameters:
+//   - page, page_size: The pagination parameters (see pagination.FromRequest).
+//   - type: Only return events of this type (e.g., "login_failure").
+//   - actor_id: Only return events performed by this user.
+//   - target_id: Only return events affecting this user.
+//   - outcome: Either "success" or "failure".
+//   - since, until: Only return events in this time range (RFC 3339).
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes a ListAuditEventsResponse as JSON, or
+//     400 for malformed filters.
+func CreateListAuditEventsHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter, err := services.ParseAuditFilter(r.URL.Query())
+		if err != nil {
+			validator.WriteError(w, err)
+			return
+		}
+
+		page, size := pagination.FromRequest(r)
+		events, total, err := services.ListAuditEvents(p_db.Postgres, filter, page, size)
+		if err != nil {
+			logger.Log("Failed to list audit events - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to list audit events", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(audit_dto.NewListAuditEventsResponse(events, page, size, total))
+	})
+}
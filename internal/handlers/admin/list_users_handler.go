@@ -0,0 +1,52 @@
+package admin_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/admin_dto"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/pagination"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// CreateListUsersHandler returns an HTTP handler that lists users page by page.
+//
+// Query parameters:
+//   - page: The 1-based page number (default 1).
+//   - page_size: The number of users per page (default 20, max 100).
+//   - q: An optional case-insensitive search on the email and the name.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes a ListUsersResponse as JSON.
+func CreateListUsersHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, size := pagination.FromRequest(r)
+		search := strings.TrimSpace(r.URL.Query().Get("q"))
+
+		users, total, err := services.ListUsers(p_db.Postgres, page, size, search)
+		if err != nil {
+			logger.Log("Failed to list users - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to list users", http.StatusInternalServerError)
+			return
+		}
+
+		res := admin_dto.ListUsersResponse{
+			Users:    make([]admin_dto.UserSummary, 0, len(users)),
+			Page:     page,
+			PageSize: size,
+			Total:    total,
+		}
+		for i := range users {
+			res.Users = append(res.Users, admin_dto.NewUserSummary(&users[i]))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	})
+}
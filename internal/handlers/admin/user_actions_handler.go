@@ -0,0 +1,99 @@
+package admin_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/admin_dto"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
+	"encoding/json"
+	"net/http"
+)
+
+// CreateChangeStatusHandler returns an HTTP handler that changes the status of the user
+// identified by the "id" path value. Moving a user out of the active status also revokes
+// all of their sessions.
+//
+// The request body is a ChangeStatusRequest. When p_status is set (e.g., on the disable and
+// enable shortcuts) the body is optional, only carries the reason, and its status is ignored.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//   - p_status: The fixed target status of the route, or "" to read it from the body.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes the updated UserSummary as JSON, 404 for
+//     unknown users, or 409 for transitions that are not allowed.
+func CreateChangeStatusHandler(p_db *database.DataRefs, p_status string) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req admin_dto.ChangeStatusRequest
+		if p_status == "" || r.ContentLength != 0 {
+			if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+				logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+				validator.WriteError(w, err)
+				return
+			}
+		}
+
+		if p_status != "" {
+			req.Status = p_status
+		} else if req.Status == "" {
+			validator.WriteError(w, validator.ValidationErrors{
+				{Field: "status", Rule: "required", Message: "status is required"}})
+			return
+		}
+
+		usr, err := services.ChangeUserStatus(p_db, r.PathValue("id"), req.Status, req.Reason,
+			requestInfo(p_db, r))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(admin_dto.NewUserSummary(usr))
+	})
+}
+
+// CreateForceLogoutHandler returns an HTTP handler that revokes every session of the user
+// identified by the "id" path value.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes an AdminMessageResponse as JSON, or 404.
+func CreateForceLogoutHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := services.ForceLogout(p_db, r.PathValue("id"), requestInfo(p_db, r)); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(admin_dto.AdminMessageResponse{Message: "User logged out"})
+	})
+}
+
+// CreateForcePasswordResetHandler returns an HTTP handler that requires the user identified
+// by the "id" path value to change their password before logging in again.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes an AdminMessageResponse as JSON, or 404.
+func CreateForcePasswordResetHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := services.ForcePasswordReset(p_db, r.PathValue("id"), requestInfo(p_db, r)); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(admin_dto.AdminMessageResponse{Message: "Password reset required"})
+	})
+}
@@ -0,0 +1,102 @@
+package admin_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/admin_dto"
+	"cerberus/internal/middleware"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// CreateGetUserHandler returns an HTTP handler that responds with a single user, identified
+// by the "id" path value.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes a UserSummary as JSON, or 404.
+func CreateGetUserHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usr, err := services.GetUserForAdmin(p_db.Postgres, r.PathValue("id"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(admin_dto.NewUserSummary(usr))
+	})
+}
+
+// CreateDeleteUserHandler returns an HTTP handler that soft-deletes the user identified by
+// the "id" path value and revokes all of their sessions.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes an AdminDeleteUserResponse as JSON, or 404.
+func CreateDeleteUserHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		purgeAt, err := services.AdminDeleteUser(p_db, r.PathValue("id"), requestInfo(p_db, r))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(admin_dto.AdminDeleteUserResponse{
+			Message: "User deleted",
+			PurgeAt: purgeAt,
+		})
+	})
+}
+
+// region Private
+
+// writeServiceError maps an administration service error to an HTTP response.
+func writeServiceError(w http.ResponseWriter, p_err error) {
+	var vErrs validator.ValidationErrors
+
+	switch {
+	case errors.As(p_err, &vErrs):
+		validator.WriteError(w, p_err)
+		return
+
+	case errors.Is(p_err, services.ErrUserNotFound),
+		errors.Is(p_err, services.ErrWebhookNotFound),
+		errors.Is(p_err, services.ErrDeliveryNotFound):
+		http.Error(w, p_err.Error(), http.StatusNotFound)
+		return
+
+	case errors.Is(p_err, services.ErrInvalidStatus):
+		http.Error(w, p_err.Error(), http.StatusBadRequest)
+		return
+
+	case errors.Is(p_err, services.ErrInvalidStatusTransition):
+		http.Error(w, p_err.Error(), http.StatusConflict)
+		return
+	}
+
+	logger.Log("Administration operation failed - "+p_err.Error(), logger.ERROR)
+	http.Error(w, "Internal server error", http.StatusInternalServerError)
+}
+
+// requestInfo builds the audit metadata of an administration request, using the
+// authenticated administrator as the actor.
+func requestInfo(p_db *database.DataRefs, r *http.Request) services.RequestInfo {
+	info := services.NewRequestInfo(p_db.ConfigData, r)
+	if claims, ok := middleware.GetSessionClaims(r.Context()); ok {
+		info.ActorID = claims.UserID
+	}
+	return info
+}
+
+// endregion Private
@@ -0,0 +1,227 @@
+package admin_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/admin_dto"
+	"cerberus/internal/dto/webhook_dto"
+	"cerberus/internal/models"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/pagination"
+	"cerberus/internal/tools/validator"
+	"encoding/json"
+	"net/http"
+	"slices"
+)
+
+// CreateListWebhooksHandler returns an HTTP handler that lists every webhook subscription.
+// Secrets are never included.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes a ListWebhooksResponse as JSON.
+func CreateListWebhooksHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subs, err := services.ListWebhookSubscriptions(p_db.Postgres)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		res := webhook_dto.ListWebhooksResponse{Webhooks: make([]webhook_dto.WebhookResponse, 0, len(subs))}
+		for i := range subs {
+			res.Webhooks = append(res.Webhooks, webhook_dto.NewWebhookResponse(&subs[i]))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	})
+}
+
+// CreateAddWebhookHandler returns an HTTP handler that subscribes an endpoint to identity events.
+//
+// The request body is a CreateWebhookRequest. The response is the only one including the
+// signing secret, which must be stored by the caller.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes a WebhookResponse as JSON with status 201,
+//     or 400 for invalid payloads.
+func CreateAddWebhookHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req webhook_dto.CreateWebhookRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		sub, err := services.CreateWebhookSubscription(p_db.Postgres, &req)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		res := webhook_dto.NewWebhookResponse(sub)
+		res.Secret = sub.Secret
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(res)
+	})
+}
+
+// CreateGetWebhookHandler returns an HTTP handler that responds with the webhook subscription
+// identified by the "id" path value, without its secret.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes a WebhookResponse as JSON, or 404.
+func CreateGetWebhookHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sub, err := services.GetWebhookSubscription(p_db.Postgres, r.PathValue("id"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(webhook_dto.NewWebhookResponse(sub))
+	})
+}
+
+// CreateUpdateWebhookHandler returns an HTTP handler that partially updates the webhook
+// subscription identified by the "id" path value.
+//
+// The request body is an UpdateWebhookRequest. The secret is only included in the response
+// when it was rotated.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes a WebhookResponse as JSON, 400 for invalid
+//     payloads, or 404.
+func CreateUpdateWebhookHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req webhook_dto.UpdateWebhookRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		sub, err := services.UpdateWebhookSubscription(p_db.Postgres, r.PathValue("id"), &req)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		res := webhook_dto.NewWebhookResponse(sub)
+		if req.RotateSecret {
+			res.Secret = sub.Secret
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	})
+}
+
+// CreateDeleteWebhookHandler returns an HTTP handler that removes the webhook subscription
+// identified by the "id" path value, together with its delivery log.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes an AdminMessageResponse as JSON, or 404.
+func CreateDeleteWebhookHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := services.DeleteWebhookSubscription(p_db.Postgres, r.PathValue("id")); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(admin_dto.AdminMessageResponse{Message: "Webhook deleted"})
+	})
+}
+
+// CreateListDeliveriesHandler returns an HTTP handler that lists the delivery log of the
+// webhook subscription identified by the "id" path value, newest first.
+//
+// Query parameters:
+//   - page, page_size: The pagination parameters (see pagination.FromRequest).
+//   - status: Only return "pending", "succeeded" or "failed" deliveries.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes a ListDeliveriesResponse as JSON, 400 for
+//     unknown statuses, or 404.
+func CreateListDeliveriesHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := r.URL.Query().Get("status")
+		if status != "" && !slices.Contains([]string{models.DeliveryPending, models.DeliverySucceeded,
+			models.DeliveryFailed}, status) {
+			validator.WriteError(w, validator.ValidationErrors{{Field: "status", Rule: "oneof",
+				Message: "status must be one of [pending succeeded failed]"}})
+			return
+		}
+
+		page, size := pagination.FromRequest(r)
+		deliveries, total, err := services.ListWebhookDeliveries(p_db.Postgres, r.PathValue("id"), status, page, size)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		res := webhook_dto.ListDeliveriesResponse{
+			Deliveries: make([]webhook_dto.DeliveryResponse, 0, len(deliveries)),
+			Page:       page,
+			PageSize:   size,
+			Total:      total,
+		}
+		for i := range deliveries {
+			res.Deliveries = append(res.Deliveries, webhook_dto.NewDeliveryResponse(&deliveries[i]))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	})
+}
+
+// CreateRetryDeliveryHandler returns an HTTP handler that schedules the delivery identified by
+// the "delivery_id" path value, of the subscription identified by "id", for an immediate new attempt.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes an AdminMessageResponse as JSON with
+//     status 202, or 404.
+func CreateRetryDeliveryHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := services.RetryWebhookDelivery(p_db.Postgres, r.PathValue("id"), r.PathValue("delivery_id"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(admin_dto.AdminMessageResponse{Message: "Delivery scheduled"})
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"cerberus/internal/dto/auth_dto"
 	"cerberus/internal/services"
 	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
 	"encoding/json"
 	"net/http"
 )
@@ -13,13 +14,13 @@ import (
 //
 // This function returns an http.HandlerFunc that processes password change requests.
 // The handler performs the following steps:
-// 1. Decodes the JSON request body into a ChangePasswordRequest struct
+// 1. Decodes and validates the JSON request body into a ChangePasswordRequest struct
 // 2. Calls the ChangePassword service function to process the request
 // 3. Responds with a success message or an error
 //
 // Parameters:
 //   - p_db: A pointer to a database.DataRefs struct, which should contain
-//     a Postgres database connection.
+//     a Postgres database connection and the configuration data.
 //
 // Returns:
 //   - http.HandlerFunc: A handler function that can be registered with an HTTP server.
@@ -28,15 +29,13 @@ import (
 func CreateChangePwdHandler(p_db *database.DataRefs) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var req auth_dto.ChangePasswordRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			msg := "Invalid request, failed on decode body - " + err.Error()
-			logger.Log(msg, logger.ERROR)
-
-			http.Error(w, msg, http.StatusBadRequest)
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
 			return
 		}
 
-		err := services.ChangePassword(p_db.Postgres, &req)
+		err := services.ChangePassword(p_db, &req, services.NewRequestInfo(p_db.ConfigData, r))
 		if err != nil {
 			logger.Log("Failed to change password - "+err.Error(), logger.ERROR)
 			http.Error(w, err.Error(), http.StatusUnauthorized)
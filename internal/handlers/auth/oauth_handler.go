@@ -0,0 +1,151 @@
+package auth_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/session_dto"
+	"cerberus/internal/models"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/cookie"
+	"cerberus/internal/tools/logger"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// oauthCookiePath scopes the state cookie to the federated login routes.
+const oauthCookiePath string = "/auth/oauth/"
+
+// CreateOAuthStartHandler returns an HTTP handler starting a federated login with the identity
+// provider named in the "{provider}" path segment.
+//
+// The browser is redirected to the provider. The state of the login is also set in a short-lived
+// cookie, so the callback is only accepted from the browser that started the login. The
+// "use_cookies=true" query parameter starts a browser session, set in cookies on the callback.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function answering:
+//   - 302 (StatusFound): Redirection to the provider
+//   - 404 (StatusNotFound): Unknown provider
+//   - 500 (StatusInternalServerError): The provider or Redis cannot be reached
+func CreateOAuthStartHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provider := r.PathValue("provider")
+
+		authURL, state, err := services.StartOAuthLogin(p_db, r.Context(), provider,
+			r.URL.Query().Get("use_cookies") == "true")
+		if errors.Is(err, services.ErrUnknownProvider) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "Failed to start login", http.StatusInternalServerError)
+			return
+		}
+
+		cookie.SetFlow(w, p_db.ConfigData, p_db.ConfigData.GetOAuthStateCookieName(), state, oauthCookiePath,
+			p_db.ConfigData.OAuthData.GetStateDuration())
+		w.Header().Set("Cache-Control", "no-store")
+		http.Redirect(w, r, authURL, http.StatusFound)
+	})
+}
+
+// CreateOAuthCallbackHandler returns an HTTP handler completing a federated login, called by the
+// identity provider with the "code" and "state" query parameters.
+//
+// The state must match the state cookie set by the start route. The user is then resolved by
+// services.CompleteOAuthLogin and logged in like on the login route: their previous session is
+// revoked and new tokens are issued. Browser sessions get the tokens in cookies and are
+// redirected to OAUTH_SUCCESS_URL when it is configured.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function answering:
+//   - 200 (StatusOK): Successful login, with a session_dto.LoginResponse
+//   - 303 (StatusSeeOther): Successful browser login, redirected to OAUTH_SUCCESS_URL
+//   - 400 (StatusBadRequest): Unknown, expired or foreign state
+//   - 401 (StatusUnauthorized): Login refused or failed at the provider
+//   - 403 (StatusForbidden): Account not active, unverified email address or signup disabled
+//   - 404 (StatusNotFound): Unknown provider
+//   - 500 (StatusInternalServerError): Server-side error during login process
+func CreateOAuthCallbackHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cfg := p_db.ConfigData
+		query := r.URL.Query()
+		provider := r.PathValue("provider")
+		w.Header().Set("Cache-Control", "no-store")
+
+		state := query.Get("state")
+		bound, err := r.Cookie(cfg.GetOAuthStateCookieName())
+		cookie.ClearFlow(w, cfg, cfg.GetOAuthStateCookieName(), oauthCookiePath)
+		if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(bound.Value), []byte(state)) != 1 {
+			logger.Log("Federated login refused - state cookie mismatch", logger.WARN)
+			http.Error(w, services.ErrInvalidOAuthState.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if msg := query.Get("error"); msg != "" {
+			logger.Log(fmt.Sprintf("Federated login refused by %s - %s", provider, msg), logger.WARN)
+			http.Error(w, "Login refused by the provider - "+msg, http.StatusUnauthorized)
+			return
+		}
+
+		info := services.NewRequestInfo(cfg, r)
+		usr, pending, err := services.CompleteOAuthLogin(p_db, r.Context(), provider, state, query.Get("code"), info)
+		switch {
+		case err == nil:
+		case errors.Is(err, services.ErrUnknownProvider):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case errors.Is(err, services.ErrInvalidOAuthState):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case errors.Is(err, services.ErrAccountNotActive), errors.Is(err, services.ErrEmailNotVerified),
+			errors.Is(err, services.ErrSignupDisabled):
+			logger.Log("Federated login refused - "+err.Error(), logger.ERROR)
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		default:
+			http.Error(w, "Federated login failed", http.StatusUnauthorized)
+			return
+		}
+
+		writeFederatedLogin(w, r, p_db, usr, info, pending.UseCookies, cfg.OAuthData.SuccessURL)
+	})
+}
+
+// writeFederatedLogin logs in the user resolved by a federated login, like the login route:
+// their previous session is revoked and new tokens are issued. Browser sessions get the tokens
+// in cookies and are redirected to p_successURL when it is set; other clients get them as JSON.
+func writeFederatedLogin(w http.ResponseWriter, r *http.Request, p_db *database.DataRefs, p_usr *models.User,
+	p_info services.RequestInfo, p_useCookies bool, p_successURL string) {
+	services.RevokeAllSessionTokensToUser(p_db, p_usr.ID.String(), "login")
+
+	loginData, err := services.LoginUser(p_db, p_usr, p_info, services.AMRFederated)
+	if err != nil {
+		logger.Log("Failed to login user - "+err.Error(), logger.ERROR)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res := session_dto.LoginResponse{Message: fmt.Sprintf("%s logged in", p_usr.Name)}
+	if p_useCookies {
+		res.CSRFToken = cookie.SetSession(w, p_db.ConfigData, p_usr.ID.String(), loginData.AccessToken, loginData.RefreshToken)
+		if p_successURL != "" {
+			http.Redirect(w, r, p_successURL, http.StatusSeeOther)
+			return
+		}
+	} else {
+		res.Token = loginData.AccessToken
+		res.RefreshToken = loginData.RefreshToken
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
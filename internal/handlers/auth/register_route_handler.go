@@ -5,6 +5,7 @@ import (
 	"cerberus/internal/dto/auth_dto"
 	"cerberus/internal/services"
 	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
 	"encoding/json"
 	"net/http"
 )
@@ -18,20 +19,19 @@ import (
 //   - http.HandlerFunc: A handler function that processes user registration requests.
 //
 // The handler expects a JSON payload in the request body and returns a JSON response.
+// Malformed or invalid payloads are rejected with 400 and per-field error details.
 // It uses the provided database connection to perform the user registration operation.
 func CreateRegisterHandler(p_db *database.DataRefs) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Parse Request
 		var req auth_dto.RegisterRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			msg := "Invalid request, failed on decode body - " + err.Error()
-			logger.Log(msg, logger.ERROR)
-
-			http.Error(w, err.Error(), http.StatusConflict)
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
 			return
 		}
 
-		usr, err := services.RegisterUser(p_db.Postgres, &req)
+		usr, err := services.RegisterUser(p_db, &req)
 		if err != nil {
 			logger.Log(err.Error(), logger.ERROR)
 			http.Error(w, err.Error(), http.StatusConflict)
@@ -0,0 +1,211 @@
+package auth_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/auth_dto"
+	"cerberus/internal/dto/profile_dto"
+	"cerberus/internal/middleware"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/cookie"
+	"cerberus/internal/tools/logger"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// samlCookiePath scopes the relay state cookie to the SAML routes.
+const samlCookiePath string = "/auth/saml/"
+
+// CreateSAMLMetadataHandler returns an HTTP handler publishing the metadata of Cerberus as a
+// service provider of the identity provider named in the "{provider}" path segment. The
+// metadata is registered with the identity provider.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function answering:
+//   - 200 (StatusOK): The XML metadata
+//   - 404 (StatusNotFound): Unknown provider
+//   - 500 (StatusInternalServerError): The metadata cannot be encoded
+func CreateSAMLMetadataHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provider, ok := p_db.SAML[r.PathValue("provider")]
+		if !ok {
+			http.Error(w, services.ErrUnknownProvider.Error(), http.StatusNotFound)
+			return
+		}
+
+		data, err := provider.Metadata()
+		if err != nil {
+			logger.Log("Failed to encode SAML metadata - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to encode metadata", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/samlmetadata+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	})
+}
+
+// CreateSAMLStartHandler returns an HTTP handler starting a SAML login with the identity
+// provider named in the "{provider}" path segment.
+//
+// The browser is redirected to the provider with an authentication request. The relay state of
+// the login is also set in a short-lived cookie, so the response is only accepted from the
+// browser that started the login. The "use_cookies=true" query parameter starts a browser
+// session, set in cookies by the ACS.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function answering:
+//   - 302 (StatusFound): Redirection to the provider
+//   - 404 (StatusNotFound): Unknown provider
+//   - 500 (StatusInternalServerError): The provider metadata or Redis cannot be reached
+func CreateSAMLStartHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authURL, relayState, err := services.StartSAMLLogin(p_db, r.Context(), r.PathValue("provider"),
+			r.URL.Query().Get("use_cookies") == "true")
+		if errors.Is(err, services.ErrUnknownProvider) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "Failed to start login", http.StatusInternalServerError)
+			return
+		}
+
+		cookie.SetCrossSiteFlow(w, p_db.ConfigData, p_db.ConfigData.GetSAMLRequestCookieName(), relayState,
+			samlCookiePath, p_db.ConfigData.SAMLData.GetRequestDuration())
+		w.Header().Set("Cache-Control", "no-store")
+		http.Redirect(w, r, authURL, http.StatusFound)
+	})
+}
+
+// CreateSAMLLinkHandler returns an HTTP handler starting to link an identity of the SAML
+// identity provider named in the "{provider}" path segment to the account of the authenticated
+// user. It must be wrapped by the session middleware.
+//
+// The relay state is set in a short-lived cookie like on the start route, and the URL of the
+// provider is returned for the application to send the browser to. The ACS then links the
+// asserted identity instead of logging in.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function answering:
+//   - 200 (StatusOK): The URL of the provider, in a SAMLLinkResponse
+//   - 401 (StatusUnauthorized): Missing session
+//   - 404 (StatusNotFound): Unknown provider
+//   - 500 (StatusInternalServerError): The provider metadata or Redis cannot be reached
+func CreateSAMLLinkHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		authURL, relayState, err := services.StartSAMLLink(p_db, r.Context(), r.PathValue("provider"), claims.UserID)
+		if errors.Is(err, services.ErrUnknownProvider) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "Failed to start link", http.StatusInternalServerError)
+			return
+		}
+
+		cookie.SetCrossSiteFlow(w, p_db.ConfigData, p_db.ConfigData.GetSAMLRequestCookieName(), relayState,
+			samlCookiePath, p_db.ConfigData.SAMLData.GetRequestDuration())
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(auth_dto.SAMLLinkResponse{RedirectURL: authURL})
+	})
+}
+
+// CreateSAMLACSHandler returns an HTTP handler for the assertion consumer service (ACS), to
+// which the identity provider posts the "SAMLResponse" and "RelayState" form values.
+//
+// The relay state must match the cookie set by the start route. The user is then resolved by
+// services.CompleteSAMLLogin and logged in like on the login route. Browser sessions get the
+// tokens in cookies and are redirected to SAML_SUCCESS_URL when it is configured. Responses to
+// a link started with CreateSAMLLinkHandler link the identity and log nobody in; they are also
+// redirected to SAML_SUCCESS_URL when it is configured.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function answering:
+//   - 200 (StatusOK): Successful login, with a session_dto.LoginResponse, or identity linked
+//   - 303 (StatusSeeOther): Successful browser login or link, redirected to SAML_SUCCESS_URL
+//   - 400 (StatusBadRequest): Malformed form, unknown, expired or foreign relay state
+//   - 401 (StatusUnauthorized): Invalid or unsuccessful SAML response
+//   - 403 (StatusForbidden): Account not active, email address missing or outside of the provider domains, or signup disabled
+//   - 404 (StatusNotFound): Unknown provider
+//   - 409 (StatusConflict): The account of the email address must link the identity, or the
+//     identity is linked to another account
+//   - 500 (StatusInternalServerError): Server-side error during login process
+func CreateSAMLACSHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cfg := p_db.ConfigData
+		w.Header().Set("Cache-Control", "no-store")
+
+		r.Body = http.MaxBytesReader(w, r.Body, cfg.GetMaxBodyBytes())
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Invalid form", http.StatusBadRequest)
+			return
+		}
+
+		relayState := r.PostForm.Get("RelayState")
+		bound, err := r.Cookie(cfg.GetSAMLRequestCookieName())
+		cookie.ClearCrossSiteFlow(w, cfg, cfg.GetSAMLRequestCookieName(), samlCookiePath)
+		if err != nil || relayState == "" || subtle.ConstantTimeCompare([]byte(bound.Value), []byte(relayState)) != 1 {
+			logger.Log("SAML login refused - relay state cookie mismatch", logger.WARN)
+			http.Error(w, services.ErrInvalidSAMLRequest.Error(), http.StatusBadRequest)
+			return
+		}
+
+		info := services.NewRequestInfo(cfg, r)
+		usr, pending, err := services.CompleteSAMLLogin(p_db, r, r.PathValue("provider"), relayState, info)
+		switch {
+		case err == nil:
+		case errors.Is(err, services.ErrUnknownProvider):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case errors.Is(err, services.ErrInvalidSAMLRequest):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case errors.Is(err, services.ErrAccountNotActive), errors.Is(err, services.ErrEmailNotVerified),
+			errors.Is(err, services.ErrEmailDomainNotAllowed), errors.Is(err, services.ErrSignupDisabled):
+			logger.Log("SAML login refused - "+err.Error(), logger.ERROR)
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		case errors.Is(err, services.ErrIdentityLinkRequired), errors.Is(err, services.ErrIdentityAlreadyLinked):
+			logger.Log("SAML login refused - "+err.Error(), logger.WARN)
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		default:
+			http.Error(w, "SAML login failed", http.StatusUnauthorized)
+			return
+		}
+
+		if pending.LinkUserID != "" {
+			if cfg.SAMLData.SuccessURL != "" {
+				http.Redirect(w, r, cfg.SAMLData.SuccessURL, http.StatusSeeOther)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(profile_dto.ProfileMessageResponse{Message: "Identity linked"})
+			return
+		}
+
+		writeFederatedLogin(w, r, p_db, usr, info, pending.UseCookies, cfg.SAMLData.SuccessURL)
+	})
+}
@@ -0,0 +1,73 @@
+package forwardauth_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/middleware"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"net/http"
+	"strings"
+)
+
+// Headers describing the authenticated user, returned to the proxy on success.
+const (
+	HeaderUserId string = "X-Auth-User-Id"
+	HeaderEmail  string = "X-Auth-Email"
+	HeaderRoles  string = "X-Auth-Roles"
+	HeaderScope  string = "X-Auth-Scope"
+	HeaderActor  string = "X-Auth-Actor"
+)
+
+// CreateForwardAuthHandler returns an HTTP handler answering the authentication subrequests of
+// reverse proxies (Traefik ForwardAuth, Nginx auth_request, Envoy ext_authz).
+//
+// The access token is read from the "Authorization: Bearer" header, or else from the session
+// cookie, and checked with services.ValidateSession like the validate route. The proxy only
+// looks at the status code and the headers, so the body is left empty.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that answers 200 with the X-Auth-User-Id, X-Auth-Email
+//     X-Auth-Roles, X-Auth-Scope and X-Auth-Actor headers for live sessions, or 401 otherwise.
+//     The scope is only set for scoped tokens and the actor for impersonation tokens, but both
+//     are always sent so clients cannot forge them.
+func CreateForwardAuthHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tkn, _, err := middleware.RequestToken(p_db.ConfigData, r)
+		if err != nil {
+			unauthorized(w)
+			return
+		}
+
+		claims, usr, err := services.ValidateSession(p_db, tkn)
+		if err != nil {
+			logger.Log("Forward auth refused - "+err.Error(), logger.WARN)
+			unauthorized(w)
+			return
+		}
+
+		w.Header().Set(HeaderUserId, usr.ID.String())
+		w.Header().Set(HeaderEmail, usr.Email)
+		w.Header().Set(HeaderRoles, strings.Join(usr.Roles, ","))
+		w.Header().Set(HeaderScope, claims.Scope)
+		w.Header().Set(HeaderActor, "")
+		if claims.Act != nil {
+			w.Header().Set(HeaderActor, claims.Act.Subject)
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// region Private
+
+// unauthorized writes the 401 response returned to the proxy, and through it to the client.
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="cerberus"`)
+	w.Header().Set("Cache-Control", "no-store")
+	http.Error(w, "Unauthorized", http.StatusUnauthorized)
+}
+
+// endregion Private
@@ -0,0 +1,237 @@
+package grpc_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/auth_dto"
+	"cerberus/internal/dto/session_dto"
+	"cerberus/internal/middleware"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
+	cerberusv1 "cerberus/pkg/proto/cerberus/v1"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AuthServer implements the cerberus.v1.AuthService gRPC service on top of the same services
+// as the HTTP handlers. Authentication is handled by the interceptors of the middleware package,
+// registered in routes.SetupGRPCServer.
+type AuthServer struct {
+	cerberusv1.UnimplementedAuthServiceServer
+
+	db *database.DataRefs
+}
+
+// NewAuthServer creates the gRPC authentication service.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//
+// Returns:
+//   - *AuthServer: The service implementation.
+func NewAuthServer(p_db *database.DataRefs) *AuthServer {
+	return &AuthServer{db: p_db}
+}
+
+// region Public
+
+// Register creates a new account. It answers InvalidArgument for invalid payloads and
+// AlreadyExists if the email address is taken.
+func (s *AuthServer) Register(p_ctx context.Context, p_req *cerberusv1.RegisterRequest) (*cerberusv1.RegisterResponse, error) {
+	req := auth_dto.RegisterRequest{Email: p_req.GetEmail(), Name: p_req.GetName(), Password: p_req.GetPassword()}
+	if err := validator.Validate(&req); err != nil {
+		logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+		return nil, validationStatus(err)
+	}
+
+	usr, err := services.RegisterUser(s.db, &req)
+	if err != nil {
+		logger.Log(err.Error(), logger.ERROR)
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+
+	return &cerberusv1.RegisterResponse{Message: "User registered", UserId: usr.ID.String()}, nil
+}
+
+// Login authenticates a user, revokes their previous session and issues new tokens.
+// It answers Unauthenticated for invalid credentials and PermissionDenied for accounts that
+// are not active or must change their password.
+func (s *AuthServer) Login(p_ctx context.Context, p_req *cerberusv1.LoginRequest) (*cerberusv1.LoginResponse, error) {
+	req := session_dto.LoginRequest{Email: p_req.GetEmail(), Password: p_req.GetPassword()}
+	if err := validator.Validate(&req); err != nil {
+		logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+		return nil, validationStatus(err)
+	}
+
+	info := requestInfo(s.db, p_ctx)
+	usr, err := services.AuthenticateUser(s.db, &req, info)
+	if errors.Is(err, services.ErrAccountNotActive) || errors.Is(err, services.ErrPasswordResetRequired) {
+		logger.Log("Login refused - "+err.Error(), logger.ERROR)
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	} else if err != nil {
+		msg := "Invalid credentials - " + err.Error()
+		logger.Log(msg, logger.ERROR)
+		return nil, status.Error(codes.Unauthenticated, msg)
+	}
+
+	services.RevokeAllSessionTokensToUser(s.db, usr.ID.String(), "login")
+
+	loginData, err := services.LoginUser(s.db, usr, info, services.AMRPassword)
+	if err != nil {
+		logger.Log("Failed to login user - "+err.Error(), logger.ERROR)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &cerberusv1.LoginResponse{
+		Message:      fmt.Sprintf("%s logged in", usr.Name),
+		Token:        loginData.AccessToken,
+		RefreshToken: loginData.RefreshToken,
+	}, nil
+}
+
+// Refresh exchanges a refresh token for a new pair of tokens. The access token of the session
+// is read from the "authorization" metadata. Every failure answers Unauthenticated.
+func (s *AuthServer) Refresh(p_ctx context.Context, p_req *cerberusv1.RefreshRequest) (*cerberusv1.RefreshResponse, error) {
+	sTkn, ok := p_ctx.Value(middleware.JWTToken("token")).(string)
+	if !ok || sTkn == "" {
+		logger.Log("Invalid token", logger.ERROR)
+		return nil, status.Error(codes.Unauthenticated, "Invalid token")
+	}
+
+	req := session_dto.RefreshRequest{RefreshToken: p_req.GetRefreshToken()}
+	if err := validator.Validate(&req); err != nil {
+		logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+		return nil, validationStatus(err)
+	}
+
+	usrId, err := s.db.JWTGen.GetUserIDFromToken(sTkn)
+	if err != nil {
+		logger.Log("Failed to get userId - "+err.Error(), logger.ERROR)
+		return nil, status.Error(codes.Unauthenticated, "Failed to get userId")
+	}
+
+	loginData, err := services.RefreshSession(s.db, usrId, req.RefreshToken, requestInfo(s.db, p_ctx))
+	if errors.Is(err, services.ErrInvalidRefreshToken) {
+		logger.Log("Invalid refresh token", logger.ERROR)
+		return nil, status.Error(codes.Unauthenticated, "Invalid or expired refresh token")
+	} else if errors.Is(err, services.ErrAccountNotActive) {
+		logger.Log("Refresh refused - "+err.Error(), logger.ERROR)
+		return nil, status.Error(codes.Unauthenticated, "Account not active")
+	} else if err != nil {
+		logger.Log("Failed to generate tokens", logger.ERROR)
+		return nil, status.Error(codes.Unauthenticated, "Failed to generate tokens")
+	}
+
+	return &cerberusv1.RefreshResponse{
+		Message:      "Refreshed tokens",
+		Token:        loginData.AccessToken,
+		RefreshToken: loginData.RefreshToken,
+	}, nil
+}
+
+// Logout ends the session validated by middleware.SessionInterceptor. Personal access tokens
+// are refused, as they are not tied to the session.
+func (s *AuthServer) Logout(p_ctx context.Context, _ *cerberusv1.LogoutRequest) (*cerberusv1.LogoutResponse, error) {
+	claims, ok := middleware.GetSessionClaims(p_ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Invalid session")
+	} else if claims.Scope != "" {
+		return nil, status.Error(codes.PermissionDenied, "A session is required")
+	}
+
+	services.LogoutUser(s.db, claims.UserID, requestInfo(s.db, p_ctx))
+	return &cerberusv1.LogoutResponse{Message: "Logged out successfully"}, nil
+}
+
+// Validate returns the claims of the session validated by middleware.SessionInterceptor.
+// Inactive, revoked or invalid tokens are rejected by the interceptor with Unauthenticated.
+func (s *AuthServer) Validate(p_ctx context.Context, _ *cerberusv1.ValidateRequest) (*cerberusv1.ValidateResponse, error) {
+	claims, ok := middleware.GetSessionClaims(p_ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Invalid session")
+	}
+
+	res := &cerberusv1.ValidateResponse{
+		Active: true,
+		UserId: claims.UserID,
+		Jti:    claims.ID,
+		Iss:    claims.Issuer,
+		Aud:    claims.Audience,
+		Scope:  claims.Scope,
+	}
+	if claims.IssuedAt != nil {
+		res.Iat = timestamppb.New(claims.IssuedAt.Time)
+	}
+	if claims.ExpiresAt != nil {
+		res.Exp = timestamppb.New(claims.ExpiresAt.Time)
+	}
+
+	return res, nil
+}
+
+// endregion Public
+
+// region Private
+
+// requestInfo extracts the audit metadata of a gRPC call, like services.NewRequestInfo does
+// for HTTP requests. The "x-forwarded-for" metadata is only trusted when TrustProxyHeaders is enabled.
+func requestInfo(p_db *database.DataRefs, p_ctx context.Context) services.RequestInfo {
+	var info services.RequestInfo
+
+	if p, ok := peer.FromContext(p_ctx); ok {
+		ip, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			ip = p.Addr.String()
+		}
+		info.IP = ip
+	}
+
+	if agents := metadata.ValueFromIncomingContext(p_ctx, "user-agent"); len(agents) > 0 {
+		info.UserAgent = agents[0]
+	}
+
+	if p_db.ConfigData.TrustProxyHeaders {
+		if fwd := metadata.ValueFromIncomingContext(p_ctx, "x-forwarded-for"); len(fwd) > 0 && fwd[0] != "" {
+			first, _, _ := strings.Cut(fwd[0], ",")
+			info.IP = strings.TrimSpace(first)
+		}
+	}
+
+	return info
+}
+
+// validationStatus maps a validation error to an InvalidArgument status. The field errors are
+// attached as a BadRequest detail, the gRPC counterpart of the JSON body of validator.WriteError.
+func validationStatus(p_err error) error {
+	var vErrs validator.ValidationErrors
+	if !errors.As(p_err, &vErrs) {
+		return status.Error(codes.InvalidArgument, p_err.Error())
+	}
+
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(vErrs))
+	for _, fe := range vErrs {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       fe.Field,
+			Description: fe.Message,
+		})
+	}
+
+	st, err := status.New(codes.InvalidArgument, "Validation failed").
+		WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, p_err.Error())
+	}
+	return st.Err()
+}
+
+// endregion Private
@@ -0,0 +1,38 @@
+package oauth2_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/services"
+	"net/http"
+)
+
+// CreateDeviceAuthorizationHandler returns an HTTP handler implementing the device
+// authorization endpoint of RFC 8628. The form-encoded request holds the client_id and the
+// space separated scope requested.
+//
+// The handler responds with:
+//   - 200 (StatusOK): The device and user codes
+//   - 400 (StatusBadRequest): Invalid request or scope
+//   - 401 (StatusUnauthorized): Unknown client (invalid_client)
+//   - 500 (StatusInternalServerError): Server-side error (server_error)
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes a DeviceAuthorizationResponse or an
+//     ErrorResponse as JSON.
+func CreateDeviceAuthorizationHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !parseForm(w, r, p_db) {
+			return
+		}
+
+		res, err := services.StartDeviceAuthorization(p_db, r.PostForm.Get("client_id"), r.PostForm.Get("scope"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeOAuthJSON(w, res)
+	})
+}
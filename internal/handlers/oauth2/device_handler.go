@@ -0,0 +1,105 @@
+package oauth2_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/oauth2_dto"
+	"cerberus/internal/middleware"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// CreateGetDeviceRequestHandler returns an HTTP handler that shows the authenticated user the
+// device authorization request of the user_code query parameter, before they decide on it.
+//
+// The handler responds with:
+//   - 200 (StatusOK): The request
+//   - 401 (StatusUnauthorized): Missing session
+//   - 404 (StatusNotFound): Unknown or expired user code
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes a DeviceRequestResponse as JSON.
+func CreateGetDeviceRequestHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := middleware.GetSessionClaims(r.Context()); !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		res, err := services.GetDeviceRequest(p_db, r.URL.Query().Get("user_code"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	})
+}
+
+// CreateDeviceDecisionHandler returns an HTTP handler through which the authenticated user
+// approves or denies a device authorization request.
+//
+// The handler responds with:
+//   - 200 (StatusOK): Decision recorded
+//   - 400 (StatusBadRequest): Invalid request body, or scopes the user may not grant
+//   - 401 (StatusUnauthorized): Missing session
+//   - 404 (StatusNotFound): Unknown or expired user code
+//   - 409 (StatusConflict): The request was already decided
+//   - 500 (StatusInternalServerError): Server-side error
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes a DeviceRequestResponse as JSON.
+func CreateDeviceDecisionHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		var req oauth2_dto.DeviceDecisionRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		info := services.NewRequestInfo(p_db.ConfigData, r)
+		info.ActorID = claims.UserID
+
+		res, err := services.DecideDeviceRequest(p_db, claims.UserID, &req, info)
+		switch {
+		case errors.Is(err, services.ErrUnknownUserCode):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+
+		case errors.Is(err, services.ErrInvalidScope):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+
+		case errors.Is(err, services.ErrDeviceRequestDecided):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+
+		case err != nil:
+			logger.Log("Failed to decide device authorization request - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to decide device authorization request", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	})
+}
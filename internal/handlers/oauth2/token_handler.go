@@ -0,0 +1,135 @@
+package oauth2_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/oauth2_dto"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// oauthErrors maps the errors of the services to the error codes of RFC 6749, RFC 8628 and
+// RFC 8693.
+var oauthErrors = []struct {
+	err  error
+	code string
+}{
+	{services.ErrInvalidRequest, "invalid_request"},
+	{services.ErrUserNotFound, "invalid_request"},
+	{services.ErrAccountNotActive, "invalid_request"},
+	{services.ErrInvalidTarget, "invalid_target"},
+	{services.ErrImpersonationDenied, "unauthorized_client"},
+	{services.ErrInvalidClient, "invalid_client"},
+	{services.ErrInvalidScope, "invalid_scope"},
+	{services.ErrInvalidGrant, "invalid_grant"},
+	{services.ErrAuthorizationPending, "authorization_pending"},
+	{services.ErrSlowDown, "slow_down"},
+	{services.ErrAccessDenied, "access_denied"},
+	{services.ErrExpiredToken, "expired_token"},
+}
+
+// CreateTokenHandler returns an HTTP handler implementing the OAuth 2.0 token endpoint. The
+// form-encoded request is dispatched on its grant_type: the device code grant
+// (services.DeviceCodeGrantType) and token exchange (services.TokenExchangeGrantType) are
+// supported.
+//
+// The handler responds with:
+//   - 200 (StatusOK): The access token
+//   - 400 (StatusBadRequest): An OAuth error, such as authorization_pending or slow_down
+//   - 401 (StatusUnauthorized): Unknown client (invalid_client)
+//   - 500 (StatusInternalServerError): Server-side error (server_error)
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes a TokenResponse or an ErrorResponse as JSON.
+func CreateTokenHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !parseForm(w, r, p_db) {
+			return
+		}
+
+		var res *oauth2_dto.TokenResponse
+		var err error
+		switch r.PostForm.Get("grant_type") {
+		case services.DeviceCodeGrantType:
+			res, err = services.PollDeviceToken(p_db, r.PostForm.Get("client_id"), r.PostForm.Get("device_code"),
+				services.NewRequestInfo(p_db.ConfigData, r))
+
+		case services.TokenExchangeGrantType:
+			res, err = services.ExchangeToken(p_db, &oauth2_dto.TokenExchangeRequest{
+				SubjectToken:       r.PostForm.Get("subject_token"),
+				SubjectTokenType:   r.PostForm.Get("subject_token_type"),
+				ActorToken:         r.PostForm.Get("actor_token"),
+				ActorTokenType:     r.PostForm.Get("actor_token_type"),
+				RequestedTokenType: r.PostForm.Get("requested_token_type"),
+				Scope:              r.PostForm.Get("scope"),
+				Audience:           r.PostForm["audience"],
+			}, services.NewRequestInfo(p_db.ConfigData, r))
+
+		default:
+			writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type", "")
+			return
+		}
+
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeOAuthJSON(w, res)
+	})
+}
+
+// region Private
+
+// parseForm reads the form-encoded body of an OAuth request, answering invalid_request when it
+// cannot.
+func parseForm(w http.ResponseWriter, r *http.Request, p_db *database.DataRefs) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, p_db.ConfigData.GetMaxBodyBytes())
+	if err := r.ParseForm(); err != nil {
+		logger.Log("Invalid OAuth request - "+err.Error(), logger.ERROR)
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "the body must be form-encoded")
+		return false
+	}
+	return true
+}
+
+// writeServiceError answers an OAuth request with the error code matching a service error.
+func writeServiceError(w http.ResponseWriter, p_err error) {
+	for _, e := range oauthErrors {
+		if !errors.Is(p_err, e.err) {
+			continue
+		}
+
+		status := http.StatusBadRequest
+		if e.code == "invalid_client" {
+			status = http.StatusUnauthorized
+		}
+		writeOAuthError(w, status, e.code, p_err.Error())
+		return
+	}
+
+	logger.Log("OAuth request failed - "+p_err.Error(), logger.ERROR)
+	writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
+}
+
+// writeOAuthError writes an OAuth error response.
+func writeOAuthError(w http.ResponseWriter, p_status int, p_code string, p_description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(p_status)
+	json.NewEncoder(w).Encode(oauth2_dto.ErrorResponse{Error: p_code, ErrorDescription: p_description})
+}
+
+// writeOAuthJSON writes a successful OAuth response, never to be cached.
+func writeOAuthJSON(w http.ResponseWriter, p_res any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(p_res)
+}
+
+// endregion Private
@@ -0,0 +1,120 @@
+package profile_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/profile_dto"
+	"cerberus/internal/middleware"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// CreateChangeEmailHandler returns an HTTP handler that starts an email change for the
+// authenticated user. A verification token is sent to the new address; the email is not
+// switched until the token is confirmed with CreateConfirmEmailHandler.
+//
+// The handler responds with:
+//   - 202 (StatusAccepted): Verification email sent
+//   - 400 (StatusBadRequest): Invalid request body
+//   - 401 (StatusUnauthorized): Missing session or wrong password
+//   - 409 (StatusConflict): The new address is already in use
+//   - 500 (StatusInternalServerError): Server-side error
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that processes email change requests.
+func CreateChangeEmailHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		var req profile_dto.ChangeEmailRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		err := services.RequestEmailChange(p_db, claims.UserID, &req, requestInfo(p_db, r, claims.UserID))
+		switch {
+		case errors.Is(err, services.ErrInvalidPassword):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+
+		case errors.Is(err, services.ErrEmailTaken):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+
+		case err != nil:
+			logger.Log("Failed to request email change - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to request email change", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(profile_dto.ProfileMessageResponse{
+			Message: "Verification email sent to the new address",
+		})
+	})
+}
+
+// CreateConfirmEmailHandler returns an HTTP handler that confirms a pending email change
+// of the authenticated user and responds with the updated profile.
+//
+// The handler responds with:
+//   - 200 (StatusOK): Email changed
+//   - 400 (StatusBadRequest): Invalid request body or unknown/expired token
+//   - 401 (StatusUnauthorized): Missing session
+//   - 409 (StatusConflict): The new address was taken in the meantime
+//   - 500 (StatusInternalServerError): Server-side error
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that processes email change confirmations.
+func CreateConfirmEmailHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		var req profile_dto.ConfirmEmailRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		usr, err := services.ConfirmEmailChange(p_db, claims.UserID, req.Token, requestInfo(p_db, r, claims.UserID))
+		switch {
+		case errors.Is(err, services.ErrInvalidEmailChange):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+
+		case errors.Is(err, services.ErrEmailTaken):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+
+		case err != nil:
+			logger.Log("Failed to confirm email change - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to confirm email change", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(profile_dto.NewProfileResponse(usr))
+	})
+}
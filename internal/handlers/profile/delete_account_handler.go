@@ -0,0 +1,65 @@
+package profile_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/profile_dto"
+	"cerberus/internal/middleware"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// CreateDeleteAccountHandler returns an HTTP handler that deletes the account of the
+// authenticated user after re-confirming their password. Accounts without a local password
+// send no password: the recent authentication required by the route is enough.
+//
+// The account is soft-deleted, every session is revoked and the data is permanently
+// removed once the configured grace period is over.
+//
+// The handler responds with:
+//   - 200 (StatusOK): Account deleted
+//   - 400 (StatusBadRequest): Invalid request body
+//   - 401 (StatusUnauthorized): Missing session or wrong password
+//   - 500 (StatusInternalServerError): Server-side error
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that processes account deletion requests.
+func CreateDeleteAccountHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		var req profile_dto.DeleteAccountRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		purgeAt, err := services.DeleteAccount(p_db, claims.UserID, req.Password, requestInfo(p_db, r, claims.UserID))
+		if errors.Is(err, services.ErrInvalidPassword) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		} else if err != nil {
+			logger.Log("Failed to delete account - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to delete account", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(profile_dto.DeleteAccountResponse{
+			Message: "Account deleted",
+			PurgeAt: purgeAt,
+		})
+	})
+}
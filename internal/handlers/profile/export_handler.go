@@ -0,0 +1,42 @@
+package profile_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/middleware"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// CreateExportHandler returns an HTTP handler that exports every piece of data stored
+// about the authenticated user as a downloadable JSON document.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes an ExportResponse as JSON.
+func CreateExportHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		res, err := services.ExportUserData(p_db, claims.UserID)
+		if err != nil {
+			logger.Log("Failed to export user data - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to export user data", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf("attachment; filename=\"cerberus-export-%s.json\"", claims.UserID))
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	})
+}
@@ -0,0 +1,57 @@
+package profile_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/profile_dto"
+	"cerberus/internal/middleware"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"encoding/json"
+	"net/http"
+)
+
+// CreateGetProfileHandler returns an HTTP handler that responds with the profile of the
+// authenticated user.
+//
+// The route must be protected by middleware.SessionMiddleware, which provides the session claims.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes a ProfileResponse as JSON.
+func CreateGetProfileHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		usr, err := services.GetUserById(p_db.Postgres, claims.UserID)
+		if err != nil {
+			logger.Log("User not found - "+err.Error(), logger.ERROR)
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(profile_dto.NewProfileResponse(usr))
+	})
+}
+
+// region Private
+
+// requestInfo builds the audit metadata of a self-service request, using the
+// authenticated user as the actor, or the admin impersonating them.
+func requestInfo(p_db *database.DataRefs, r *http.Request, p_usrId string) services.RequestInfo {
+	info := services.NewRequestInfo(p_db.ConfigData, r)
+	info.ActorID = p_usrId
+	if claims, ok := middleware.GetSessionClaims(r.Context()); ok && claims.Act != nil {
+		info.ActorID = claims.Act.Subject
+	}
+	return info
+}
+
+// endregion Private
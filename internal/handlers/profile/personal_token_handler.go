@@ -0,0 +1,150 @@
+package profile_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/profile_dto"
+	"cerberus/internal/middleware"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// CreateListPersonalTokensHandler returns an HTTP handler that lists the personal access
+// tokens of the authenticated user. The tokens themselves are never included.
+//
+// The handler responds with:
+//   - 200 (StatusOK): The tokens
+//   - 401 (StatusUnauthorized): Missing session
+//   - 500 (StatusInternalServerError): Server-side error
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes a ListPersonalTokensResponse as JSON.
+func CreateListPersonalTokensHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		tkns, err := services.ListPersonalTokens(p_db.Postgres, claims.UserID)
+		if err != nil {
+			logger.Log("Failed to list personal access tokens - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to list personal access tokens", http.StatusInternalServerError)
+			return
+		}
+
+		res := profile_dto.ListPersonalTokensResponse{Tokens: make([]profile_dto.PersonalTokenResponse, 0, len(tkns))}
+		for i := range tkns {
+			res.Tokens = append(res.Tokens, profile_dto.NewPersonalTokenResponse(&tkns[i]))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	})
+}
+
+// CreateAddPersonalTokenHandler returns an HTTP handler that creates a personal access token
+// for the authenticated user. The response is the only one including the token, which must
+// be stored by the caller.
+//
+// The handler responds with:
+//   - 201 (StatusCreated): Token created
+//   - 400 (StatusBadRequest): Invalid request body, scopes or expiry
+//   - 401 (StatusUnauthorized): Missing session
+//   - 409 (StatusConflict): Name already used, or too many tokens
+//   - 500 (StatusInternalServerError): Server-side error
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes a PersonalTokenResponse as JSON.
+func CreateAddPersonalTokenHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		var req profile_dto.CreatePersonalTokenRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		tkn, raw, err := services.CreatePersonalToken(p_db, claims.UserID, &req, requestInfo(p_db, r, claims.UserID))
+		var vErrs validator.ValidationErrors
+		switch {
+		case errors.As(err, &vErrs):
+			validator.WriteError(w, err)
+			return
+
+		case errors.Is(err, services.ErrPersonalTokenNameTaken), errors.Is(err, services.ErrPersonalTokenLimit):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+
+		case err != nil:
+			logger.Log("Failed to create personal access token - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to create personal access token", http.StatusInternalServerError)
+			return
+		}
+
+		res := profile_dto.NewPersonalTokenResponse(tkn)
+		res.Token = raw
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(res)
+	})
+}
+
+// CreateRevokePersonalTokenHandler returns an HTTP handler that revokes a personal access
+// token of the authenticated user.
+//
+// The handler responds with:
+//   - 200 (StatusOK): Token revoked
+//   - 401 (StatusUnauthorized): Missing session
+//   - 404 (StatusNotFound): The user owns no such token
+//   - 500 (StatusInternalServerError): Server-side error
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that processes token revocations.
+func CreateRevokePersonalTokenHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		err := services.RevokePersonalToken(p_db, claims.UserID, r.PathValue("id"), requestInfo(p_db, r, claims.UserID))
+		switch {
+		case errors.Is(err, services.ErrPersonalTokenNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+
+		case err != nil:
+			logger.Log("Failed to revoke personal access token - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to revoke personal access token", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(profile_dto.ProfileMessageResponse{Message: "Personal access token revoked"})
+	})
+}
@@ -0,0 +1,154 @@
+package profile_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/profile_dto"
+	"cerberus/internal/middleware"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// CreateChangePhoneHandler returns an HTTP handler that starts a phone number change for the
+// authenticated user. A verification code is sent to the new number by SMS; the number is not
+// saved until the code is confirmed with CreateConfirmPhoneHandler.
+//
+// The handler responds with:
+//   - 202 (StatusAccepted): Verification code sent
+//   - 400 (StatusBadRequest): Invalid request body or SMS not configured
+//   - 401 (StatusUnauthorized): Missing session
+//   - 429 (StatusTooManyRequests): A code was sent less than 30 seconds ago
+//   - 500 (StatusInternalServerError): Server-side error
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that processes phone change requests.
+func CreateChangePhoneHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		var req profile_dto.ChangePhoneRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		err := services.RequestPhoneVerification(p_db, claims.UserID, req.Phone, requestInfo(p_db, r, claims.UserID))
+		switch {
+		case errors.Is(err, services.ErrOTPChannelUnavailable):
+			http.Error(w, "SMS not available", http.StatusBadRequest)
+			return
+
+		case errors.Is(err, services.ErrOTPResendTooSoon), errors.Is(err, services.ErrOTPLocked):
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+
+		case err != nil:
+			logger.Log("Failed to request phone change - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to request phone change", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(profile_dto.ProfileMessageResponse{
+			Message: "Verification code sent to the new number",
+		})
+	})
+}
+
+// CreateConfirmPhoneHandler returns an HTTP handler that confirms a pending phone number of
+// the authenticated user and responds with the updated profile.
+//
+// The handler responds with:
+//   - 200 (StatusOK): Phone number saved
+//   - 400 (StatusBadRequest): Invalid request body
+//   - 401 (StatusUnauthorized): Missing session, or wrong, unknown or expired code
+//   - 429 (StatusTooManyRequests): Too many attempts, the code was discarded
+//   - 500 (StatusInternalServerError): Server-side error
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that processes phone confirmations.
+func CreateConfirmPhoneHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		var req profile_dto.ConfirmPhoneRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		usr, err := services.ConfirmPhone(p_db, claims.UserID, req.Code, requestInfo(p_db, r, claims.UserID))
+		switch {
+		case errors.Is(err, services.ErrOTPAttemptsExceeded), errors.Is(err, services.ErrOTPLocked):
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+
+		case errors.Is(err, services.ErrInvalidOTP):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+
+		case err != nil:
+			logger.Log("Failed to confirm phone - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to confirm phone", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(profile_dto.NewProfileResponse(usr))
+	})
+}
+
+// CreateRemovePhoneHandler returns an HTTP handler that removes the phone number of the
+// authenticated user and responds with the updated profile.
+//
+// The handler responds with:
+//   - 200 (StatusOK): Phone number removed
+//   - 401 (StatusUnauthorized): Missing session
+//   - 500 (StatusInternalServerError): Server-side error
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that processes phone removals.
+func CreateRemovePhoneHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		usr, err := services.RemovePhone(p_db, claims.UserID, requestInfo(p_db, r, claims.UserID))
+		if err != nil {
+			logger.Log("Failed to remove phone - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to remove phone", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(profile_dto.NewProfileResponse(usr))
+	})
+}
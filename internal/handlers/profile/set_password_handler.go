@@ -0,0 +1,66 @@
+package profile_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/profile_dto"
+	"cerberus/internal/middleware"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// CreateSetPasswordHandler returns an HTTP handler that replaces the password of the
+// authenticated user. The current password is not asked for; the route requires a recent
+// authentication instead (see RequireRecentAuth).
+//
+// The handler responds with:
+//   - 200 (StatusOK): Password changed
+//   - 400 (StatusBadRequest): Invalid request body, or the new password is the current one
+//   - 401 (StatusUnauthorized): Missing session or no recent authentication
+//   - 409 (StatusConflict): The account has no local password
+//   - 500 (StatusInternalServerError): Server-side error
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that processes password changes.
+func CreateSetPasswordHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		var req profile_dto.SetPasswordRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		err := services.SetPassword(p_db, claims.UserID, &req, requestInfo(p_db, r, claims.UserID))
+		switch {
+		case errors.Is(err, services.ErrPasswordUnchanged):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+
+		case errors.Is(err, services.ErrNoLocalPassword):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+
+		case err != nil:
+			logger.Log("Failed to change password - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to change password", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(profile_dto.ProfileMessageResponse{Message: "Password changed"})
+	})
+}
@@ -0,0 +1,58 @@
+package profile_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/profile_dto"
+	"cerberus/internal/middleware"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// CreateUpdateProfileHandler returns an HTTP handler that partially updates the profile of
+// the authenticated user (name and attributes) and responds with the updated profile.
+//
+// The handler responds with:
+//   - 200 (StatusOK): Profile updated
+//   - 400 (StatusBadRequest): Invalid request body or profile values
+//   - 401 (StatusUnauthorized): Missing session
+//   - 500 (StatusInternalServerError): Server-side error during the update
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that processes profile update requests.
+func CreateUpdateProfileHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		var req profile_dto.UpdateProfileRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		usr, err := services.UpdateProfile(p_db.Postgres, claims.UserID, &req)
+		if errors.Is(err, services.ErrInvalidProfile) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if err != nil {
+			logger.Log("Failed to update profile - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to update profile", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(profile_dto.NewProfileResponse(usr))
+	})
+}
@@ -4,8 +4,11 @@ import (
 	"cerberus/internal/database"
 	"cerberus/internal/dto/session_dto"
 	"cerberus/internal/services"
+	"cerberus/internal/tools/cookie"
 	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -19,6 +22,9 @@ import (
 // 4. Generates new JWT and refresh tokens for the user.
 // 5. Responds with the new tokens and a success message.
 //
+// When the request sets "use_cookies", the tokens are set in HttpOnly cookies instead, and the
+// response holds the CSRF token that the browser must send on state-changing requests.
+//
 // Parameters:
 //   - p_db: A pointer to the DataRefs structure containing database and configuration references.
 //
@@ -27,22 +33,27 @@ import (
 //
 // The handler responds with different HTTP status codes based on the outcome:
 //   - 201 (StatusCreated): Successful login
-//   - 409 (StatusConflict): Invalid request body
+//   - 400 (StatusBadRequest): Invalid request body
+//   - 413 (StatusRequestEntityTooLarge): Request body exceeds the configured limit
 //   - 401 (StatusUnauthorized): Invalid credentials
+//   - 403 (StatusForbidden): Account not active or password reset required
 //   - 500 (StatusInternalServerError): Server-side error during login process
 func CreateLoginHandler(p_db *database.DataRefs) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var req session_dto.LoginRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			msg := "Invalid request, failed on decode body - " + err.Error()
-			logger.Log(msg, logger.ERROR)
-
-			http.Error(w, err.Error(), http.StatusBadRequest)
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
 			return
 		}
 
-		usr, err := services.AuthenticateUser(p_db.Postgres, &req)
-		if err != nil {
+		info := services.NewRequestInfo(p_db.ConfigData, r)
+		usr, err := services.AuthenticateUser(p_db, &req, info)
+		if errors.Is(err, services.ErrAccountNotActive) || errors.Is(err, services.ErrPasswordResetRequired) {
+			logger.Log("Login refused - "+err.Error(), logger.ERROR)
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		} else if err != nil {
 			msg := "Invalid credentials - " + err.Error()
 			logger.Log(msg, logger.ERROR)
 
@@ -50,19 +61,21 @@ func CreateLoginHandler(p_db *database.DataRefs) http.HandlerFunc {
 			return
 		}
 
-		services.RevokeAllSessionTokensToUser(p_db.Redis, usr.ID.String())
+		services.RevokeAllSessionTokensToUser(p_db, usr.ID.String(), "login")
 
-		loginData, err := services.LoginUser(p_db, usr)
+		loginData, err := services.LoginUser(p_db, usr, info, services.AMRPassword)
 		if err != nil {
 			logger.Log("Failed to login user - "+err.Error(), logger.ERROR)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		res := session_dto.LoginResponse{
-			Message:      fmt.Sprintf("%s logged in", usr.Name),
-			Token:        loginData.AccessToken,
-			RefreshToken: loginData.RefreshToken,
+		res := session_dto.LoginResponse{Message: fmt.Sprintf("%s logged in", usr.Name)}
+		if req.UseCookies {
+			res.CSRFToken = cookie.SetSession(w, p_db.ConfigData, usr.ID.String(), loginData.AccessToken, loginData.RefreshToken)
+		} else {
+			res.Token = loginData.AccessToken
+			res.RefreshToken = loginData.RefreshToken
 		}
 
 		w.Header().Set("Content-Type", "application/json")
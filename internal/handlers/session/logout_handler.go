@@ -5,6 +5,7 @@ import (
 	"cerberus/internal/dto/session_dto"
 	"cerberus/internal/middleware"
 	"cerberus/internal/services"
+	"cerberus/internal/tools/cookie"
 	"cerberus/internal/tools/logger"
 	"encoding/json"
 	"net/http"
@@ -12,7 +13,8 @@ import (
 
 // CreateLogoutHandler returns an HTTP handler function for processing logout requests.
 // It validates the user's token, ensures the token is active, revokes all session tokens
-// for the user, and returns a success response upon successful logout.
+// for the user, and returns a success response upon successful logout. The cookies of browser
+// sessions are cleared.
 //
 // Parameters:
 //   - p_db: A pointer to the database references (`database.DataRefs`) containing
@@ -58,7 +60,11 @@ func CreateLogoutHandler(p_db *database.DataRefs) http.HandlerFunc {
 			return
 		}
 
-		services.RevokeAllSessionTokensToUser(p_db.Redis, usr.ID.String())
+		services.LogoutUser(p_db, usr.ID.String(), services.NewRequestInfo(p_db.ConfigData, r))
+		if middleware.GetTokenSource(r.Context()) == middleware.TokenFromCookie {
+			cookie.ClearSession(w, p_db.ConfigData)
+		}
+
 		res := session_dto.LogoutResponse{
 			Message: "Logged out successfully",
 		}
@@ -0,0 +1,138 @@
+package session_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/session_dto"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/cookie"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// magicLinkCookiePath scopes the nonce cookie to the login link routes.
+const magicLinkCookiePath string = "/session/magic-link"
+
+// CreateMagicLinkHandler creates an HTTP handler function emailing a one-time login link.
+//
+// The link is bound to the requesting browser through a nonce set in a short-lived cookie.
+// The response is the same whether the account exists or not.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//
+// Returns:
+//   - http.HandlerFunc: An HTTP handler function that processes login link requests.
+//
+// The handler responds with different HTTP status codes based on the outcome:
+//   - 202 (StatusAccepted): Request accepted, a link is sent if the account exists
+//   - 400 (StatusBadRequest): Invalid request body
+//   - 413 (StatusRequestEntityTooLarge): Request body exceeds the configured limit
+//   - 429 (StatusTooManyRequests): Too many links requested for the address or from the client
+//   - 500 (StatusInternalServerError): Failed to check the rate limits
+func CreateMagicLinkHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req session_dto.MagicLinkRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		nonce, err := services.RequestMagicLink(p_db, &req, services.NewRequestInfo(p_db.ConfigData, r))
+		if errors.Is(err, services.ErrMagicLinkRateLimited) {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		} else if err != nil {
+			logger.Log("Failed to request login link - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to request login link", http.StatusInternalServerError)
+			return
+		}
+
+		cookie.SetFlow(w, p_db.ConfigData, p_db.ConfigData.GetMagicLinkCookieName(), nonce, magicLinkCookiePath,
+			p_db.ConfigData.RedisData.GetMagicLinkDuration())
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(session_dto.MagicLinkResponse{
+			Message: "If an account exists for this address, a login link has been sent",
+		})
+	})
+}
+
+// CreateConsumeMagicLinkHandler creates an HTTP handler function logging a user in with a
+// login link.
+//
+// The request must come from the browser that requested the link, carrying its nonce cookie.
+// On success, the previous session is revoked and a new one issued like CreateLoginHandler,
+// in cookies when the link was requested with "use_cookies".
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//
+// Returns:
+//   - http.HandlerFunc: An HTTP handler function that processes login link consumption.
+//
+// The handler responds with different HTTP status codes based on the outcome:
+//   - 201 (StatusCreated): Successful login
+//   - 400 (StatusBadRequest): Invalid request body
+//   - 413 (StatusRequestEntityTooLarge): Request body exceeds the configured limit
+//   - 401 (StatusUnauthorized): Unknown, expired, consumed or foreign link
+//   - 403 (StatusForbidden): Account not active
+//   - 500 (StatusInternalServerError): Server-side error during login process
+func CreateConsumeMagicLinkHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req session_dto.ConsumeMagicLinkRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		var nonce string
+		if bound, err := r.Cookie(p_db.ConfigData.GetMagicLinkCookieName()); err == nil {
+			nonce = bound.Value
+		}
+
+		info := services.NewRequestInfo(p_db.ConfigData, r)
+		usr, link, err := services.ConsumeMagicLink(p_db, req.Token, nonce, info)
+		if errors.Is(err, services.ErrAccountNotActive) {
+			logger.Log("Login refused - "+err.Error(), logger.ERROR)
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		} else if errors.Is(err, services.ErrInvalidMagicLink) {
+			logger.Log("Login link refused - "+err.Error(), logger.WARN)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		} else if err != nil {
+			logger.Log("Failed to consume login link - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to consume login link", http.StatusInternalServerError)
+			return
+		}
+
+		cookie.ClearFlow(w, p_db.ConfigData, p_db.ConfigData.GetMagicLinkCookieName(), magicLinkCookiePath)
+		services.RevokeAllSessionTokensToUser(p_db, usr.ID.String(), "login")
+
+		loginData, err := services.LoginUser(p_db, usr, info, services.AMREmail)
+		if err != nil {
+			logger.Log("Failed to login user - "+err.Error(), logger.ERROR)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		res := session_dto.LoginResponse{Message: fmt.Sprintf("%s logged in", usr.Name)}
+		if link.UseCookies {
+			res.CSRFToken = cookie.SetSession(w, p_db.ConfigData, usr.ID.String(), loginData.AccessToken, loginData.RefreshToken)
+		} else {
+			res.Token = loginData.AccessToken
+			res.RefreshToken = loginData.RefreshToken
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(res)
+	})
+}
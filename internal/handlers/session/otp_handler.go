@@ -0,0 +1,135 @@
+package session_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/session_dto"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/cookie"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// CreateOTPLoginHandler creates an HTTP handler function sending a one-time login code by
+// email or SMS. The response is the same whether the account exists or not.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//
+// Returns:
+//   - http.HandlerFunc: An HTTP handler function that processes login code requests.
+//
+// The handler responds with different HTTP status codes based on the outcome:
+//   - 202 (StatusAccepted): Request accepted, a code is sent if the account exists
+//   - 400 (StatusBadRequest): Invalid request body or channel not configured
+//   - 413 (StatusRequestEntityTooLarge): Request body exceeds the configured limit
+//   - 500 (StatusInternalServerError): Failed to store or send the code
+func CreateOTPLoginHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req session_dto.OTPLoginRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		err := services.RequestLoginOTP(p_db, &req, services.NewRequestInfo(p_db.ConfigData, r))
+		if errors.Is(err, services.ErrOTPChannelUnavailable) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if err != nil {
+			logger.Log("Failed to send login code - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to send login code", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(session_dto.OTPSentResponse{
+			Message: "If an account exists for this address, a code has been sent",
+		})
+	})
+}
+
+// CreateOTPVerifyHandler creates an HTTP handler function logging a user in with a one-time
+// code. On success, the previous session is revoked and a new one issued like
+// CreateLoginHandler, in cookies when the code was requested with "use_cookies".
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//
+// Returns:
+//   - http.HandlerFunc: An HTTP handler function that processes login code verification.
+//
+// The handler responds with different HTTP status codes based on the outcome:
+//   - 201 (StatusCreated): Successful login
+//   - 400 (StatusBadRequest): Invalid request body
+//   - 413 (StatusRequestEntityTooLarge): Request body exceeds the configured limit
+//   - 401 (StatusUnauthorized): Wrong, unknown or expired code
+//   - 403 (StatusForbidden): Account not active
+//   - 429 (StatusTooManyRequests): Too many attempts, the code was discarded
+//   - 500 (StatusInternalServerError): Server-side error during login process
+func CreateOTPVerifyHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req session_dto.OTPVerifyRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		info := services.NewRequestInfo(p_db.ConfigData, r)
+		usr, data, err := services.VerifyLoginOTP(p_db, req.Email, req.Code, info)
+		if !writeOTPError(w, err) {
+			return
+		}
+
+		services.RevokeAllSessionTokensToUser(p_db, usr.ID.String(), "login")
+
+		loginData, err := services.LoginUser(p_db, usr, info, data.Methods...)
+		if err != nil {
+			logger.Log("Failed to login user - "+err.Error(), logger.ERROR)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		res := session_dto.LoginResponse{Message: fmt.Sprintf("%s logged in", usr.Name)}
+		if data.UseCookies {
+			res.CSRFToken = cookie.SetSession(w, p_db.ConfigData, usr.ID.String(), loginData.AccessToken, loginData.RefreshToken)
+		} else {
+			res.Token = loginData.AccessToken
+			res.RefreshToken = loginData.RefreshToken
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(res)
+	})
+}
+
+// writeOTPError answers a failed code verification. It returns true when there is no error
+// and the handler must go on.
+func writeOTPError(w http.ResponseWriter, p_err error) bool {
+	switch {
+	case p_err == nil:
+		return true
+
+	case errors.Is(p_err, services.ErrOTPAttemptsExceeded), errors.Is(p_err, services.ErrOTPLocked):
+		http.Error(w, p_err.Error(), http.StatusTooManyRequests)
+
+	case errors.Is(p_err, services.ErrInvalidOTP):
+		http.Error(w, p_err.Error(), http.StatusUnauthorized)
+
+	case errors.Is(p_err, services.ErrAccountNotActive):
+		logger.Log("Login refused - "+p_err.Error(), logger.ERROR)
+		http.Error(w, p_err.Error(), http.StatusForbidden)
+
+	default:
+		logger.Log("Failed to verify code - "+p_err.Error(), logger.ERROR)
+		http.Error(w, "Failed to verify code", http.StatusInternalServerError)
+	}
+	return false
+}
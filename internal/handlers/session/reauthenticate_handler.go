@@ -0,0 +1,140 @@
+package session_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/session_dto"
+	"cerberus/internal/middleware"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/cookie"
+	"cerberus/internal/tools/csrf"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// CreateStepUpChallengeHandler creates an HTTP handler function sending a reauthentication code
+// to the authenticated user, by email or SMS. The code is then proven to
+// CreateReauthenticateHandler.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//
+// Returns:
+//   - http.HandlerFunc: An HTTP handler function that processes reauthentication code requests.
+//
+// The handler responds with different HTTP status codes based on the outcome:
+//   - 202 (StatusAccepted): Code sent, the response tells where
+//   - 400 (StatusBadRequest): Invalid request body, channel not configured or no phone number
+//   - 401 (StatusUnauthorized): Missing session
+//   - 429 (StatusTooManyRequests): A code was sent less than 30 seconds ago
+//   - 500 (StatusInternalServerError): Failed to store or send the code
+func CreateStepUpChallengeHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		var req session_dto.OTPChallengeRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		info := services.NewRequestInfo(p_db.ConfigData, r)
+		info.ActorID = claims.UserID
+
+		res, err := services.RequestStepUpOTP(p_db, claims.UserID, req.Channel, info)
+		switch {
+		case errors.Is(err, services.ErrOTPChannelUnavailable):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+
+		case errors.Is(err, services.ErrOTPResendTooSoon), errors.Is(err, services.ErrOTPLocked):
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+
+		case err != nil:
+			logger.Log("Failed to send step-up code - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to send step-up code", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(res)
+	})
+}
+
+// CreateReauthenticateHandler creates an HTTP handler function proving the identity of the
+// authenticated user again, with their password or a code from CreateStepUpChallengeHandler.
+//
+// The session is kept: its authentication time is refreshed, the method is added to its
+// methods and the access token is replaced by one carrying the new claims. Browser sessions
+// receive the new access token in the session cookie, along with a new CSRF token.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//
+// Returns:
+//   - http.HandlerFunc: An HTTP handler function that processes reauthentications.
+//
+// The handler responds with different HTTP status codes based on the outcome:
+//   - 200 (StatusOK): Reauthenticated, the response holds the new claims
+//   - 400 (StatusBadRequest): Invalid request body, or neither or both of password and code
+//   - 401 (StatusUnauthorized): Missing session, wrong password, or wrong, unknown or expired code
+//   - 429 (StatusTooManyRequests): Too many attempts, the code was discarded
+//   - 500 (StatusInternalServerError): Server-side error
+func CreateReauthenticateHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		var req session_dto.ReauthenticateRequest
+		if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+			logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+			validator.WriteError(w, err)
+			return
+		}
+
+		info := services.NewRequestInfo(p_db.ConfigData, r)
+		info.ActorID = claims.UserID
+
+		data, err := services.Reauthenticate(p_db, claims.UserID, &req, info)
+		switch {
+		case errors.Is(err, services.ErrReauthenticationMethod):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+
+		case errors.Is(err, services.ErrInvalidCredentials):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+
+		case !writeOTPError(w, err):
+			return
+		}
+
+		res := session_dto.ReauthenticateResponse{
+			Message:  "Reauthenticated",
+			AuthTime: data.AuthTime,
+			AMR:      data.AMR,
+			ACR:      data.ACR,
+		}
+		if middleware.GetTokenSource(r.Context()) == middleware.TokenFromCookie {
+			res.CSRFToken = cookie.SetAccess(w, p_db.ConfigData, data.AccessToken, r.Header.Get(csrf.HeaderName))
+		} else {
+			res.Token = data.AccessToken
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	})
+}
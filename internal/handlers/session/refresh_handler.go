@@ -5,14 +5,21 @@ import (
 	"cerberus/internal/dto/session_dto"
 	"cerberus/internal/middleware"
 	"cerberus/internal/services"
+	"cerberus/internal/tools/cookie"
 	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
 	"encoding/json"
+	"errors"
 	"net/http"
 )
 
 // CreateRefreshHandler returns an HTTP handler function for refreshing session tokens.
-// It validates the provided JWT token and refresh token, revokes all existing session tokens for the user,
-// generates new tokens, and returns them in the response.
+// It validates the provided JWT token and refresh token, checks that the account is still active,
+// revokes all existing session tokens for the user, generates new tokens, and returns them in the response.
+//
+// Browser sessions send no body: the user and the refresh token are read from the refresh cookie,
+// checked by RefreshCookieMiddleware, so the session can be refreshed after the access cookie
+// expired. The new tokens are set in cookies again, with a new CSRF token.
 //
 // Parameters:
 //   - p_db: A pointer to the database.DataRefs struct containing database references.
@@ -21,54 +28,65 @@ import (
 //   - http.HandlerFunc: The HTTP handler function that processes the refresh token request.
 func CreateRefreshHandler(p_db *database.DataRefs) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		tkn := r.Context().Value(middleware.JWTToken("token"))
-
-		if tkn == nil || tkn == "" {
-			logger.Log("Invalid token - ", logger.ERROR)
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
-			return
-		}
+		cookieMode := middleware.GetTokenSource(r.Context()) == middleware.TokenFromCookie
 
+		var usr_id string
 		var req session_dto.RefreshRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			logger.Log("Invalid request, failed to decode body - "+err.Error(), logger.ERROR)
-			http.Error(w, "Invalid request format", http.StatusBadRequest)
-			return
-		}
+		if cookieMode {
+			ok := false
+			if c, err := r.Cookie(p_db.ConfigData.GetRefreshCookieName()); err == nil {
+				usr_id, req.RefreshToken, ok = cookie.ParseRefresh(c.Value)
+			}
+			if !ok || validator.Validate(&req) != nil {
+				logger.Log("Invalid refresh cookie", logger.ERROR)
+				http.Error(w, "Invalid or expired refresh token", http.StatusUnauthorized)
+				return
+			}
+		} else {
+			sTkn, _ := r.Context().Value(middleware.JWTToken("token")).(string)
+			if sTkn == "" {
+				logger.Log("Invalid token - ", logger.ERROR)
+				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				return
+			}
 
-		sTkn, ok := tkn.(string)
-		if !ok {
-			logger.Log("Invalid token", logger.ERROR)
-			http.Error(w, "Failed to parse token", int(logger.ERROR))
-			return
-		}
-
-		usr_id, err := p_db.JWTGen.GetUserIDFromToken(sTkn)
-		if err != nil {
-			logger.Log("Failed to get userId - "+err.Error(), logger.ERROR)
-			http.Error(w, "Failed to get userId", http.StatusUnauthorized)
+			if err := validator.DecodeJSONBody(w, r, &req, p_db.ConfigData.GetMaxBodyBytes()); err != nil {
+				logger.Log("Invalid request - "+err.Error(), logger.ERROR)
+				validator.WriteError(w, err)
+				return
+			}
 
+			var err error
+			usr_id, err = p_db.JWTGen.GetUserIDFromToken(sTkn)
+			if err != nil {
+				logger.Log("Failed to get userId - "+err.Error(), logger.ERROR)
+				http.Error(w, "Failed to get userId", http.StatusUnauthorized)
+				return
+			}
 		}
 
-		valid, err := services.ValidateRefreshToken(p_db, usr_id, req.RefreshToken)
-		if err != nil || !valid {
+		loginData, err := services.RefreshSession(p_db, usr_id, req.RefreshToken,
+			services.NewRequestInfo(p_db.ConfigData, r))
+		if errors.Is(err, services.ErrInvalidRefreshToken) {
 			logger.Log("Invalid refresh token", logger.ERROR)
 			http.Error(w, "Invalid or expired refresh token", http.StatusUnauthorized)
 			return
-		}
-		services.RevokeAllSessionTokensToUser(p_db.Redis, usr_id)
-
-		loginData, err := services.GenerateTokensAndSave(p_db, usr_id)
-		if err != nil {
+		} else if errors.Is(err, services.ErrAccountNotActive) {
+			logger.Log("Refresh refused - "+err.Error(), logger.ERROR)
+			http.Error(w, "Account not active", http.StatusUnauthorized)
+			return
+		} else if err != nil {
 			logger.Log("Failed to generate tokens", logger.ERROR)
 			http.Error(w, "Failed to generate tokens", http.StatusUnauthorized)
 			return
 		}
 
-		res := session_dto.RefreshResponse{
-			Message:      "Refreshed tokens",
-			Token:        loginData.AccessToken,
-			RefreshToken: loginData.RefreshToken,
+		res := session_dto.RefreshResponse{Message: "Refreshed tokens"}
+		if cookieMode {
+			res.CSRFToken = cookie.SetSession(w, p_db.ConfigData, usr_id, loginData.AccessToken, loginData.RefreshToken)
+		} else {
+			res.Token = loginData.AccessToken
+			res.RefreshToken = loginData.RefreshToken
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,54 @@
+package session_handler
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// CreateRevocationsHandler returns an HTTP handler that lists the access tokens revoked before
+// their expiry, so that services verifying tokens locally can maintain a deny list.
+//
+// Verifiers poll the handler with the "since" query parameter (RFC 3339) set to the
+// "next_since" value of the previous response, or subscribe to the revocation pub/sub channel.
+// Without "since" every revoked token that has not expired yet is returned.
+//
+// The handler responds with:
+//   - 200 (StatusOK): A RevocationsResponse
+//   - 400 (StatusBadRequest): Malformed "since" parameter
+//   - 500 (StatusInternalServerError): Server-side error
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that serves the revocation feed.
+func CreateRevocationsHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var since time.Time
+		if v := r.URL.Query().Get("since"); v != "" {
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				validator.WriteError(w, validator.ValidationErrors{{Field: "since", Rule: "rfc3339",
+					Message: "since must be an RFC 3339 timestamp"}})
+				return
+			}
+			since = t
+		}
+
+		res, err := services.ListRevocations(p_db, since)
+		if err != nil {
+			logger.Log("Failed to list revocations - "+err.Error(), logger.ERROR)
+			http.Error(w, "Failed to list revocations", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	})
+}
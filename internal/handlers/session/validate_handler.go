@@ -2,16 +2,20 @@ package session_handler
 
 import (
 	"cerberus/internal/database"
+	"cerberus/internal/dto/session_dto"
 	"cerberus/internal/middleware"
 	"cerberus/internal/services"
 	"cerberus/internal/tools/logger"
+	"encoding/json"
+	"errors"
 	"net/http"
 )
 
 // CreateValidateHandler returns an HTTP handler function that validates a JWT token from the request context.
-// It checks if the token is present, parses it as a string, and validates it using the provided JWT generator.
-// If the token is valid and active, the handler responds with a 200 OK status. Otherwise, it returns an appropriate
-// error response with details about the failure.
+// It checks if the token is present, parses it as a string, and validates it with services.ValidateSession.
+// If the token is valid and active and the account is still active, the handler responds with a 200 OK status
+// and the claims of the token as a ValidateResponse.
+// Otherwise, it returns an appropriate error response with details about the failure.
 //
 // Parameters:
 //   - p_db: A pointer to the database.DataRefs struct containing dependencies such as the JWT generator and token validation service.
@@ -35,20 +39,41 @@ func CreateValidateHandler(p_db *database.DataRefs) http.HandlerFunc {
 			return
 		}
 
-		claims, err := p_db.JWTGen.ValidateJWT(sTkn)
-		if err != nil {
-			logger.Log("Invalid token - "+err.Error(), logger.ERROR)
+		claims, _, err := services.ValidateSession(p_db, sTkn)
+		if errors.Is(err, services.ErrInvalidToken) {
 			http.Error(w, "Invalid token", http.StatusUnauthorized)
 			return
-		}
-
-		isValid, err := services.IsTokenActive(p_db, claims.UserID, sTkn)
-		if err != nil || !isValid {
-			logger.Log("Revoked token", logger.ERROR)
+		} else if errors.Is(err, services.ErrRevokedToken) {
 			http.Error(w, "Revoked token", http.StatusUnauthorized)
 			return
+		} else if err != nil {
+			http.Error(w, "Account not active", http.StatusUnauthorized)
+			return
+		}
+
+		res := session_dto.ValidateResponse{
+			Active:   true,
+			UserId:   claims.UserID,
+			TokenId:  claims.ID,
+			Issuer:   claims.Issuer,
+			Audience: claims.Audience,
+			Scope:    claims.Scope,
+			AMR:      claims.AMR,
+			ACR:      claims.ACR,
+			Act:      claims.Act,
+		}
+		if claims.AuthTime != nil {
+			res.AuthTime = &claims.AuthTime.Time
+		}
+		if claims.IssuedAt != nil {
+			res.IssuedAt = claims.IssuedAt.Time
+		}
+		if claims.ExpiresAt != nil {
+			res.ExpiresAt = claims.ExpiresAt.Time
 		}
 
+		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
 	})
 }
@@ -0,0 +1,25 @@
+package wellknown_handler
+
+import (
+	"cerberus/internal/database"
+	"encoding/json"
+	"net/http"
+)
+
+// CreateJWKSHandler returns an HTTP handler that publishes the public keys verifying the
+// access tokens as a JSON Web Key Set. The set is empty when tokens are signed with the
+// HS256 shared secret.
+//
+// Parameters:
+//   - p_db: A pointer to the database.DataRefs struct containing database references.
+//
+// Returns:
+//   - http.HandlerFunc: A handler function that writes the key set as JSON.
+func CreateJWKSHandler(p_db *database.DataRefs) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(p_db.JWTGen.JWKS())
+	})
+}
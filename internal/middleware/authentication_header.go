@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"cerberus/pkg/config"
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 )
@@ -11,31 +13,85 @@ import (
 // avoiding potential collisions or misuse of generic string types.
 type JWTToken string
 
-// AuthenticationHeaderMiddleware is an HTTP middleware that validates the "Authorization" header
-// in incoming requests. It ensures the header is present, checks for the "Bearer " prefix, and
-// extracts the token for use in subsequent handlers.
+// TokenSource tells where the access token of a request was read from. It is stored in the
+// request context under JWTToken("source").
+type TokenSource string
+
+const (
+	TokenFromHeader TokenSource = "header" // TokenFromHeader marks tokens sent in the "Authorization" header.
+	TokenFromCookie TokenSource = "cookie" // TokenFromCookie marks tokens sent in the session cookie.
+)
+
+var (
+	ErrMissingToken       = errors.New("Missing Authorization header")
+	ErrInvalidTokenFormat = errors.New("Invalid token format")
+)
+
+// AuthenticationHeaderMiddleware is an HTTP middleware that extracts the access token of
+// incoming requests for use in subsequent handlers. The token is read from the "Authorization"
+// header, which must use the "Bearer " prefix, or else from the session cookie of browser sessions.
+//
+// The token is stored in the request context under JWTToken("token"), and its TokenSource
+// under JWTToken("source").
 //
 // Parameters:
-//   - p_next: The next http.Handler in the middleware chain to be called after validation.
+//   - p_cfg: A pointer to the ConfigData structure holding the session cookie name.
 //
 // Returns:
-//   - http.Handler: A middleware function that processes the request and passes it to the next handler.
-func AuthenticationHeaderMiddleware(p_next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Missing Authorization header", http.StatusUnauthorized)
-			return
-		}
+//   - func(http.Handler) http.Handler: The middleware function.
+func AuthenticationHeaderMiddleware(p_cfg *config.ConfigData) func(http.Handler) http.Handler {
+	return func(p_next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tkn, source, err := RequestToken(p_cfg, r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			ctx := r.Context()
+			ctx = context.WithValue(ctx, JWTToken("token"), tkn)
+			ctx = context.WithValue(ctx, JWTToken("source"), source)
+
+			p_next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
 
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			http.Error(w, "Invalid token format", http.StatusUnauthorized)
-			return
+// RequestToken returns the access token of a request. The "Authorization" header takes
+// precedence; the session cookie is only read when the header is absent.
+//
+// Parameters:
+//   - p_cfg: A pointer to the ConfigData structure holding the session cookie name.
+//   - r: The incoming request.
+//
+// Returns:
+//   - string: The access token.
+//   - TokenSource: Where the token was read from.
+//   - error: ErrMissingToken if the request holds no token, ErrInvalidTokenFormat if the header
+//     does not use the "Bearer " prefix.
+func RequestToken(p_cfg *config.ConfigData, r *http.Request) (string, TokenSource, error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		tkn, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || tkn == "" {
+			return "", "", ErrInvalidTokenFormat
 		}
+		return tkn, TokenFromHeader, nil
+	}
 
-		ctx := r.Context()
-		ctx = context.WithValue(ctx, JWTToken("token"), strings.TrimPrefix(authHeader, "Bearer "))
+	if cookie, err := r.Cookie(p_cfg.GetSessionCookieName()); err == nil && cookie.Value != "" {
+		return cookie.Value, TokenFromCookie, nil
+	}
+	return "", "", ErrMissingToken
+}
 
-		p_next.ServeHTTP(w, r.WithContext(ctx))
-	})
+// GetTokenSource returns the TokenSource stored in the context by AuthenticationHeaderMiddleware.
+//
+// Parameters:
+//   - p_ctx: The request context.
+//
+// Returns:
+//   - TokenSource: Where the token of the request was read from, empty if unknown.
+func GetTokenSource(p_ctx context.Context) TokenSource {
+	source, _ := p_ctx.Value(JWTToken("source")).(TokenSource)
+	return source
 }
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"cerberus/internal/tools/csrf"
+	"cerberus/internal/tools/logger"
+	"cerberus/pkg/config"
+	"net/http"
+)
+
+// CSRFMiddleware is an HTTP middleware protecting browser sessions against cross-site request forgery.
+//
+// Requests authenticated with the session cookie must send the CSRF token of their session in the
+// csrf.HeaderName header, unless their method is safe (GET, HEAD, OPTIONS). Requests using the
+// "Authorization" header are not exposed to CSRF and pass through unchecked.
+//
+// The token is bound to the access token extracted by AuthenticationHeaderMiddleware, so it
+// must be listed before AuthenticationHeaderMiddleware so that it runs after it.
+//
+// Parameters:
+//   - p_cfg: A pointer to the ConfigData structure holding the CSRF key.
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware function.
+func CSRFMiddleware(p_cfg *config.ConfigData) func(http.Handler) http.Handler {
+	return func(p_next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				p_next.ServeHTTP(w, r)
+				return
+			}
+
+			if GetTokenSource(r.Context()) != TokenFromCookie {
+				p_next.ServeHTTP(w, r)
+				return
+			}
+
+			sTkn, _ := r.Context().Value(JWTToken("token")).(string)
+			if !csrf.Verify(p_cfg.GetCSRFSecret(), sTkn, r.Header.Get(csrf.HeaderName)) {
+				logger.Log("CSRF token mismatch", logger.WARN)
+				http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+				return
+			}
+
+			p_next.ServeHTTP(w, r)
+		})
+	}
+}
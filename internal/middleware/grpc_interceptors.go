@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"cerberus/internal/database"
+	logger "cerberus/internal/tools/logger"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// LogRequestInterceptor is the gRPC equivalent of LogRequestMiddleware and TimeRequestMiddleware.
+// It logs the called method and the address of the client, then the processing time and the
+// status code of the call.
+func LogRequestInterceptor(p_ctx context.Context, p_req interface{}, p_info *grpc.UnaryServerInfo,
+	p_handler grpc.UnaryHandler) (interface{}, error) {
+	addr := "unknown"
+	if p, ok := peer.FromContext(p_ctx); ok {
+		addr = p.Addr.String()
+	}
+	logger.Log(fmt.Sprintf("Call received: %s from %s", p_info.FullMethod, addr), logger.INFO)
+
+	var startT time.Time = time.Now()
+	res, err := p_handler(p_ctx, p_req)
+	logger.Log(fmt.Sprintf("Processed in: %s (%s)", time.Since(startT), status.Code(err)), logger.DEBUG)
+
+	return res, err
+}
+
+// AuthenticationMetadataInterceptor is the gRPC equivalent of AuthenticationHeaderMiddleware.
+//
+// For the listed methods, it requires an "authorization" metadata formatted as
+// "Bearer <token>" and stores the token in the context under JWTToken("token"). Other methods
+// are called unchanged.
+//
+// Parameters:
+//   - p_methods: The full names of the protected methods (e.g., "/cerberus.v1.AuthService/Logout").
+//
+// Returns:
+//   - grpc.UnaryServerInterceptor: The interceptor.
+func AuthenticationMetadataInterceptor(p_methods ...string) grpc.UnaryServerInterceptor {
+	return func(p_ctx context.Context, p_req interface{}, p_info *grpc.UnaryServerInfo,
+		p_handler grpc.UnaryHandler) (interface{}, error) {
+		if !slices.Contains(p_methods, p_info.FullMethod) {
+			return p_handler(p_ctx, p_req)
+		}
+
+		values := metadata.ValueFromIncomingContext(p_ctx, "authorization")
+		if len(values) == 0 || values[0] == "" {
+			return nil, status.Error(codes.Unauthenticated, "Missing authorization metadata")
+		}
+
+		tkn, ok := strings.CutPrefix(values[0], "Bearer ")
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "Invalid token format")
+		}
+
+		return p_handler(context.WithValue(p_ctx, JWTToken("token"), tkn), p_req)
+	}
+}
+
+// SessionInterceptor is the gRPC equivalent of SessionMiddleware.
+//
+// For the listed methods, it validates the token extracted by AuthenticationMetadataInterceptor,
+// checks that it is still the active token of the user and that the account is active, and
+// stores the resulting claims in the context under SessionClaims("claims"). It must therefore
+// be chained after AuthenticationMetadataInterceptor.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs struct containing the JWT generator and Redis connection.
+//   - p_methods: The full names of the methods requiring an active session.
+//
+// Returns:
+//   - grpc.UnaryServerInterceptor: The interceptor.
+func SessionInterceptor(p_db *database.DataRefs, p_methods ...string) grpc.UnaryServerInterceptor {
+	return func(p_ctx context.Context, p_req interface{}, p_info *grpc.UnaryServerInfo,
+		p_handler grpc.UnaryHandler) (interface{}, error) {
+		if !slices.Contains(p_methods, p_info.FullMethod) {
+			return p_handler(p_ctx, p_req)
+		}
+
+		sTkn, ok := p_ctx.Value(JWTToken("token")).(string)
+		if !ok || sTkn == "" {
+			logger.Log("Invalid token", logger.ERROR)
+			return nil, status.Error(codes.Unauthenticated, "Invalid token")
+		}
+
+		claims, err := checkSession(p_db, sTkn)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		return p_handler(context.WithValue(p_ctx, SessionClaims("claims"), claims), p_req)
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"cerberus/internal/tools/logger"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// RequireRecentAuth is an HTTP middleware that only lets through sessions whose user
+// authenticated recently, guarding sensitive changes against a stolen or forgotten session.
+//
+// The "auth_time" claim of the session must be at most p_maxAge old and, when methods are
+// given, its "amr" claim must hold one of them. Otherwise the request is refused with the
+// insufficient_user_authentication challenge of RFC 9470, telling the client to reauthenticate
+// through "/session/reauthenticate". It relies on the session claims stored by
+// SessionMiddleware and must therefore run after it: since route middlewares wrap the group
+// ones, it wraps the handler directly on routes of groups holding the session middleware.
+//
+// Parameters:
+//   - p_maxAge: How long ago the user may have authenticated.
+//   - p_methods: The authentication methods accepted, any of them if none is given.
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware function.
+func RequireRecentAuth(p_maxAge time.Duration, p_methods ...string) func(http.Handler) http.Handler {
+	challenge := fmt.Sprintf(`Bearer error="insufficient_user_authentication", `+
+		`error_description="A recent authentication is required", max_age=%d`, int(p_maxAge.Seconds()))
+
+	return func(p_next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := GetSessionClaims(r.Context())
+			if !ok {
+				http.Error(w, "Invalid session", http.StatusUnauthorized)
+				return
+			}
+
+			recent := claims.AuthTime != nil && time.Since(claims.AuthTime.Time) <= p_maxAge
+			allowed := len(p_methods) == 0 || slices.ContainsFunc(p_methods, func(m string) bool {
+				return slices.Contains(claims.AMR, m)
+			})
+
+			if !recent || !allowed {
+				logger.Log("Reauthentication required - "+claims.UserID, logger.WARN)
+				w.Header().Set("WWW-Authenticate", challenge)
+				http.Error(w, "Reauthentication required", http.StatusUnauthorized)
+				return
+			}
+
+			p_next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"cerberus/internal/tools/cookie"
+	"cerberus/internal/tools/csrf"
+	"cerberus/internal/tools/logger"
+	"cerberus/pkg/config"
+	"context"
+	"net/http"
+)
+
+// RefreshCookieMiddleware is an HTTP middleware authenticating the refresh requests of browser
+// sessions with the refresh cookie alone, since the access cookie may have expired already.
+//
+// Requests sending an "Authorization" header are token clients: they are handed to
+// AuthenticationHeaderMiddleware. Other requests must hold the refresh cookie and send the CSRF
+// token of their session in the csrf.HeaderName header; the refresh part of the token is
+// checked against the refresh cookie. The request is then marked with TokenFromCookie, and the
+// handler reads the session from the refresh cookie.
+//
+// Parameters:
+//   - p_cfg: A pointer to the ConfigData structure holding the cookie names and the CSRF key.
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware function.
+func RefreshCookieMiddleware(p_cfg *config.ConfigData) func(http.Handler) http.Handler {
+	return func(p_next http.Handler) http.Handler {
+		header := AuthenticationHeaderMiddleware(p_cfg)(p_next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "" {
+				header.ServeHTTP(w, r)
+				return
+			}
+
+			c, err := r.Cookie(p_cfg.GetRefreshCookieName())
+			if err != nil {
+				http.Error(w, ErrMissingToken.Error(), http.StatusUnauthorized)
+				return
+			}
+			_, refresh, ok := cookie.ParseRefresh(c.Value)
+			if !ok {
+				http.Error(w, "Invalid or expired refresh token", http.StatusUnauthorized)
+				return
+			}
+
+			if !csrf.VerifyRefresh(p_cfg.GetCSRFSecret(), refresh, r.Header.Get(csrf.HeaderName)) {
+				logger.Log("CSRF token mismatch", logger.WARN)
+				http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), JWTToken("source"), TokenFromCookie)
+			p_next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"cerberus/internal/tools/cookie"
+	"cerberus/internal/tools/csrf"
+	"cerberus/pkg/config"
+	db_config "cerberus/pkg/config/db"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// browserLogin returns the cookies a browser keeps after a cookie based login.
+func browserLogin(t *testing.T, p_cfg *config.ConfigData) map[string]*http.Cookie {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	cookie.SetSession(rec, p_cfg, "user-1", "access-token", "refresh-token")
+
+	jar := make(map[string]*http.Cookie)
+	for _, c := range rec.Result().Cookies() {
+		jar[c.Name] = c
+	}
+	return jar
+}
+
+func newRefreshRequest(p_cookies ...*http.Cookie) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, cookie.RefreshPath, nil)
+	for _, c := range p_cookies {
+		r.AddCookie(c)
+	}
+	return r
+}
+
+func TestRefreshCookieMiddlewareAfterAccessCookieExpired(t *testing.T) {
+	t.Setenv("CSRF_SECRET", "csrf-secret")
+	cfg := &config.ConfigData{RedisData: db_config.RedisConfigData{JWTDuration: "15m", RefreshJWTDuration: "168h"}}
+	jar := browserLogin(t, cfg)
+
+	access, refresh, csrfCookie := jar[cfg.GetSessionCookieName()], jar[cfg.GetRefreshCookieName()], jar[cfg.GetCSRFCookieName()]
+	if access == nil || refresh == nil || csrfCookie == nil {
+		t.Fatalf("cookies = %v, want the access, refresh and CSRF cookies", jar)
+	}
+	// The browser stayed idle: the access cookie expired, the other two are still kept.
+	if csrfCookie.MaxAge < refresh.MaxAge || access.MaxAge >= refresh.MaxAge {
+		t.Fatalf("max ages: access %d, refresh %d, CSRF %d", access.MaxAge, refresh.MaxAge, csrfCookie.MaxAge)
+	}
+
+	var reached bool
+	h := RefreshCookieMiddleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		if GetTokenSource(r.Context()) != TokenFromCookie {
+			t.Errorf("token source = %q, want %q", GetTokenSource(r.Context()), TokenFromCookie)
+		}
+		c, _ := r.Cookie(cfg.GetRefreshCookieName())
+		if usrId, tkn, ok := cookie.ParseRefresh(c.Value); !ok || usrId != "user-1" || tkn != "refresh-token" {
+			t.Errorf("refresh cookie = %q", c.Value)
+		}
+	}))
+
+	r := newRefreshRequest(refresh)
+	r.Header.Set(csrf.HeaderName, csrfCookie.Value)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	if !reached {
+		t.Fatalf("refresh refused with %d", rec.Code)
+	}
+}
+
+func TestRefreshCookieMiddlewareRefusesForgedRequests(t *testing.T) {
+	t.Setenv("CSRF_SECRET", "csrf-secret")
+	cfg := &config.ConfigData{}
+	jar := browserLogin(t, cfg)
+	refresh := jar[cfg.GetRefreshCookieName()]
+
+	tests := []struct {
+		name    string
+		cookies []*http.Cookie
+		csrf    string
+		want    int
+	}{
+		{"no cookie", nil, jar[cfg.GetCSRFCookieName()].Value, http.StatusUnauthorized},
+		{"no CSRF token", []*http.Cookie{refresh}, "", http.StatusForbidden},
+		{"access part only", []*http.Cookie{refresh}, csrf.Token([]byte("csrf-secret"), "access-token", "other"), http.StatusForbidden},
+		{"malformed cookie", []*http.Cookie{{Name: refresh.Name, Value: "refresh-token"}}, jar[cfg.GetCSRFCookieName()].Value, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := RefreshCookieMiddleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Error("forged refresh reached the handler")
+			}))
+
+			r := newRefreshRequest(tt.cookies...)
+			if tt.csrf != "" {
+				r.Header.Set(csrf.HeaderName, tt.csrf)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
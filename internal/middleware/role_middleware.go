@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/logger"
+	"fmt"
+	"net/http"
+)
+
+// RequireRoleMiddleware is an HTTP middleware that only lets through users holding the given role.
+//
+// The roles are read from the database on every request, so revoking a role takes effect
+// immediately. It relies on the session claims stored by SessionMiddleware and must
+// therefore be listed before it so that it runs after it.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs struct containing the Postgres connection.
+//   - p_role: The role required to access the route (e.g., models.RoleAdmin).
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware function.
+func RequireRoleMiddleware(p_db *database.DataRefs, p_role string) func(http.Handler) http.Handler {
+	return func(p_next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := GetSessionClaims(r.Context())
+			if !ok {
+				http.Error(w, "Invalid session", http.StatusUnauthorized)
+				return
+			}
+
+			usr, err := services.GetUserById(p_db.Postgres, claims.UserID)
+			if err != nil || !usr.HasRole(p_role) {
+				logger.Log(fmt.Sprintf("Access denied, %s role required - %s", p_role, claims.UserID), logger.WARN)
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			p_next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"cerberus/internal/tools/logger"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// RequireScopeMiddleware is an HTTP middleware limiting what scoped tokens, such as personal
+// access tokens, may do. Session tokens hold no scope and pass through unchecked.
+//
+// Scoped tokens must hold p_readScope for safe methods (GET, HEAD, OPTIONS) and p_writeScope
+// for the others. It relies on the session claims stored by SessionMiddleware and must
+// therefore be listed before it so that it runs after it.
+//
+// Parameters:
+//   - p_readScope: The scope required to read.
+//   - p_writeScope: The scope required to modify.
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware function.
+func RequireScopeMiddleware(p_readScope string, p_writeScope string) func(http.Handler) http.Handler {
+	return func(p_next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := GetSessionClaims(r.Context())
+			if !ok {
+				http.Error(w, "Invalid session", http.StatusUnauthorized)
+				return
+			}
+
+			if claims.Scope == "" {
+				p_next.ServeHTTP(w, r)
+				return
+			}
+
+			scope := p_writeScope
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				scope = p_readScope
+			}
+
+			if !slices.Contains(strings.Fields(claims.Scope), scope) {
+				logger.Log("Access denied, "+scope+" scope required - "+claims.UserID, logger.WARN)
+				w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+scope+`"`)
+				http.Error(w, "Insufficient scope", http.StatusForbidden)
+				return
+			}
+
+			p_next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SessionOnlyMiddleware is an HTTP middleware refusing scoped tokens, such as personal access
+// tokens, on routes managing the session or the credentials themselves. It relies on the
+// session claims stored by SessionMiddleware and must therefore run after it.
+//
+// Parameters:
+//   - p_next: The handler only reachable with session tokens.
+//
+// Returns:
+//   - http.Handler: The wrapped handler.
+func SessionOnlyMiddleware(p_next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := GetSessionClaims(r.Context())
+		if !ok {
+			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			return
+		}
+
+		if claims.Scope != "" {
+			logger.Log("Scoped token refused - "+claims.UserID, logger.WARN)
+			http.Error(w, "A session is required", http.StatusForbidden)
+			return
+		}
+
+		p_next.ServeHTTP(w, r)
+	})
+}
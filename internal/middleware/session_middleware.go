@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/services"
+	"cerberus/internal/tools/jwt"
+	"cerberus/internal/tools/logger"
+	"context"
+	"errors"
+	"net/http"
+)
+
+// SessionClaims is a custom type used as the context key under which the validated
+// JWT claims of the current session are stored.
+type SessionClaims string
+
+// SessionMiddleware is an HTTP middleware that requires an active session.
+//
+// It reads the token extracted by AuthenticationHeaderMiddleware, validates it, checks
+// that it is still the active token of the user and that the account is active, and stores the resulting claims in the
+// request context under SessionClaims("claims"). It must therefore be listed before
+// AuthenticationHeaderMiddleware so that it runs after it.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs struct containing the JWT generator and Redis connection.
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware function.
+func SessionMiddleware(p_db *database.DataRefs) func(http.Handler) http.Handler {
+	return func(p_next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sTkn, ok := r.Context().Value(JWTToken("token")).(string)
+			if !ok || sTkn == "" {
+				logger.Log("Invalid token", logger.ERROR)
+				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				return
+			}
+
+			claims, err := checkSession(p_db, sTkn)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), SessionClaims("claims"), claims)
+			p_next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetSessionClaims returns the claims stored in the context by SessionMiddleware.
+//
+// Parameters:
+//   - p_ctx: The request context.
+//
+// Returns:
+//   - *jwt.Claims: The claims of the current session.
+//   - bool: false if the context holds no session claims.
+func GetSessionClaims(p_ctx context.Context) (*jwt.Claims, bool) {
+	claims, ok := p_ctx.Value(SessionClaims("claims")).(*jwt.Claims)
+	return claims, ok && claims != nil
+}
+
+// region Private
+
+// checkSession validates the token of a session with services.ValidateSession. The returned
+// error holds the message sent to the client.
+func checkSession(p_db *database.DataRefs, p_tkn string) (*jwt.Claims, error) {
+	claims, _, err := services.ValidateSession(p_db, p_tkn)
+	switch {
+	case err == nil:
+		return claims, nil
+	case errors.Is(err, services.ErrInvalidToken):
+		return nil, errors.New("Invalid token")
+	case errors.Is(err, services.ErrRevokedToken):
+		return nil, errors.New("Revoked token")
+	default:
+		return nil, errors.New("Account not active")
+	}
+}
+
+// endregion Private
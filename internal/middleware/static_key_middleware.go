@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"cerberus/internal/tools/logger"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// StaticKeyMiddleware is an HTTP middleware that protects service-to-service routes with a
+// shared key, sent as "Authorization: Bearer <key>". When p_key is empty the route is
+// disabled and answers 404, rather than being left public.
+//
+// Parameters:
+//   - p_key: The expected key, or an empty string to disable the route.
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware function.
+func StaticKeyMiddleware(p_key string) func(http.Handler) http.Handler {
+	return func(p_next http.Handler) http.Handler {
+		if p_key == "" {
+			return http.NotFoundHandler()
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(key), []byte(p_key)) != 1 {
+				logger.Log("Invalid service key", logger.WARN)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			p_next.ServeHTTP(w, r)
+		})
+	}
+}
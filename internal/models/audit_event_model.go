@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	AuditLoginSuccess       string = "login_success"        // AuditLoginSuccess is recorded when a session is issued.
+	AuditLoginFailure       string = "login_failure"        // AuditLoginFailure is recorded when credentials are rejected.
+	AuditLogout             string = "logout"               // AuditLogout is recorded when a user ends their session.
+	AuditRefresh            string = "refresh"              // AuditRefresh is recorded when a session is refreshed.
+	AuditPasswordChange     string = "password_change"      // AuditPasswordChange is recorded when a user changes their password.
+	AuditEmailChangeRequest string = "email_change_request" // AuditEmailChangeRequest is recorded when an email change is requested.
+	AuditEmailChange        string = "email_change"         // AuditEmailChange is recorded when an email change is confirmed.
+	AuditAccountDeletion    string = "account_deletion"     // AuditAccountDeletion is recorded when a user deletes their account.
+	AuditAdminStatusChange  string = "admin_status_change"  // AuditAdminStatusChange is recorded when an operator changes a user status.
+	AuditAdminForceLogout   string = "admin_force_logout"   // AuditAdminForceLogout is recorded when an operator revokes the sessions of a user.
+	AuditAdminPasswordReset string = "admin_password_reset" // AuditAdminPasswordReset is recorded when an operator forces a password reset.
+	AuditAdminUserDeletion  string = "admin_user_deletion"  // AuditAdminUserDeletion is recorded when an operator deletes a user.
+	AuditIdentityLink       string = "identity_link"        // AuditIdentityLink is recorded when an external identity is linked to a user.
+	AuditMagicLinkRequest   string = "magic_link_request"   // AuditMagicLinkRequest is recorded when a login link is sent by email.
+	AuditOTPRequest         string = "otp_request"          // AuditOTPRequest is recorded when a one-time code is sent.
+	AuditStepUp             string = "step_up"              // AuditStepUp is recorded when the user of a session reauthenticates.
+	AuditPhoneChange        string = "phone_change"         // AuditPhoneChange is recorded when a user sets or removes their phone number.
+	AuditTokenCreate        string = "token_create"         // AuditTokenCreate is recorded when a user creates a personal access token.
+	AuditTokenRevoke        string = "token_revoke"         // AuditTokenRevoke is recorded when a user revokes a personal access token.
+	AuditDeviceApprove      string = "device_approve"       // AuditDeviceApprove is recorded when a user approves a device authorization request.
+	AuditDeviceDeny         string = "device_deny"          // AuditDeviceDeny is recorded when a user denies a device authorization request.
+	AuditImpersonation      string = "impersonation"        // AuditImpersonation is recorded when an admin obtains a token acting as a user.
+
+	OutcomeSuccess string = "success" // OutcomeSuccess marks an event whose operation succeeded.
+	OutcomeFailure string = "failure" // OutcomeFailure marks an event whose operation failed.
+)
+
+// AuditEvent represents an entry of the append-only security audit log.
+//
+// Fields:
+//
+//	ID: Unique identifier of the event.
+//	CreatedAt: When the event happened (automatically set).
+//	Type: The kind of event (one of the Audit* constants).
+//	ActorID: The user who performed the operation, nil for anonymous requests.
+//	TargetID: The user affected by the operation, nil if unknown.
+//	IP: The IP address of the client.
+//	UserAgent: The User-Agent header of the client.
+//	Outcome: Whether the operation succeeded (OutcomeSuccess) or failed (OutcomeFailure).
+//	Details: Additional free-form information about the event.
+//
+// Rows are never deleted, nor updated but to clear the IP address, User-Agent and details of
+// the events of purged accounts; the database rejects other statements with a trigger created
+// by the migrations.
+type AuditEvent struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time  `gorm:"autoCreateTime;index"`
+	Type      string     `gorm:"not null;index"`
+	ActorID   *uuid.UUID `gorm:"type:uuid;index"`
+	TargetID  *uuid.UUID `gorm:"type:uuid;index"`
+	IP        string     `gorm:"not null;default:''"`
+	UserAgent string     `gorm:"not null;default:''"`
+	Outcome   string     `gorm:"not null"`
+
+	Details map[string]string `gorm:"type:jsonb;serializer:json"`
+}
+
+// BeforeCreate hook to generate UUID before inserting a record
+func (e *AuditEvent) BeforeCreate(tx *gorm.DB) (err error) {
+	e.ID = uuid.New()
+	return
+}
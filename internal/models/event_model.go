@@ -0,0 +1,12 @@
+package models
+
+const (
+	EventUserRegistered      string = "user.registered"       // EventUserRegistered is emitted when a new account is created.
+	EventUserEmailVerified   string = "user.email_verified"   // EventUserEmailVerified is emitted when a user confirms a new email address.
+	EventUserPasswordChanged string = "user.password_changed" // EventUserPasswordChanged is emitted when a user changes their password.
+	EventUserDisabled        string = "user.disabled"         // EventUserDisabled is emitted when an account is disabled.
+	EventUserStatusChanged   string = "user.status_changed"   // EventUserStatusChanged is emitted on every account status change.
+	EventUserDeleted         string = "user.deleted"          // EventUserDeleted is emitted when an account is soft-deleted.
+	EventSessionCreated      string = "session.created"       // EventSessionCreated is emitted when a user logs in.
+	EventSessionRevoked      string = "session.revoked"       // EventSessionRevoked is emitted when the session tokens of a user are revoked.
+)
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Identity links an account of an external identity provider to a user.
+//
+// Fields:
+//
+//	ID: Unique identifier of the identity.
+//	UserID: The user the identity logs in as. Identities are removed when the user is deleted.
+//	Provider: The name of the provider, as configured (e.g., "google").
+//	Subject: The stable ID of the account at the provider. A provider account is linked to a single user.
+//	Email: The email address shared by the provider at the last login.
+//	LastLoginAt: When the identity was last used to log in.
+type Identity struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	User        User      `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Provider    string    `gorm:"not null;uniqueIndex:idx_identities_provider_subject"`
+	Subject     string    `gorm:"not null;uniqueIndex:idx_identities_provider_subject"`
+	Email       string
+	LastLoginAt time.Time
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// BeforeCreate hook to generate UUID before inserting a record
+func (i *Identity) BeforeCreate(tx *gorm.DB) (err error) {
+	i.ID = uuid.New()
+	return
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ScopeProfileRead  string = "profile:read"  // ScopeProfileRead grants read access to the "/me" routes.
+	ScopeProfileWrite string = "profile:write" // ScopeProfileWrite grants write access to the "/me" routes.
+	ScopeAdminRead    string = "admin:read"    // ScopeAdminRead grants read access to the administration API, to admins only.
+	ScopeAdminWrite   string = "admin:write"   // ScopeAdminWrite grants write access to the administration API, to admins only.
+)
+
+// PersonalTokenScopes lists the scopes of Cerberus itself that personal access tokens may be
+// granted.
+var PersonalTokenScopes = []string{ScopeProfileRead, ScopeProfileWrite, ScopeAdminRead, ScopeAdminWrite}
+
+// PersonalToken represents a long-lived API key created by a user for scripts and tools.
+//
+// Fields:
+//
+//	ID: Unique identifier of the token.
+//	UserID: The user the token acts as. Tokens are removed with the user.
+//	Name: The name given by the user, unique among their tokens.
+//	Prefix: The public part of the token, used to look it up and shown to identify it.
+//	Hash: The SHA-256 hash of the whole token. The token itself is never stored.
+//	Scopes: The scopes granted to the token, stored as a JSON array.
+//	ExpiresAt: When the token stops being accepted, nil if it never expires.
+//	LastUsedAt: When the token was last accepted, nil if never used.
+type PersonalToken struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_personal_tokens_user_name"`
+	User       User      `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Name       string    `gorm:"not null;uniqueIndex:idx_personal_tokens_user_name"`
+	Prefix     string    `gorm:"not null;uniqueIndex"`
+	Hash       string    `gorm:"not null"`
+	Scopes     []string  `gorm:"type:jsonb;serializer:json"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// Expired checks whether the token is past its expiry.
+func (t *PersonalToken) Expired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
+
+// BeforeCreate hook to generate UUID before inserting a record
+func (t *PersonalToken) BeforeCreate(tx *gorm.DB) (err error) {
+	t.ID = uuid.New()
+	return
+}
@@ -1,12 +1,22 @@
 package models
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+const (
+	StatusActive   string = "active"   // StatusActive is the status of an account that can log in.
+	StatusDisabled string = "disabled" // StatusDisabled is the status of an account suspended by an operator.
+	StatusLocked   string = "locked"   // StatusLocked is the status of an account temporarily locked for security reasons.
+	StatusPending  string = "pending"  // StatusPending is the status of an account awaiting activation.
+
+	RoleAdmin string = "admin" // RoleAdmin grants access to the administration API.
+)
+
 // User represents a user entity in the application.
 //
 // This struct is used for object-relational mapping (ORM) with GORM,
@@ -16,9 +26,21 @@ import (
 //
 //	ID: Unique identifier for the user (primary key in the database).
 //	Name: The user's name (cannot be null).
-//	Email: The user's email address (must be unique and cannot be null).
+//	Email: The user's canonical email address (cannot be null). Uniqueness is case-insensitive
+//	among accounts not deleted, enforced by the "idx_users_email_lower_active" index created in
+//	the database migrations.
 //	Password: The user's hashed password (cannot be null).
+//	Phone: The user's verified phone number in E.164 format, empty if none. One-time codes can
+//	be sent to it by SMS.
+//	Attributes: Free-form profile attributes, stored as a JSON object.
+//	Status: The account status (StatusActive, StatusDisabled, StatusLocked or StatusPending).
+//	StatusReason: Why the status was last changed.
+//	StatusChangedAt: When the status was last changed, nil if it never changed.
+//	Roles: The roles granted to the user (e.g., RoleAdmin), stored as a JSON array.
+//	PasswordResetRequired: Whether the user must change their password before logging in again.
 //	CreatedAt: Timestamp of when the user account was created (automatically set).
+//	DeletedAt: Timestamp of when the account was soft-deleted. Soft-deleted users are ignored
+//	by regular queries and permanently removed once the deletion grace period is over.
 //
 // GORM Tags:
 //
@@ -27,11 +49,22 @@ import (
 //	"unique": Ensures the field value is unique across all records.
 //	"autoCreateTime": Automatically sets the time when the record is created.
 type User struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	Name      string    `gorm:"not null"`
-	Email     string    `gorm:"unique;not null"`
-	Password  string    `gorm:"not null"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
+	ID        uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name      string         `gorm:"not null"`
+	Email     string         `gorm:"not null"`
+	Password  string         `gorm:"not null"`
+	Phone     string         `gorm:"not null;default:''"`
+	CreatedAt time.Time      `gorm:"autoCreateTime"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	Attributes map[string]string `gorm:"type:jsonb;serializer:json"`
+
+	Status          string `gorm:"not null;default:active"`
+	StatusReason    string `gorm:"not null;default:''"`
+	StatusChangedAt *time.Time
+
+	Roles                 []string `gorm:"type:jsonb;serializer:json"`
+	PasswordResetRequired bool     `gorm:"not null;default:false"`
 }
 
 // BeforeCreate hook to generate UUID before inserting a record
@@ -39,3 +72,8 @@ func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
 	u.ID = uuid.New()
 	return
 }
+
+// HasRole reports whether the user has been granted the given role.
+func (u *User) HasRole(p_role string) bool {
+	return slices.Contains(u.Roles, p_role)
+}
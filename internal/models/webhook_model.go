@@ -0,0 +1,99 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DeliveryPending   string = "pending"   // DeliveryPending marks a delivery waiting for its next attempt.
+	DeliverySucceeded string = "succeeded" // DeliverySucceeded marks a delivery acknowledged by the receiver.
+	DeliveryFailed    string = "failed"    // DeliveryFailed marks a delivery that exhausted its attempts.
+)
+
+// WebhookEventTypes lists every event type a subscription can listen to.
+var WebhookEventTypes = []string{
+	EventUserRegistered, EventUserEmailVerified, EventUserPasswordChanged, EventUserDisabled,
+}
+
+// WebhookSubscription represents an endpoint notified of identity events.
+//
+// Fields:
+//
+//	ID: Unique identifier of the subscription.
+//	URL: The endpoint receiving the deliveries.
+//	Secret: The key used to sign the deliveries with HMAC-SHA256.
+//	EventTypes: The event types sent to the endpoint (WebhookEventTypes values).
+//	Active: Whether new events are delivered to the endpoint.
+type WebhookSubscription struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	URL        string    `gorm:"not null"`
+	Secret     string    `gorm:"not null"`
+	EventTypes []string  `gorm:"type:jsonb;serializer:json"`
+	Active     bool      `gorm:"not null;default:true"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// Accepts checks whether the subscription listens to an event type.
+func (s *WebhookSubscription) Accepts(p_eventType string) bool {
+	return slices.Contains(s.EventTypes, p_eventType)
+}
+
+// BeforeCreate hook to generate UUID before inserting a record
+func (s *WebhookSubscription) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.New()
+	return
+}
+
+// WebhookDelivery represents one event queued for a subscription, and the log of its attempts.
+//
+// Fields:
+//
+//	ID: Unique identifier of the delivery, sent to the receiver for deduplication.
+//	SubscriptionID: The subscription the event is delivered to. Deliveries are removed with it.
+//	URL: The endpoint of the subscription when the event was queued.
+//	EventType: The type of the delivered event.
+//	Payload: The JSON body sent to the receiver.
+//	Status: DeliveryPending, DeliverySucceeded or DeliveryFailed.
+//	Attempts: How many times the delivery was attempted.
+//	NextAttemptAt: When the worker should attempt the delivery again.
+//	ResponseStatus: The HTTP status of the last attempt, 0 if no response was received.
+//	LastError: Why the last attempt failed.
+//	DeliveredAt: When the receiver acknowledged the delivery.
+type WebhookDelivery struct {
+	ID             uuid.UUID           `gorm:"type:uuid;primaryKey"`
+	SubscriptionID uuid.UUID           `gorm:"type:uuid;not null;index"`
+	Subscription   WebhookSubscription `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	URL            string              `gorm:"not null;default:''"`
+	EventType      string              `gorm:"not null"`
+	Payload        string              `gorm:"type:jsonb;not null"`
+	Status         string              `gorm:"not null;default:pending;index"`
+	Attempts       int                 `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time           `gorm:"not null;index"`
+	ResponseStatus int
+	LastError      string
+	DeliveredAt    *time.Time
+
+	CreatedAt time.Time `gorm:"autoCreateTime;index"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// Endpoint returns the URL a delivery is sent to. Deliveries queued before the URL was recorded
+// on them fall back to the current URL of their subscription.
+func (d *WebhookDelivery) Endpoint() string {
+	if d.URL != "" {
+		return d.URL
+	}
+	return d.Subscription.URL
+}
+
+// BeforeCreate hook to generate UUID before inserting a record
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) (err error) {
+	d.ID = uuid.New()
+	return
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"cerberus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditFilter holds the optional criteria used to query the audit log.
+// Empty fields are ignored.
+//
+// Fields:
+//   - Type: Only return events of this type.
+//   - ActorID: Only return events performed by this user.
+//   - TargetID: Only return events affecting this user.
+//   - SubjectID: Only return events performed by or affecting this user.
+//   - Outcome: Only return events with this outcome.
+//   - Since: Only return events created at or after this instant.
+//   - Until: Only return events created before this instant.
+type AuditFilter struct {
+	Type      string
+	ActorID   string
+	TargetID  string
+	SubjectID string
+	Outcome   string
+	Since     *time.Time
+	Until     *time.Time
+}
+
+// region Public
+
+// CreateAuditEvent appends an event to the audit log.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_event: A pointer to the event to insert.
+//
+// Returns:
+//   - error: An error if the insertion fails, or nil if successful.
+func CreateAuditEvent(p_db *gorm.DB, p_event *models.AuditEvent) error {
+	return p_db.Create(p_event).Error
+}
+
+// ListAuditEvents retrieves a page of audit events matching a filter, newest first.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_filter: The criteria the events must match.
+//   - p_offset: The number of events to skip.
+//   - p_limit: The maximum number of events to return.
+//
+// Returns:
+//   - []models.AuditEvent: The events of the requested page.
+//   - int64: The total number of events matching the filter.
+//   - error: An error object if the query fails; otherwise, nil.
+func ListAuditEvents(p_db *gorm.DB, p_filter *AuditFilter, p_offset int, p_limit int) ([]models.AuditEvent, int64, error) {
+	query := p_db.Model(&models.AuditEvent{})
+
+	if p_filter.Type != "" {
+		query = query.Where("type = ?", p_filter.Type)
+	}
+	if p_filter.ActorID != "" {
+		query = query.Where("actor_id = ?", p_filter.ActorID)
+	}
+	if p_filter.TargetID != "" {
+		query = query.Where("target_id = ?", p_filter.TargetID)
+	}
+	if p_filter.SubjectID != "" {
+		query = query.Where("actor_id = ? OR target_id = ?", p_filter.SubjectID, p_filter.SubjectID)
+	}
+	if p_filter.Outcome != "" {
+		query = query.Where("outcome = ?", p_filter.Outcome)
+	}
+	if p_filter.Since != nil {
+		query = query.Where("created_at >= ?", *p_filter.Since)
+	}
+	if p_filter.Until != nil {
+		query = query.Where("created_at < ?", *p_filter.Until)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []models.AuditEvent
+	res := query.Order("created_at DESC").Offset(p_offset).Limit(p_limit).Find(&events)
+	if res.Error != nil {
+		return nil, 0, res.Error
+	}
+
+	return events, total, nil
+}
+
+// endregion Public
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"cerberus/internal/database"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var (
+	deviceCodePrefix     string = "device_code:"      // deviceCodePrefix is the prefix used for storing device authorization requests in Redis.
+	deviceUserCodePrefix string = "device_user_code:" // deviceUserCodePrefix is the prefix used for finding device authorization requests by user code.
+
+	// devicePollScript records a poll of a device authorization request. Polls sooner than the
+	// interval of the request raise the interval and get "slow_down"; others get the status.
+	devicePollScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return "expired"
+end
+local polled = tonumber(redis.call("HGET", KEYS[1], "polled") or "0")
+local interval = tonumber(redis.call("HGET", KEYS[1], "interval"))
+redis.call("HSET", KEYS[1], "polled", ARGV[1])
+if tonumber(ARGV[1]) - polled < interval then
+	redis.call("HINCRBY", KEYS[1], "interval", ARGV[2])
+	return "slow_down"
+end
+return redis.call("HGET", KEYS[1], "status")`)
+
+	// deviceDecideScript records the decision of the user on a pending request, once.
+	deviceDecideScript = redis.NewScript(`
+if redis.call("HGET", KEYS[1], "status") ~= ARGV[3] then
+	return 0
+end
+redis.call("HSET", KEYS[1], "status", ARGV[1], "user_id", ARGV[2])
+return 1`)
+)
+
+// region Public
+
+// StoreDeviceCode stores a device authorization request in Redis, along with its user code.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_deviceKey: The hash of the device code, identifying the request.
+//   - p_userCode: The code typed by the user, in its canonical form.
+//   - p_fields: The fields of the request, such as its client and status.
+//   - p_duration: How long the request remains valid.
+//
+// Returns:
+//   - bool: false if the user code is used by another request, in which case nothing is stored.
+//   - error: An error if the storage operation fails, nil otherwise.
+func StoreDeviceCode(p_db *database.RedisPack, p_deviceKey string, p_userCode string, p_fields map[string]string,
+	p_duration time.Duration) (bool, error) {
+	ok, err := p_db.Client.SetNX(p_db.Ctx, deviceUserCodePrefix+p_userCode, p_deviceKey, p_duration).Result()
+	if err != nil || !ok {
+		return false, err
+	}
+
+	pipe := p_db.Client.TxPipeline()
+	pipe.HSet(p_db.Ctx, deviceCodePrefix+p_deviceKey, p_fields)
+	pipe.Expire(p_db.Ctx, deviceCodePrefix+p_deviceKey, p_duration)
+	_, err = pipe.Exec(p_db.Ctx)
+	return err == nil, err
+}
+
+// FindDeviceCodeByUserCode retrieves a device authorization request by its user code.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_userCode: The code typed by the user, in its canonical form.
+//
+// Returns:
+//   - string: The hash of the device code of the request.
+//   - map[string]string: The fields of the request.
+//   - error: An error if the code is unknown, expired, or the retrieval fails, nil otherwise.
+func FindDeviceCodeByUserCode(p_db *database.RedisPack, p_userCode string) (string, map[string]string, error) {
+	key, err := p_db.Client.Get(p_db.Ctx, deviceUserCodePrefix+p_userCode).Result()
+	if err != nil {
+		return "", nil, errors.New("not found")
+	}
+
+	fields, err := GetDeviceCode(p_db, key)
+	if err != nil {
+		return "", nil, err
+	}
+	return key, fields, nil
+}
+
+// GetDeviceCode retrieves a device authorization request.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_deviceKey: The hash of the device code of the request.
+//
+// Returns:
+//   - map[string]string: The fields of the request.
+//   - error: An error if the request is unknown, expired, or the retrieval fails, nil otherwise.
+func GetDeviceCode(p_db *database.RedisPack, p_deviceKey string) (map[string]string, error) {
+	fields, err := p_db.Client.HGetAll(p_db.Ctx, deviceCodePrefix+p_deviceKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("not found")
+	}
+	return fields, nil
+}
+
+// PollDeviceCode records a poll of a device authorization request by its device.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_deviceKey: The hash of the device code of the request.
+//   - p_now: The time of the poll.
+//   - p_slowDown: The seconds added to the interval of the request when polled too soon.
+//
+// Returns:
+//   - string: "expired" for unknown or expired requests, "slow_down" for polls sooner than the
+//     interval, or else the status of the request.
+//   - error: An error if the script fails, nil otherwise.
+func PollDeviceCode(p_db *database.RedisPack, p_deviceKey string, p_now time.Time, p_slowDown int) (string, error) {
+	return devicePollScript.Run(p_db.Ctx, p_db.Client, []string{deviceCodePrefix + p_deviceKey},
+		p_now.Unix(), p_slowDown).Text()
+}
+
+// DecideDeviceCode records the decision of a user on a device authorization request, if the
+// request is still in the expected status.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_deviceKey: The hash of the device code of the request.
+//   - p_status: The new status of the request.
+//   - p_usrId: The unique ID (string) of the user deciding.
+//   - p_from: The status the request must be in.
+//
+// Returns:
+//   - bool: Whether the decision was recorded by this call.
+//   - error: An error if the script fails, nil otherwise.
+func DecideDeviceCode(p_db *database.RedisPack, p_deviceKey string, p_status string, p_usrId string,
+	p_from string) (bool, error) {
+	n, err := deviceDecideScript.Run(p_db.Ctx, p_db.Client, []string{deviceCodePrefix + p_deviceKey},
+		p_status, p_usrId, p_from).Int()
+	return n == 1, err
+}
+
+// DeleteDeviceCode removes a device authorization request and its user code from Redis. Only
+// the caller that actually removed the request gets true, so concurrent polls cannot both
+// complete it.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_deviceKey: The hash of the device code of the request.
+//   - p_userCode: The user code of the request.
+//
+// Returns:
+//   - bool: Whether the request was removed by this call.
+//   - error: An error if the delete operation fails, nil otherwise.
+func DeleteDeviceCode(p_db *database.RedisPack, p_deviceKey string, p_userCode string) (bool, error) {
+	n, err := p_db.Client.Del(p_db.Ctx, deviceCodePrefix+p_deviceKey).Result()
+	if err != nil {
+		return false, err
+	}
+
+	p_db.Client.Del(p_db.Ctx, deviceUserCodePrefix+p_userCode)
+	return n == 1, nil
+}
+
+// endregion Public
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"cerberus/internal/database"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// region Public
+
+// AppendStreamEvent appends an entry to a Redis Stream with an auto-generated ID, trimming
+// the stream to approximately p_maxLen entries.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_stream: The key of the stream.
+//   - p_maxLen: The approximate number of entries kept in the stream.
+//   - p_values: The fields of the entry.
+//
+// Returns:
+//   - string: The ID of the new entry (e.g., "1718030000000-0").
+//   - error: An error if the append fails, nil otherwise.
+func AppendStreamEvent(p_db *database.RedisPack, p_stream string, p_maxLen int64,
+	p_values map[string]interface{}) (string, error) {
+	return p_db.Client.XAdd(p_db.Ctx, &redis.XAddArgs{
+		Stream: p_stream,
+		MaxLen: p_maxLen,
+		Approx: true,
+		ID:     "*",
+		Values: p_values,
+	}).Result()
+}
+
+// endregion Public
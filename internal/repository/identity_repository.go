@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	oauthStatePrefix  string = "oauth_state:"  // oauthStatePrefix is the prefix used for storing pending federated logins in Redis.
+	samlRequestPrefix string = "saml_request:" // samlRequestPrefix is the prefix used for storing pending SAML logins in Redis.
+)
+
+// region Public
+
+// FindIdentity retrieves the identity of a provider account.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_provider: The name of the provider.
+//   - p_subject: The ID of the account at the provider.
+//
+// Returns:
+//   - *models.Identity: The identity if found.
+//   - error: gorm.ErrRecordNotFound if the account is not linked, or the query error.
+func FindIdentity(p_db *gorm.DB, p_provider string, p_subject string) (*models.Identity, error) {
+	var idt models.Identity
+	if err := p_db.Where("provider = ? AND subject = ?", p_provider, p_subject).First(&idt).Error; err != nil {
+		return nil, err
+	}
+	return &idt, nil
+}
+
+// CreateIdentity links a provider account to a user.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_identity: The identity to create.
+//
+// Returns:
+//   - error: An error object if the insert fails, e.g. when the account is already linked; otherwise, nil.
+func CreateIdentity(p_db *gorm.DB, p_identity *models.Identity) error {
+	return p_db.Create(p_identity).Error
+}
+
+// UserHasIdentities tells whether a user has at least one linked identity.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_usrId: The unique ID (string) of the user.
+//
+// Returns:
+//   - bool: true if an identity is linked to the user.
+//   - error: An error object if the query fails; otherwise, nil.
+func UserHasIdentities(p_db *gorm.DB, p_usrId string) (bool, error) {
+	var n int64
+	if err := p_db.Model(&models.Identity{}).Where("user_id = ?", p_usrId).Limit(1).Count(&n).Error; err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// TouchIdentity records a login with an identity and the email address shared by the provider.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_identity: The identity used to log in.
+//   - p_email: The email address shared by the provider.
+//
+// Returns:
+//   - error: An error object if the update fails; otherwise, nil.
+func TouchIdentity(p_db *gorm.DB, p_identity *models.Identity, p_email string) error {
+	return p_db.Model(p_identity).Updates(map[string]interface{}{
+		"email":         p_email,
+		"last_login_at": time.Now(),
+	}).Error
+}
+
+// StoreOAuthState stores a pending federated login in Redis.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_state: The state sent to the provider.
+//   - p_data: The JSON encoded login.
+//   - p_duration: How long the login may take at the provider.
+//
+// Returns:
+//   - error: An error if the storage operation fails, nil otherwise.
+func StoreOAuthState(p_db *database.RedisPack, p_state string, p_data string, p_duration time.Duration) error {
+	return p_db.Client.Set(p_db.Ctx, oauthStatePrefix+p_state, p_data, p_duration).Err()
+}
+
+// TakeOAuthState retrieves and removes a pending federated login from Redis, so that each
+// state can only be used once.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_state: The state received on the callback.
+//
+// Returns:
+//   - string: The JSON encoded login.
+//   - error: An error if the state is unknown, expired, or the retrieval fails, nil otherwise.
+func TakeOAuthState(p_db *database.RedisPack, p_state string) (string, error) {
+	val, err := p_db.Client.GetDel(p_db.Ctx, oauthStatePrefix+p_state).Result()
+	if err != nil {
+		return "", errors.New("not found")
+	}
+	return val, nil
+}
+
+// StoreSAMLRequest stores a pending SAML login in Redis.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_relayState: The relay state sent to the identity provider.
+//   - p_data: The JSON encoded login.
+//   - p_duration: How long the login may take at the provider.
+//
+// Returns:
+//   - error: An error if the storage operation fails, nil otherwise.
+func StoreSAMLRequest(p_db *database.RedisPack, p_relayState string, p_data string, p_duration time.Duration) error {
+	return p_db.Client.Set(p_db.Ctx, samlRequestPrefix+p_relayState, p_data, p_duration).Err()
+}
+
+// TakeSAMLRequest retrieves and removes a pending SAML login from Redis, so that each
+// authentication request can only be answered once.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_relayState: The relay state received on the ACS.
+//
+// Returns:
+//   - string: The JSON encoded login.
+//   - error: An error if the relay state is unknown, expired, or the retrieval fails, nil otherwise.
+func TakeSAMLRequest(p_db *database.RedisPack, p_relayState string) (string, error) {
+	val, err := p_db.Client.GetDel(p_db.Ctx, samlRequestPrefix+p_relayState).Result()
+	if err != nil {
+		return "", errors.New("not found")
+	}
+	return val, nil
+}
+
+// endregion Public
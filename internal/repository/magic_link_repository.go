@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"cerberus/internal/database"
+	"errors"
+	"time"
+)
+
+var (
+	magicLinkPrefix string = "magic_link:" // magicLinkPrefix is the prefix used for storing login links in Redis.
+)
+
+// region Public
+
+// StoreMagicLink stores a login link in Redis.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_token: The token of the link.
+//   - p_data: The JSON encoded link.
+//   - p_duration: How long the link remains valid.
+//
+// Returns:
+//   - error: An error if the storage operation fails, nil otherwise.
+func StoreMagicLink(p_db *database.RedisPack, p_token string, p_data string, p_duration time.Duration) error {
+	return p_db.Client.Set(p_db.Ctx, magicLinkPrefix+p_token, p_data, p_duration).Err()
+}
+
+// GetMagicLink retrieves a login link from Redis.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_token: The token of the link.
+//
+// Returns:
+//   - string: The JSON encoded link.
+//   - error: An error if the link is unknown, expired, or the retrieval fails, nil otherwise.
+func GetMagicLink(p_db *database.RedisPack, p_token string) (string, error) {
+	val, err := p_db.Client.Get(p_db.Ctx, magicLinkPrefix+p_token).Result()
+	if err != nil {
+		return "", errors.New("not found")
+	}
+	return val, nil
+}
+
+// DeleteMagicLink removes a login link from Redis. Only the caller that actually removed the
+// link gets true, so concurrent requests cannot both consume it.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_token: The token of the link.
+//
+// Returns:
+//   - bool: Whether the link was removed by this call.
+//   - error: An error if the delete operation fails, nil otherwise.
+func DeleteMagicLink(p_db *database.RedisPack, p_token string) (bool, error) {
+	n, err := p_db.Client.Del(p_db.Ctx, magicLinkPrefix+p_token).Result()
+	return n == 1, err
+}
+
+// endregion Public
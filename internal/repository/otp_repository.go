@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"cerberus/internal/database"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var (
+	otpPrefix        string = "otp:"          // otpPrefix is the prefix used for storing one-time codes in Redis.
+	otpFailurePrefix string = "otp_failures:" // otpFailurePrefix is the prefix of the wrong code counters in Redis.
+
+	// otpAttemptScript counts a guess on a code, without recreating a code that expired meanwhile.
+	otpAttemptScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return -1
+end
+return redis.call("HINCRBY", KEYS[1], "attempts", 1)`)
+)
+
+// region Public
+
+// StoreOTP stores a one-time code in Redis, replacing the pending code of the same purpose and
+// subject and resetting its attempt counter.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_purpose: What the code is for (e.g., "login").
+//   - p_subject: Who the code was issued to, usually a user ID.
+//   - p_fields: The fields of the code, such as its hash and recipient.
+//   - p_duration: How long the code remains valid.
+//
+// Returns:
+//   - error: An error if the storage operation fails, nil otherwise.
+func StoreOTP(p_db *database.RedisPack, p_purpose string, p_subject string, p_fields map[string]string,
+	p_duration time.Duration) error {
+	key := otpKey(p_purpose, p_subject)
+
+	pipe := p_db.Client.TxPipeline()
+	pipe.Del(p_db.Ctx, key)
+	pipe.HSet(p_db.Ctx, key, p_fields)
+	pipe.Expire(p_db.Ctx, key, p_duration)
+	_, err := pipe.Exec(p_db.Ctx)
+	return err
+}
+
+// GetOTP retrieves a pending one-time code from Redis.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_purpose: What the code is for.
+//   - p_subject: Who the code was issued to.
+//
+// Returns:
+//   - map[string]string: The fields of the code, including its "attempts" counter.
+//   - error: An error if no code is pending or the retrieval fails, nil otherwise.
+func GetOTP(p_db *database.RedisPack, p_purpose string, p_subject string) (map[string]string, error) {
+	fields, err := p_db.Client.HGetAll(p_db.Ctx, otpKey(p_purpose, p_subject)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("not found")
+	}
+	return fields, nil
+}
+
+// CountOTPAttempt records a guess on a pending one-time code.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_purpose: What the code is for.
+//   - p_subject: Who the code was issued to.
+//
+// Returns:
+//   - int64: The number of guesses made on the code, this one included.
+//   - error: An error if no code is pending or the update fails, nil otherwise.
+func CountOTPAttempt(p_db *database.RedisPack, p_purpose string, p_subject string) (int64, error) {
+	n, err := otpAttemptScript.Run(p_db.Ctx, p_db.Client, []string{otpKey(p_purpose, p_subject)}).Int64()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, errors.New("not found")
+	}
+	return n, nil
+}
+
+// DeleteOTP removes a one-time code from Redis. Only the caller that actually removed the code
+// gets true, so concurrent requests cannot both use it.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_purpose: What the code is for.
+//   - p_subject: Who the code was issued to.
+//
+// Returns:
+//   - bool: Whether the code was removed by this call.
+//   - error: An error if the delete operation fails, nil otherwise.
+func DeleteOTP(p_db *database.RedisPack, p_purpose string, p_subject string) (bool, error) {
+	n, err := p_db.Client.Del(p_db.Ctx, otpKey(p_purpose, p_subject)).Result()
+	return n == 1, err
+}
+
+// CountOTPFailure records a wrong code sent by a subject. The counter is not tied to a code:
+// it survives new codes and expires p_window after the first failure.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_subject: Who the code was issued to.
+//   - p_window: How long failures are counted.
+//
+// Returns:
+//   - int64: The number of failures in the window, this one included.
+//   - error: An error if the update fails, nil otherwise.
+func CountOTPFailure(p_db *database.RedisPack, p_subject string, p_window time.Duration) (int64, error) {
+	return rateLimitScript.Run(p_db.Ctx, p_db.Client, []string{otpFailurePrefix + p_subject},
+		p_window.Milliseconds()).Int64()
+}
+
+// GetOTPFailures retrieves the number of wrong codes sent by a subject in the current window.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_subject: Who the codes were issued to.
+//
+// Returns:
+//   - int64: The number of failures, 0 outside of a window.
+//   - error: An error if the retrieval fails, nil otherwise.
+func GetOTPFailures(p_db *database.RedisPack, p_subject string) (int64, error) {
+	n, err := p_db.Client.Get(p_db.Ctx, otpFailurePrefix+p_subject).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return n, err
+}
+
+// ClearOTPFailures resets the wrong code counter of a subject.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_subject: Who the codes were issued to.
+//
+// Returns:
+//   - error: An error if the delete operation fails, nil otherwise.
+func ClearOTPFailures(p_db *database.RedisPack, p_subject string) error {
+	return p_db.Client.Del(p_db.Ctx, otpFailurePrefix+p_subject).Err()
+}
+
+// endregion Public
+
+// region Private
+
+// otpKey returns the Redis key of the code of a purpose and subject.
+func otpKey(p_purpose string, p_subject string) string {
+	return otpPrefix + p_purpose + ":" + p_subject
+}
+
+// endregion Private
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"cerberus/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// region Public
+
+// CreatePersonalToken inserts a new personal access token.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_tkn: A pointer to the token to insert.
+//
+// Returns:
+//   - error: An error if the insertion fails, e.g. when the name or prefix is taken, or nil if successful.
+func CreatePersonalToken(p_db *gorm.DB, p_tkn *models.PersonalToken) error {
+	return p_db.Create(p_tkn).Error
+}
+
+// FindPersonalTokenByPrefix retrieves a personal access token by its lookup prefix.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_prefix: The public prefix of the token.
+//
+// Returns:
+//   - *models.PersonalToken: The token if found, nil otherwise.
+//   - error: gorm.ErrRecordNotFound if no token matches, or the database error.
+func FindPersonalTokenByPrefix(p_db *gorm.DB, p_prefix string) (*models.PersonalToken, error) {
+	var tkn models.PersonalToken
+	if err := p_db.Where("prefix = ?", p_prefix).First(&tkn).Error; err != nil {
+		return nil, err
+	}
+	return &tkn, nil
+}
+
+// ListPersonalTokens retrieves the personal access tokens of a user, oldest first.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_usrId: The unique ID (string) of the user.
+//
+// Returns:
+//   - []models.PersonalToken: The tokens of the user.
+//   - error: An error object if the query fails; otherwise, nil.
+func ListPersonalTokens(p_db *gorm.DB, p_usrId string) ([]models.PersonalToken, error) {
+	var tkns []models.PersonalToken
+	if err := p_db.Where("user_id = ?", p_usrId).Order("created_at ASC").Find(&tkns).Error; err != nil {
+		return nil, err
+	}
+	return tkns, nil
+}
+
+// TouchPersonalToken records that a personal access token was used.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_id: The unique ID (string) of the token.
+//   - p_at: When the token was used.
+//
+// Returns:
+//   - error: An error object if the update fails; otherwise, nil.
+func TouchPersonalToken(p_db *gorm.DB, p_id string, p_at time.Time) error {
+	return p_db.Model(&models.PersonalToken{}).Where("id = ?", p_id).Update("last_used_at", p_at).Error
+}
+
+// DeletePersonalToken removes a personal access token of a user.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_usrId: The unique ID (string) of the user owning the token.
+//   - p_id: The unique ID (string) of the token.
+//
+// Returns:
+//   - error: gorm.ErrRecordNotFound if the user owns no such token, or the database error.
+func DeletePersonalToken(p_db *gorm.DB, p_usrId string, p_id string) error {
+	res := p_db.Where("id = ? AND user_id = ?", p_id, p_usrId).Delete(&models.PersonalToken{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteUserPersonalTokens removes every personal access token of a user.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_usrId: The unique ID (string) of the user.
+//
+// Returns:
+//   - int64: The number of removed tokens.
+//   - error: An error if the deletion fails, or nil if successful.
+func DeleteUserPersonalTokens(p_db *gorm.DB, p_usrId string) (int64, error) {
+	res := p_db.Where("user_id = ?", p_usrId).Delete(&models.PersonalToken{})
+	return res.RowsAffected, res.Error
+}
+
+// endregion Public
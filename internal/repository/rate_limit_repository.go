@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"cerberus/internal/database"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var (
+	rateLimitPrefix string = "rate:" // rateLimitPrefix is the prefix used for storing rate limit counters in Redis.
+
+	// rateLimitScript counts a request, starting the window on the first one.
+	rateLimitScript = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return n`)
+)
+
+// region Public
+
+// CountRateLimitHit records a request against a fixed window rate limit. The window starts
+// with the first request and is not extended by the following ones.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_key: What is limited, such as "magic_link:ip:<address>".
+//   - p_window: How long requests are counted.
+//
+// Returns:
+//   - int64: The number of requests in the window, this one included.
+//   - error: An error if the update fails, nil otherwise.
+func CountRateLimitHit(p_db *database.RedisPack, p_key string, p_window time.Duration) (int64, error) {
+	return rateLimitScript.Run(p_db.Ctx, p_db.Client, []string{rateLimitPrefix + p_key},
+		p_window.Milliseconds()).Int64()
+}
+
+// endregion Public
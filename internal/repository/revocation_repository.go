@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"cerberus/internal/database"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var (
+	revocationsKey string = "revocations" // revocationsKey is the sorted set of revoked tokens, scored by revocation time.
+)
+
+// region Public
+
+// StoreRevocation adds a revoked token to the revocation feed and drops the entries older
+// than p_retention, whose tokens have expired in the meantime.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_entry: The JSON encoded revocation.
+//   - p_revokedAt: When the token was revoked, used to order the feed.
+//   - p_retention: How long entries are kept, at least the lifetime of an access token.
+//
+// Returns:
+//   - error: An error if the storage operation fails, nil otherwise.
+func StoreRevocation(p_db *database.RedisPack, p_entry string, p_revokedAt time.Time, p_retention time.Duration) error {
+	pipe := p_db.Client.TxPipeline()
+	pipe.ZAdd(p_db.Ctx, revocationsKey, &redis.Z{Score: float64(p_revokedAt.UnixMilli()), Member: p_entry})
+	pipe.ZRemRangeByScore(p_db.Ctx, revocationsKey, "-inf",
+		"("+strconv.FormatInt(time.Now().Add(-p_retention).UnixMilli(), 10))
+
+	_, err := pipe.Exec(p_db.Ctx)
+	return err
+}
+
+// ListRevocationsSince retrieves the revocations of the feed that happened at or after an
+// instant, oldest first.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_since: The earliest revocation time to return.
+//
+// Returns:
+//   - []string: The JSON encoded revocations.
+//   - error: An error if the retrieval fails, nil otherwise.
+func ListRevocationsSince(p_db *database.RedisPack, p_since time.Time) ([]string, error) {
+	return p_db.Client.ZRangeByScore(p_db.Ctx, revocationsKey, &redis.ZRangeBy{
+		Min: strconv.FormatInt(p_since.UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+}
+
+// PublishRevocation broadcasts a revocation to the subscribers of a pub/sub channel.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_channel: The name of the channel.
+//   - p_entry: The JSON encoded revocation.
+//
+// Returns:
+//   - error: An error if the publication fails, nil otherwise.
+func PublishRevocation(p_db *database.RedisPack, p_channel string, p_entry string) error {
+	return p_db.Client.Publish(p_db.Ctx, p_channel, p_entry).Err()
+}
+
+// endregion Public
@@ -8,8 +8,9 @@ import (
 )
 
 var (
-	refreshPrefix string = "refresh:" // refreshPrefix is the prefix used for storing refresh tokens in Redis.
-	tokenPrefix   string = "token:"   // tokenPrefix is the prefix used for storing JWT tokens in Redis.
+	refreshPrefix     string = "refresh:"      // refreshPrefix is the prefix used for storing refresh tokens in Redis.
+	tokenPrefix       string = "token:"        // tokenPrefix is the prefix used for storing JWT tokens in Redis.
+	sessionAuthPrefix string = "session_auth:" // sessionAuthPrefix is the prefix used for storing session authentications in Redis.
 )
 
 // StoreJWTToken stores a JWT token in Redis for a given user ID.
@@ -100,3 +101,85 @@ func GetRefreshToken(p_db *database.RedisPack, p_usrId string) (string, error) {
 func RevokeRefreshToken(p_db *database.RedisPack, p_usrId string) error {
 	return p_db.Client.Del(p_db.Ctx, refreshPrefix+p_usrId).Err()
 }
+
+// StoreSessionAuth stores how the user of a session authenticated.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_usrId: The user ID of the session.
+//   - p_data: The JSON encoded authentication.
+//   - p_duration: How long the record is kept, the lifetime of the session.
+//
+// Returns:
+//   - error: An error if the storage operation fails, nil otherwise.
+func StoreSessionAuth(p_db *database.RedisPack, p_usrId string, p_data string, p_duration time.Duration) error {
+	return p_db.Client.Set(p_db.Ctx, sessionAuthPrefix+p_usrId, p_data, p_duration).Err()
+}
+
+// GetSessionAuth retrieves how the user of a session authenticated.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_usrId: The user ID of the session.
+//
+// Returns:
+//   - string: The JSON encoded authentication.
+//   - error: An error if no record is stored or retrieval fails, nil otherwise.
+func GetSessionAuth(p_db *database.RedisPack, p_usrId string) (string, error) {
+	val, err := p_db.Client.Get(p_db.Ctx, sessionAuthPrefix+p_usrId).Result()
+	if err != nil {
+		return "", errors.New("not found")
+	}
+	return val, nil
+}
+
+// RevokeSessionAuth removes the authentication record of the session of a user.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_usrId: The user ID of the session.
+//
+// Returns:
+//   - error: An error if the delete operation fails, nil otherwise.
+func RevokeSessionAuth(p_db *database.RedisPack, p_usrId string) error {
+	return p_db.Client.Del(p_db.Ctx, sessionAuthPrefix+p_usrId).Err()
+}
+
+// GetJWTTokenTTL retrieves the remaining lifetime of the JWT token stored for a given user ID.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_usrId: The user ID associated with the token.
+//
+// Returns:
+//   - time.Duration: The remaining lifetime of the token.
+//   - error: An error if no token is stored or retrieval fails, nil otherwise.
+func GetJWTTokenTTL(p_db *database.RedisPack, p_usrId string) (time.Duration, error) {
+	return getTTL(p_db, tokenPrefix+p_usrId)
+}
+
+// GetRefreshTokenTTL retrieves the remaining lifetime of the refresh token stored for a given user ID.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_usrId: The user ID associated with the refresh token.
+//
+// Returns:
+//   - time.Duration: The remaining lifetime of the refresh token.
+//   - error: An error if no refresh token is stored or retrieval fails, nil otherwise.
+func GetRefreshTokenTTL(p_db *database.RedisPack, p_usrId string) (time.Duration, error) {
+	return getTTL(p_db, refreshPrefix+p_usrId)
+}
+
+// getTTL returns the remaining lifetime of a key, or an error if the key does not exist
+// or has no expiration.
+func getTTL(p_db *database.RedisPack, p_key string) (time.Duration, error) {
+	ttl, err := p_db.Client.TTL(p_db.Ctx, p_key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, errors.New("not found")
+	}
+	return ttl, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"cerberus/internal/database"
+	"strconv"
+	"time"
+)
+
+var (
+	tokenExchangePrefix string = "token_exchange:" // tokenExchangePrefix is the prefix used for storing the tokens issued by token exchange in Redis.
+)
+
+// region Public
+
+// StoreExchangedToken records a token issued by token exchange for a user, so that it is
+// accepted until it expires or the sessions of the user are revoked.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_usrId: The unique ID (string) of the subject of the token.
+//   - p_tokenId: The "jti" claim of the token.
+//   - p_expiresAt: When the token expires.
+//   - p_duration: How long the tokens of the user are kept, at least the lifetime of the token.
+//
+// Returns:
+//   - error: An error if the storage operation fails, nil otherwise.
+func StoreExchangedToken(p_db *database.RedisPack, p_usrId string, p_tokenId string, p_expiresAt time.Time,
+	p_duration time.Duration) error {
+	pipe := p_db.Client.TxPipeline()
+	pipe.HSet(p_db.Ctx, tokenExchangePrefix+p_usrId, p_tokenId, p_expiresAt.Unix())
+	pipe.Expire(p_db.Ctx, tokenExchangePrefix+p_usrId, p_duration)
+	_, err := pipe.Exec(p_db.Ctx)
+	return err
+}
+
+// IsExchangedTokenActive checks whether a token issued by token exchange was recorded for a
+// user and not revoked since.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_usrId: The unique ID (string) of the subject of the token.
+//   - p_tokenId: The "jti" claim of the token.
+//
+// Returns:
+//   - bool: Whether the token is active.
+//   - error: An error if the retrieval fails, nil otherwise.
+func IsExchangedTokenActive(p_db *database.RedisPack, p_usrId string, p_tokenId string) (bool, error) {
+	return p_db.Client.HExists(p_db.Ctx, tokenExchangePrefix+p_usrId, p_tokenId).Result()
+}
+
+// RevokeExchangedTokens removes every token issued by token exchange for a user.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_usrId: The unique ID (string) of the subject of the tokens.
+//
+// Returns:
+//   - map[string]time.Time: The expiration of the revoked tokens, by "jti".
+//   - error: An error if the delete operation fails, nil otherwise.
+func RevokeExchangedTokens(p_db *database.RedisPack, p_usrId string) (map[string]time.Time, error) {
+	pipe := p_db.Client.TxPipeline()
+	all := pipe.HGetAll(p_db.Ctx, tokenExchangePrefix+p_usrId)
+	pipe.Del(p_db.Ctx, tokenExchangePrefix+p_usrId)
+	if _, err := pipe.Exec(p_db.Ctx); err != nil {
+		return nil, err
+	}
+
+	tkns := make(map[string]time.Time, len(all.Val()))
+	for id, exp := range all.Val() {
+		unix, err := strconv.ParseInt(exp, 10, 64)
+		if err != nil {
+			continue
+		}
+		tkns[id] = time.Unix(unix, 0)
+	}
+	return tkns, nil
+}
+
+// endregion Public
@@ -1,14 +1,33 @@
 package repository
 
 import (
+	"cerberus/internal/database"
 	"cerberus/internal/models"
+	"errors"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// EmailCollision groups the users whose email addresses only differ by case.
+//
+// Fields:
+//   - Email: The lowercased email address shared by the colliding users.
+//   - UserIds: The IDs of the users sharing the address, oldest account first.
+type EmailCollision struct {
+	Email   string
+	UserIds []string
+}
+
+var (
+	emailChangePrefix string = "email_change:" // emailChangePrefix is the prefix used for storing pending email changes in Redis.
+)
+
 // region Public
 
 // FindUserByEmail retrieves a user from the database by their email address.
+// The comparison is case-insensitive, matching the lower(email) unique index.
 //
 // Parameters:
 //   - p_db: A gorm.DB instance representing the database connection.
@@ -19,7 +38,7 @@ import (
 //   - error: An error if the database query fails, or nil if successful.
 func FindUserByEmail(p_db *gorm.DB, p_email string) (*models.User, error) {
 	var u models.User
-	res := p_db.Where("email = ?", p_email).First(&u)
+	res := p_db.Where("lower(email) = lower(?)", p_email).First(&u)
 	if res.Error != nil {
 		return nil, res.Error
 	}
@@ -61,7 +80,7 @@ func CreateUser(p_db *gorm.DB, p_user *models.User) error {
 
 // UpdatePassword updates the password of a user in the database.
 // It uses the provided GORM database connection to update the "password" field
-// for the user identified by their ID.
+// for the user identified by their ID, and clears any pending forced password reset.
 //
 // Parameters:
 //   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
@@ -71,7 +90,274 @@ func CreateUser(p_db *gorm.DB, p_user *models.User) error {
 // Returns:
 //   - error: An error object if the update operation fails; otherwise, nil.
 func UpdatePassword(p_db *gorm.DB, p_user *models.User, p_pwd string) error {
-	return p_db.Model(&models.User{}).Where("id = ?", p_user.ID).Update("password", p_pwd).Error
+	return p_db.Model(&models.User{}).Where("id = ?", p_user.ID).
+		Updates(map[string]interface{}{"password": p_pwd, "password_reset_required": false}).Error
+}
+
+// UpdateProfile updates the editable profile fields (name and attributes) of a user.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_user: A pointer to the user model holding the new values.
+//
+// Returns:
+//   - error: An error object if the update operation fails; otherwise, nil.
+func UpdateProfile(p_db *gorm.DB, p_user *models.User) error {
+	return p_db.Model(&models.User{}).Where("id = ?", p_user.ID).
+		Select("name", "attributes").Updates(p_user).Error
+}
+
+// UpdateEmail updates the email address of a user.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_user: A pointer to the user model (*models.User) containing the user's ID.
+//   - p_email: The new canonical email address.
+//
+// Returns:
+//   - error: An error object if the update operation fails; otherwise, nil.
+func UpdateEmail(p_db *gorm.DB, p_user *models.User, p_email string) error {
+	return p_db.Model(&models.User{}).Where("id = ?", p_user.ID).Update("email", p_email).Error
+}
+
+// UpdatePhone updates the verified phone number of a user.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_user: A pointer to the user model (*models.User) containing the user's ID.
+//   - p_phone: The new phone number in E.164 format, empty to remove it.
+//
+// Returns:
+//   - error: An error object if the update operation fails; otherwise, nil.
+func UpdatePhone(p_db *gorm.DB, p_user *models.User, p_phone string) error {
+	return p_db.Model(&models.User{}).Where("id = ?", p_user.ID).Update("phone", p_phone).Error
+}
+
+// StorePendingEmailChange stores a pending email change in Redis until it is confirmed.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_token: The verification token sent to the new address.
+//   - p_usrId: The ID of the user requesting the change.
+//   - p_email: The new canonical email address.
+//   - p_duration: How long the pending change remains valid.
+//
+// Returns:
+//   - error: An error if the storage operation fails, nil otherwise.
+func StorePendingEmailChange(p_db *database.RedisPack, p_token string, p_usrId string, p_email string,
+	p_duration time.Duration) error {
+	return p_db.Client.Set(p_db.Ctx, emailChangePrefix+p_token, p_usrId+"|"+p_email, p_duration).Err()
+}
+
+// GetPendingEmailChange retrieves a pending email change from Redis.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_token: The verification token sent to the new address.
+//
+// Returns:
+//   - string: The ID of the user who requested the change.
+//   - string: The new canonical email address.
+//   - error: An error if the change is not found or retrieval fails, nil otherwise.
+func GetPendingEmailChange(p_db *database.RedisPack, p_token string) (string, string, error) {
+	val, err := p_db.Client.Get(p_db.Ctx, emailChangePrefix+p_token).Result()
+	if err != nil {
+		return "", "", errors.New("not found")
+	}
+
+	usrId, mail, ok := strings.Cut(val, "|")
+	if !ok {
+		return "", "", errors.New("malformed pending email change")
+	}
+	return usrId, mail, nil
+}
+
+// DeletePendingEmailChange removes a pending email change from Redis.
+//
+// Parameters:
+//   - p_db: A pointer to the RedisPack instance for database operations.
+//   - p_token: The verification token of the pending change.
+//
+// Returns:
+//   - error: An error if the delete operation fails, nil otherwise.
+func DeletePendingEmailChange(p_db *database.RedisPack, p_token string) error {
+	return p_db.Client.Del(p_db.Ctx, emailChangePrefix+p_token).Err()
+}
+
+// ListUsers retrieves a page of users ordered by creation date, newest first.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_offset: The number of users to skip.
+//   - p_limit: The maximum number of users to return.
+//   - p_search: An optional case-insensitive filter matched against the email and the name.
+//
+// Returns:
+//   - []models.User: The users of the requested page.
+//   - int64: The total number of users matching the filter.
+//   - error: An error object if the query fails; otherwise, nil.
+func ListUsers(p_db *gorm.DB, p_offset int, p_limit int, p_search string) ([]models.User, int64, error) {
+	query := p_db.Model(&models.User{})
+	if p_search != "" {
+		pattern := "%" + escapeLike(p_search) + "%"
+		query = query.Where("email ILIKE ? OR name ILIKE ?", pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	res := query.Order("created_at DESC").Offset(p_offset).Limit(p_limit).Find(&users)
+	if res.Error != nil {
+		return nil, 0, res.Error
+	}
+
+	return users, total, nil
+}
+
+// UpdateStatus persists the status, status reason and status change time of a user.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_user: A pointer to the user model (*models.User) holding the ID and the new status fields.
+//
+// Returns:
+//   - error: An error object if the update operation fails; otherwise, nil.
+func UpdateStatus(p_db *gorm.DB, p_user *models.User) error {
+	return p_db.Model(&models.User{}).Where("id = ?", p_user.ID).
+		Select("status", "status_reason", "status_changed_at").Updates(p_user).Error
+}
+
+// UpdateRoles replaces the roles of a user.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_user: A pointer to the user model (*models.User) holding the ID and the new roles.
+//
+// Returns:
+//   - error: An error object if the update operation fails; otherwise, nil.
+func UpdateRoles(p_db *gorm.DB, p_user *models.User) error {
+	return p_db.Model(&models.User{}).Where("id = ?", p_user.ID).Select("roles").Updates(p_user).Error
+}
+
+// SetPasswordResetRequired flags a user as having to change their password before logging in.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_user: A pointer to the user model (*models.User) containing the user's ID.
+//   - p_required: Whether a password reset is required.
+//
+// Returns:
+//   - error: An error object if the update operation fails; otherwise, nil.
+func SetPasswordResetRequired(p_db *gorm.DB, p_user *models.User, p_required bool) error {
+	return p_db.Model(&models.User{}).Where("id = ?", p_user.ID).Update("password_reset_required", p_required).Error
+}
+
+// SoftDeleteUser marks a user as deleted. The row is kept, but regular queries ignore it
+// until it is permanently removed by PurgeDeletedUsers.
+//
+// The external identities of the user are removed at once, so that the same provider accounts
+// can sign up again during the grace period.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_user: A pointer to the user model (*models.User) containing the user's ID.
+//
+// Returns:
+//   - error: An error object if the delete operation fails; otherwise, nil.
+func SoftDeleteUser(p_db *gorm.DB, p_user *models.User) error {
+	return p_db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", p_user.ID).Delete(&models.Identity{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.User{}, "id = ?", p_user.ID).Error
+	})
+}
+
+// PurgeDeletedUsers permanently removes the users soft-deleted before the given cutoff.
+//
+// The audit events whose actor or target is a removed user are pseudonymised in the same
+// transaction: their IP address, User-Agent and details are cleared, and only the IDs of the
+// users remain. The audit log trigger allows this update alone, and only for transactions
+// setting "cerberus.audit_pseudonymise".
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_cutoff: Users deleted before this instant are removed.
+//
+// Returns:
+//   - int64: The number of removed users.
+//   - error: An error object if the delete operation fails; otherwise, nil.
+func PurgeDeletedUsers(p_db *gorm.DB, p_cutoff time.Time) (int64, error) {
+	var purged int64
+
+	err := p_db.Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		err := tx.Unscoped().Model(&models.User{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", p_cutoff).Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		if err := tx.Exec("SET LOCAL cerberus.audit_pseudonymise = 'on'").Error; err != nil {
+			return err
+		}
+		err = tx.Model(&models.AuditEvent{}).Where("actor_id IN ? OR target_id IN ?", ids, ids).
+			Updates(map[string]interface{}{"ip": "", "user_agent": "", "details": nil}).Error
+		if err != nil {
+			return err
+		}
+
+		res := tx.Unscoped().Where("id IN ?", ids).Delete(&models.User{})
+		purged = res.RowsAffected
+		return res.Error
+	})
+	return purged, err
+}
+
+// FindEmailCollisions lists the email addresses shared by more than one user when compared
+// case-insensitively. These rows prevent the lower(email) unique index from being created.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//
+// Returns:
+//   - []EmailCollision: The colliding addresses with the IDs of the users sharing them.
+//   - error: An error object if the query fails; otherwise, nil.
+func FindEmailCollisions(p_db *gorm.DB) ([]EmailCollision, error) {
+	var rows []struct {
+		Email   string
+		UserIds string
+	}
+
+	res := p_db.Model(&models.User{}).
+		Select("lower(email) AS email, string_agg(id::text, ',' ORDER BY created_at) AS user_ids").
+		Group("lower(email)").
+		Having("count(*) > 1").
+		Order("email").
+		Scan(&rows)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	collisions := make([]EmailCollision, 0, len(rows))
+	for _, r := range rows {
+		collisions = append(collisions, EmailCollision{
+			Email:   r.Email,
+			UserIds: strings.Split(r.UserIds, ","),
+		})
+	}
+	return collisions, nil
 }
 
 // endregion Public
+// region Private
+
+// escapeLike escapes the wildcard characters of a LIKE pattern.
+func escapeLike(p_value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(p_value)
+}
+
+// endregion Private
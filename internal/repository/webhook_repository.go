@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"cerberus/internal/models"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// region Public
+
+// CreateWebhookSubscription inserts a new webhook subscription.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_sub: A pointer to the subscription to insert.
+//
+// Returns:
+//   - error: An error if the insertion fails, or nil if successful.
+func CreateWebhookSubscription(p_db *gorm.DB, p_sub *models.WebhookSubscription) error {
+	return p_db.Create(p_sub).Error
+}
+
+// FindWebhookSubscription retrieves a webhook subscription by its ID.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_id: The unique ID (string) of the subscription.
+//
+// Returns:
+//   - *models.WebhookSubscription: The subscription if found, nil otherwise.
+//   - error: gorm.ErrRecordNotFound if no subscription matches, or the database error.
+func FindWebhookSubscription(p_db *gorm.DB, p_id string) (*models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	if err := p_db.Where("id = ?", p_id).First(&sub).Error; err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// ListWebhookSubscriptions retrieves every webhook subscription, oldest first.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//
+// Returns:
+//   - []models.WebhookSubscription: The subscriptions.
+//   - error: An error object if the query fails; otherwise, nil.
+func ListWebhookSubscriptions(p_db *gorm.DB) ([]models.WebhookSubscription, error) {
+	var subs []models.WebhookSubscription
+	if err := p_db.Order("created_at ASC").Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// FindSubscriptionsForEvent retrieves the active subscriptions listening to an event type.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_eventType: The event type the subscriptions must accept.
+//
+// Returns:
+//   - []models.WebhookSubscription: The matching subscriptions.
+//   - error: An error object if the query fails; otherwise, nil.
+func FindSubscriptionsForEvent(p_db *gorm.DB, p_eventType string) ([]models.WebhookSubscription, error) {
+	var subs []models.WebhookSubscription
+	res := p_db.Where("active = ? AND event_types @> ?::jsonb", true, `[`+strconv.Quote(p_eventType)+`]`).Find(&subs)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return subs, nil
+}
+
+// SaveWebhookSubscription updates every field of an existing webhook subscription.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_sub: A pointer to the modified subscription.
+//
+// Returns:
+//   - error: An error if the update fails, or nil if successful.
+func SaveWebhookSubscription(p_db *gorm.DB, p_sub *models.WebhookSubscription) error {
+	return p_db.Save(p_sub).Error
+}
+
+// DeleteWebhookSubscription removes a webhook subscription and, through the foreign key,
+// its delivery log.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_id: The unique ID (string) of the subscription.
+//
+// Returns:
+//   - error: gorm.ErrRecordNotFound if no subscription matches, or the database error.
+func DeleteWebhookSubscription(p_db *gorm.DB, p_id string) error {
+	res := p_db.Where("id = ?", p_id).Delete(&models.WebhookSubscription{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CreateWebhookDeliveries queues deliveries for the delivery worker.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_deliveries: The deliveries to insert.
+//
+// Returns:
+//   - error: An error if the insertion fails, or nil if successful.
+func CreateWebhookDeliveries(p_db *gorm.DB, p_deliveries []models.WebhookDelivery) error {
+	if len(p_deliveries) == 0 {
+		return nil
+	}
+	return p_db.Omit(clause.Associations).Create(&p_deliveries).Error
+}
+
+// ClaimDueWebhookDeliveries retrieves pending deliveries whose next attempt is due, together
+// with their subscription. Deliveries of inactive subscriptions are not claimed: they stay
+// pending until the subscription is reactivated.
+//
+// The claimed rows are locked with SKIP LOCKED and their next attempt is pushed back by
+// p_lease, so concurrent workers (or instances) never send the same delivery twice.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_limit: The maximum number of deliveries to claim.
+//   - p_lease: How long the claimed deliveries are hidden from other workers.
+//
+// Returns:
+//   - []models.WebhookDelivery: The claimed deliveries.
+//   - error: An error object if the query fails; otherwise, nil.
+func ClaimDueWebhookDeliveries(p_db *gorm.DB, p_limit int, p_lease time.Duration) ([]models.WebhookDelivery, error) {
+	var ids []string
+
+	err := p_db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		res := tx.Model(&models.WebhookDelivery{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Scopes(dueWebhookDeliveries(now)).
+			Order("next_attempt_at ASC").Limit(p_limit).Pluck("id", &ids)
+		if res.Error != nil || len(ids) == 0 {
+			return res.Error
+		}
+
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(p_lease)).Error
+	})
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := p_db.Preload("Subscription").Where("id IN ?", ids).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// SaveWebhookDelivery records the result of a delivery attempt.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_delivery: A pointer to the modified delivery.
+//
+// Returns:
+//   - error: An error if the update fails, or nil if successful.
+func SaveWebhookDelivery(p_db *gorm.DB, p_delivery *models.WebhookDelivery) error {
+	return p_db.Omit(clause.Associations).Save(p_delivery).Error
+}
+
+// RetryWebhookDelivery schedules a delivery of a subscription for an immediate new attempt,
+// whatever its current status.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_subId: The unique ID (string) of the subscription.
+//   - p_deliveryId: The unique ID (string) of the delivery.
+//
+// Returns:
+//   - error: gorm.ErrRecordNotFound if no delivery matches, or the database error.
+func RetryWebhookDelivery(p_db *gorm.DB, p_subId string, p_deliveryId string) error {
+	res := p_db.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND subscription_id = ?", p_deliveryId, p_subId).
+		Updates(map[string]interface{}{
+			"status":          models.DeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListWebhookDeliveries retrieves a page of the delivery log of a subscription, newest first.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_subId: The unique ID (string) of the subscription.
+//   - p_status: Only return deliveries with this status, or "" for every status.
+//   - p_offset: The number of deliveries to skip.
+//   - p_limit: The maximum number of deliveries to return.
+//
+// Returns:
+//   - []models.WebhookDelivery: The deliveries of the requested page.
+//   - int64: The total number of matching deliveries.
+//   - error: An error object if the query fails; otherwise, nil.
+func ListWebhookDeliveries(p_db *gorm.DB, p_subId string, p_status string, p_offset int,
+	p_limit int) ([]models.WebhookDelivery, int64, error) {
+	query := p_db.Model(&models.WebhookDelivery{}).Where("subscription_id = ?", p_subId)
+	if p_status != "" {
+		query = query.Where("status = ?", p_status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	res := query.Order("created_at DESC").Offset(p_offset).Limit(p_limit).Find(&deliveries)
+	if res.Error != nil {
+		return nil, 0, res.Error
+	}
+
+	return deliveries, total, nil
+}
+
+// endregion Public
+
+// region Private
+
+// dueWebhookDeliveries scopes a query to the pending deliveries whose next attempt is due and
+// whose subscription is active.
+func dueWebhookDeliveries(p_now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		active := db.Session(&gorm.Session{NewDB: true}).Model(&models.WebhookSubscription{}).
+			Select("id").Where("active = ?", true)
+		return db.Where("status = ? AND next_attempt_at <= ?", models.DeliveryPending, p_now).
+			Where("subscription_id IN (?)", active)
+	}
+}
+
+// endregion Private
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"cerberus/internal/models"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestDueWebhookDeliveriesSkipsInactiveSubscriptions(t *testing.T) {
+	// Dry run: the statement is built without reaching a database.
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var deliveries []models.WebhookDelivery
+	stmt := db.Model(&models.WebhookDelivery{}).Scopes(dueWebhookDeliveries(time.Now())).Find(&deliveries).Statement
+
+	sql := stmt.SQL.String()
+	if !strings.Contains(sql, `subscription_id IN (SELECT "id" FROM "webhook_subscriptions" WHERE active = $3)`) {
+		t.Errorf("query = %s, want it restricted to active subscriptions", sql)
+	}
+	if !slices.Contains(stmt.Vars, interface{}(true)) || !slices.Contains(stmt.Vars, interface{}(models.DeliveryPending)) {
+		t.Errorf("vars = %v", stmt.Vars)
+	}
+}
@@ -0,0 +1,78 @@
+package routes
+
+import (
+	"cerberus/internal/database"
+	admin_handler "cerberus/internal/handlers/admin"
+	md "cerberus/internal/middleware"
+	"cerberus/internal/models"
+	"cerberus/internal/tools/logger"
+	"cerberus/pkg/config"
+	"net/http"
+)
+
+// SetupAdminRoutes configures the user administration routes.
+//
+// Every route in the "/admin" group requires an active session of a user holding the
+// models.RoleAdmin role. User specific routes read the target user from the "{id}" path value.
+//
+// Parameters:
+//   - p_mux: A pointer to the http.ServeMux to which the routes will be added.
+//   - p_cfg: A pointer to the ConfigData structure containing application configuration.
+//   - p_dbs: A pointer to the DataRefs structure containing database references.
+//
+// Returns:
+//   - []*Route: A slice of pointers to Route structures representing the configured routes.
+func SetupAdminRoutes(p_mux *http.ServeMux, p_cfg *config.ConfigData, p_dbs *database.DataRefs) []*Route {
+	logger.Log("🛡️ Setting up Admin Routes", logger.INFO)
+
+	var adminGroup *GroupRoute = NewGroupRoute(p_mux, "/admin",
+		md.TimeRequestMiddleware, md.CORSMiddleware(p_cfg), md.LogRequestMiddleware,
+		md.RequireRoleMiddleware(p_dbs, models.RoleAdmin), md.RequireScopeMiddleware(models.ScopeAdminRead, models.ScopeAdminWrite),
+		md.CSRFMiddleware(p_cfg), md.SessionMiddleware(p_dbs),
+		md.AuthenticationHeaderMiddleware(p_cfg))
+
+	return []*Route{
+		adminGroup.NewRoute("/users", admin_handler.CreateListUsersHandler(p_dbs),
+			md.GetMethodCheckMiddleware),
+
+		adminGroup.NewRoute("/users/{id}", MethodHandler{
+			http.MethodGet:    admin_handler.CreateGetUserHandler(p_dbs),
+			http.MethodDelete: admin_handler.CreateDeleteUserHandler(p_dbs),
+		}),
+
+		adminGroup.NewRoute("/users/{id}/status", admin_handler.CreateChangeStatusHandler(p_dbs, ""),
+			md.PostMethodCheckMiddleware),
+
+		adminGroup.NewRoute("/users/{id}/disable", admin_handler.CreateChangeStatusHandler(p_dbs, models.StatusDisabled),
+			md.PostMethodCheckMiddleware),
+
+		adminGroup.NewRoute("/users/{id}/enable", admin_handler.CreateChangeStatusHandler(p_dbs, models.StatusActive),
+			md.PostMethodCheckMiddleware),
+
+		adminGroup.NewRoute("/users/{id}/logout", admin_handler.CreateForceLogoutHandler(p_dbs),
+			md.PostMethodCheckMiddleware),
+
+		adminGroup.NewRoute("/users/{id}/reset-password", admin_handler.CreateForcePasswordResetHandler(p_dbs),
+			md.PostMethodCheckMiddleware),
+
+		adminGroup.NewRoute("/webhooks", MethodHandler{
+			http.MethodGet:  admin_handler.CreateListWebhooksHandler(p_dbs),
+			http.MethodPost: admin_handler.CreateAddWebhookHandler(p_dbs),
+		}),
+
+		adminGroup.NewRoute("/webhooks/{id}", MethodHandler{
+			http.MethodGet:    admin_handler.CreateGetWebhookHandler(p_dbs),
+			http.MethodPatch:  admin_handler.CreateUpdateWebhookHandler(p_dbs),
+			http.MethodDelete: admin_handler.CreateDeleteWebhookHandler(p_dbs),
+		}),
+
+		adminGroup.NewRoute("/webhooks/{id}/deliveries", admin_handler.CreateListDeliveriesHandler(p_dbs),
+			md.GetMethodCheckMiddleware),
+
+		adminGroup.NewRoute("/webhooks/{id}/deliveries/{delivery_id}/retry", admin_handler.CreateRetryDeliveryHandler(p_dbs),
+			md.PostMethodCheckMiddleware),
+
+		adminGroup.NewRoute("/audit", admin_handler.CreateListAuditEventsHandler(p_dbs),
+			md.GetMethodCheckMiddleware),
+	}
+}
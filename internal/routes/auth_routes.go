@@ -27,11 +27,31 @@ func SetupAuthRoutes(p_mux *http.ServeMux, p_cfg *config.ConfigData, p_dbs *data
 	var authGroup *GroupRoute = NewGroupRoute(p_mux, "/auth",
 		md.TimeRequestMiddleware, md.CORSMiddleware(p_cfg), md.LogRequestMiddleware)
 
+	// The ACS receives a form posted by the identity provider, from its own origin, so it is
+	// kept out of the CORS policy. The response is authenticated by its signature.
+	var samlGroup *GroupRoute = NewGroupRoute(p_mux, "/auth/saml",
+		md.TimeRequestMiddleware, md.LogRequestMiddleware)
+
 	return []*Route{
 		authGroup.NewRoute("/register", auth_handler.CreateRegisterHandler(p_dbs),
 			md.PostMethodCheckMiddleware),
 
 		authGroup.NewRoute("/change-password", auth_handler.CreateChangePwdHandler(p_dbs),
 			md.PostMethodCheckMiddleware),
+
+		authGroup.NewRoute("/oauth/{provider}/start", auth_handler.CreateOAuthStartHandler(p_dbs),
+			md.GetMethodCheckMiddleware),
+
+		authGroup.NewRoute("/oauth/{provider}/callback", auth_handler.CreateOAuthCallbackHandler(p_dbs),
+			md.GetMethodCheckMiddleware),
+
+		authGroup.NewRoute("/saml/{provider}/metadata", auth_handler.CreateSAMLMetadataHandler(p_dbs),
+			md.GetMethodCheckMiddleware),
+
+		authGroup.NewRoute("/saml/{provider}/start", auth_handler.CreateSAMLStartHandler(p_dbs),
+			md.GetMethodCheckMiddleware),
+
+		samlGroup.NewRoute("/{provider}/acs", auth_handler.CreateSAMLACSHandler(p_dbs),
+			md.PostMethodCheckMiddleware),
 	}
 }
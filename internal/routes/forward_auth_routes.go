@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"cerberus/internal/database"
+	forwardauth_handler "cerberus/internal/handlers/forwardauth"
+	md "cerberus/internal/middleware"
+	"cerberus/internal/tools/logger"
+	"cerberus/pkg/config"
+	"net/http"
+)
+
+// SetupForwardAuthRoutes configures the routes queried by reverse proxies to authenticate the
+// requests of the applications they protect.
+//
+// "/forward-auth" serves Traefik ForwardAuth and Nginx auth_request. "/forward-auth/envoy/" is
+// the Envoy ext_authz mode: Envoy forwards the method and the path of the original request
+// under its path_prefix, so every method and sub-path is accepted.
+//
+// Parameters:
+//   - p_mux: A pointer to the http.ServeMux to which the routes will be added.
+//   - p_cfg: A pointer to the ConfigData structure containing application configuration.
+//   - p_dbs: A pointer to the DataRefs structure containing database references.
+//
+// Returns:
+//   - []*Route: A slice of pointers to Route structures representing the configured routes.
+func SetupForwardAuthRoutes(p_mux *http.ServeMux, p_cfg *config.ConfigData, p_dbs *database.DataRefs) []*Route {
+	logger.Log("🚪 Setting up Forward Auth Routes", logger.INFO)
+
+	var forwardAuthGroup *GroupRoute = NewGroupRoute(p_mux, "/forward-auth",
+		md.TimeRequestMiddleware, md.LogRequestMiddleware)
+
+	return []*Route{
+		forwardAuthGroup.NewRoute("", forwardauth_handler.CreateForwardAuthHandler(p_dbs),
+			md.GetMethodCheckMiddleware),
+
+		forwardAuthGroup.NewRoute("/envoy/", forwardauth_handler.CreateForwardAuthHandler(p_dbs)),
+	}
+}
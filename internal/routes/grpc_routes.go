@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"cerberus/internal/database"
+	grpc_handler "cerberus/internal/handlers/grpc"
+	md "cerberus/internal/middleware"
+	"cerberus/internal/tools/logger"
+	"cerberus/pkg/config"
+	cerberusv1 "cerberus/pkg/proto/cerberus/v1"
+	"fmt"
+
+	"google.golang.org/grpc"
+)
+
+// SetupGRPCServer creates the gRPC server and registers its services.
+//
+// The interceptors mirror the middleware of the HTTP routes: every call is logged, Refresh
+// requires the "authorization" metadata, and Logout and Validate require an active session.
+//
+// Parameters:
+//   - p_cfg: A pointer to the ConfigData structure containing application configuration.
+//   - p_dbs: A pointer to the DataRefs structure containing database references.
+//   - p_opts: Additional server options, such as the transport credentials.
+//
+// Returns:
+//   - *grpc.Server: The configured gRPC server, not yet serving.
+func SetupGRPCServer(p_cfg *config.ConfigData, p_dbs *database.DataRefs, p_opts ...grpc.ServerOption) *grpc.Server {
+	logger.Log("📡 Setting up gRPC Services", logger.INFO)
+
+	var (
+		refresh  string = cerberusv1.AuthService_Refresh_FullMethodName
+		logout   string = cerberusv1.AuthService_Logout_FullMethodName
+		validate string = cerberusv1.AuthService_Validate_FullMethodName
+	)
+
+	opts := append([]grpc.ServerOption{
+		grpc.MaxRecvMsgSize(int(p_cfg.GetMaxBodyBytes())),
+		grpc.ChainUnaryInterceptor(
+			md.LogRequestInterceptor,
+			md.AuthenticationMetadataInterceptor(refresh, logout, validate),
+			md.SessionInterceptor(p_dbs, logout, validate),
+		),
+	}, p_opts...)
+
+	srv := grpc.NewServer(opts...)
+	cerberusv1.RegisterAuthServiceServer(srv, grpc_handler.NewAuthServer(p_dbs))
+
+	for name := range srv.GetServiceInfo() {
+		logger.Log(fmt.Sprintf("Service added: %s", name), logger.INFO)
+	}
+	return srv
+}
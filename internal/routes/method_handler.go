@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// MethodHandler dispatches requests on a single path to a different handler per HTTP method.
+// Requests using a method without a registered handler are answered with
+// "405 Method Not Allowed" and an "Allow" header listing the supported methods.
+//
+// Example usage:
+//
+//	meGroup.NewRoute("", MethodHandler{
+//	    http.MethodGet:   getHandler,
+//	    http.MethodPatch: patchHandler,
+//	}, otherMiddlewares...)
+type MethodHandler map[string]http.Handler
+
+// ServeHTTP calls the handler registered for the request method.
+func (mh MethodHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h, ok := mh[r.Method]; ok {
+		h.ServeHTTP(w, r)
+		return
+	}
+
+	methods := make([]string, 0, len(mh))
+	for m := range mh {
+		methods = append(methods, m)
+	}
+	slices.Sort(methods)
+
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+}
@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"cerberus/internal/database"
+	oauth2_handler "cerberus/internal/handlers/oauth2"
+	md "cerberus/internal/middleware"
+	"cerberus/internal/tools/logger"
+	"cerberus/pkg/config"
+	"net/http"
+)
+
+// SetupOAuth2Routes configures the OAuth 2.0 device authorization grant (RFC 8628) and token
+// exchange (RFC 8693).
+//
+// The "/oauth2" endpoints take no session: devices authenticate with their device code, and
+// token exchange with the tokens it is given. The "/device" route is
+// where users review and approve the requests of the devices: it requires a session, which
+// scoped tokens cannot stand in for.
+//
+// Parameters:
+//   - p_mux: A pointer to the http.ServeMux to which the routes will be added.
+//   - p_cfg: A pointer to the ConfigData structure containing application configuration.
+//   - p_dbs: A pointer to the DataRefs structure containing database references.
+//
+// Returns:
+//   - []*Route: A slice of pointers to Route structures representing the configured routes.
+func SetupOAuth2Routes(p_mux *http.ServeMux, p_cfg *config.ConfigData, p_dbs *database.DataRefs) []*Route {
+	logger.Log("📺 Setting up OAuth2 Routes", logger.INFO)
+
+	var oauth2Group *GroupRoute = NewGroupRoute(p_mux, "/oauth2",
+		md.TimeRequestMiddleware, md.CORSMiddleware(p_cfg), md.LogRequestMiddleware)
+
+	var deviceGroup *GroupRoute = NewGroupRoute(p_mux, "/device",
+		md.TimeRequestMiddleware, md.CORSMiddleware(p_cfg), md.LogRequestMiddleware,
+		md.SessionOnlyMiddleware, md.CSRFMiddleware(p_cfg),
+		md.SessionMiddleware(p_dbs), md.AuthenticationHeaderMiddleware(p_cfg))
+
+	return []*Route{
+		oauth2Group.NewRoute("/device_authorization", oauth2_handler.CreateDeviceAuthorizationHandler(p_dbs),
+			md.PostMethodCheckMiddleware),
+
+		oauth2Group.NewRoute("/token", oauth2_handler.CreateTokenHandler(p_dbs),
+			md.PostMethodCheckMiddleware),
+
+		deviceGroup.NewRoute("", MethodHandler{
+			http.MethodGet:  oauth2_handler.CreateGetDeviceRequestHandler(p_dbs),
+			http.MethodPost: oauth2_handler.CreateDeviceDecisionHandler(p_dbs),
+		}),
+	}
+}
@@ -0,0 +1,88 @@
+package routes
+
+import (
+	"cerberus/internal/database"
+	auth_handler "cerberus/internal/handlers/auth"
+	profile_handler "cerberus/internal/handlers/profile"
+	md "cerberus/internal/middleware"
+	"cerberus/internal/models"
+	"cerberus/internal/tools/logger"
+	"cerberus/pkg/config"
+	"net/http"
+)
+
+// SetupProfileRoutes configures the self-service routes of the authenticated user.
+//
+// Every route in the "/me" group requires a valid and active access token, checked by
+// SessionMiddleware after AuthenticationHeaderMiddleware extracted it. Browser sessions must also
+// send their CSRF token on state-changing requests, checked by CSRFMiddleware. Changing the
+// password or email, changing the phone number and deleting the account also require the user
+// to have authenticated within REAUTH_MAX_AGE, checked by RequireRecentAuth. The phone number is
+// a step-up factor itself, so setting it without a recent authentication would bypass the check.
+// Linking a SAML identity, which then logs in as the user, requires it too.
+//
+// Personal access tokens need the profile:read scope to read and profile:write to modify, and
+// cannot manage personal access tokens themselves.
+//
+// Parameters:
+//   - p_mux: A pointer to the http.ServeMux to which the routes will be added.
+//   - p_cfg: A pointer to the ConfigData structure containing application configuration.
+//   - p_dbs: A pointer to the DataRefs structure containing database references.
+//
+// Returns:
+//   - []*Route: A slice of pointers to Route structures representing the configured routes.
+func SetupProfileRoutes(p_mux *http.ServeMux, p_cfg *config.ConfigData, p_dbs *database.DataRefs) []*Route {
+	logger.Log("🪪 Setting up Profile Routes", logger.INFO)
+
+	var meGroup *GroupRoute = NewGroupRoute(p_mux, "/me",
+		md.TimeRequestMiddleware, md.CORSMiddleware(p_cfg), md.LogRequestMiddleware,
+		md.RequireScopeMiddleware(models.ScopeProfileRead, models.ScopeProfileWrite), md.CSRFMiddleware(p_cfg),
+		md.SessionMiddleware(p_dbs), md.AuthenticationHeaderMiddleware(p_cfg))
+
+	// Sensitive changes also require a recent authentication. The middleware wraps the handlers
+	// directly so that it runs after SessionMiddleware.
+	recentAuth := md.RequireRecentAuth(p_cfg.GetReauthMaxAge())
+
+	return []*Route{
+		meGroup.NewRoute("", MethodHandler{
+			http.MethodGet:    profile_handler.CreateGetProfileHandler(p_dbs),
+			http.MethodPatch:  profile_handler.CreateUpdateProfileHandler(p_dbs),
+			http.MethodDelete: recentAuth(profile_handler.CreateDeleteAccountHandler(p_dbs)),
+		}),
+
+		meGroup.NewRoute("/export", profile_handler.CreateExportHandler(p_dbs),
+			md.GetMethodCheckMiddleware),
+
+		meGroup.NewRoute("/password", recentAuth(profile_handler.CreateSetPasswordHandler(p_dbs)),
+			md.PostMethodCheckMiddleware),
+
+		meGroup.NewRoute("/email", recentAuth(profile_handler.CreateChangeEmailHandler(p_dbs)),
+			md.PostMethodCheckMiddleware),
+
+		meGroup.NewRoute("/email/confirm", profile_handler.CreateConfirmEmailHandler(p_dbs),
+			md.PostMethodCheckMiddleware),
+
+		meGroup.NewRoute("/phone", MethodHandler{
+			http.MethodPost:   recentAuth(profile_handler.CreateChangePhoneHandler(p_dbs)),
+			http.MethodDelete: recentAuth(profile_handler.CreateRemovePhoneHandler(p_dbs)),
+		}),
+
+		meGroup.NewRoute("/phone/confirm", recentAuth(profile_handler.CreateConfirmPhoneHandler(p_dbs)),
+			md.PostMethodCheckMiddleware),
+
+		meGroup.NewRoute("/tokens", md.SessionOnlyMiddleware(MethodHandler{
+			http.MethodGet:  profile_handler.CreateListPersonalTokensHandler(p_dbs),
+			http.MethodPost: profile_handler.CreateAddPersonalTokenHandler(p_dbs),
+		})),
+
+		meGroup.NewRoute("/tokens/{id}", md.SessionOnlyMiddleware(MethodHandler{
+			http.MethodDelete: profile_handler.CreateRevokePersonalTokenHandler(p_dbs),
+		})),
+
+		meGroup.NewRoute("/identities/saml/{provider}", md.SessionOnlyMiddleware(
+			recentAuth(auth_handler.CreateSAMLLinkHandler(p_dbs))), md.PostMethodCheckMiddleware),
+
+		meGroup.NewRoute("/security-events", profile_handler.CreateSecurityEventsHandler(p_dbs),
+			md.GetMethodCheckMiddleware),
+	}
+}
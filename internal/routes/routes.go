@@ -23,6 +23,11 @@ func SetupRoutes(p_mux *http.ServeMux, p_cfg *config.ConfigData, p_dbs *database
 	var routes []*Route = make([]*Route, 0)
 	routes = append(routes, SetupAuthRoutes(p_mux, p_cfg, p_dbs)...)
 	routes = append(routes, SetupSessionRoutes(p_mux, p_cfg, p_dbs)...)
+	routes = append(routes, SetupProfileRoutes(p_mux, p_cfg, p_dbs)...)
+	routes = append(routes, SetupAdminRoutes(p_mux, p_cfg, p_dbs)...)
+	routes = append(routes, SetupWellKnownRoutes(p_mux, p_cfg, p_dbs)...)
+	routes = append(routes, SetupForwardAuthRoutes(p_mux, p_cfg, p_dbs)...)
+	routes = append(routes, SetupOAuth2Routes(p_mux, p_cfg, p_dbs)...)
 
 	listRoutes(routes)
 }
@@ -32,13 +32,36 @@ func SetupSessionRoutes(p_mux *http.ServeMux, p_cfg *config.ConfigData, p_dgs *d
 		sessionGroup.NewRoute("/login", session_handler.CreateLoginHandler(p_dgs),
 			md.PostMethodCheckMiddleware),
 
+		sessionGroup.NewRoute("/magic-link", session_handler.CreateMagicLinkHandler(p_dgs),
+			md.PostMethodCheckMiddleware),
+
+		sessionGroup.NewRoute("/magic-link/consume", session_handler.CreateConsumeMagicLinkHandler(p_dgs),
+			md.PostMethodCheckMiddleware),
+
+		sessionGroup.NewRoute("/otp", session_handler.CreateOTPLoginHandler(p_dgs),
+			md.PostMethodCheckMiddleware),
+
+		sessionGroup.NewRoute("/otp/verify", session_handler.CreateOTPVerifyHandler(p_dgs),
+			md.PostMethodCheckMiddleware),
+
+		sessionGroup.NewRoute("/reauthenticate", session_handler.CreateReauthenticateHandler(p_dgs),
+			md.PostMethodCheckMiddleware, md.SessionOnlyMiddleware, md.CSRFMiddleware(p_cfg),
+			md.SessionMiddleware(p_dgs), md.AuthenticationHeaderMiddleware(p_cfg)),
+
+		sessionGroup.NewRoute("/reauthenticate/otp", session_handler.CreateStepUpChallengeHandler(p_dgs),
+			md.PostMethodCheckMiddleware, md.SessionOnlyMiddleware, md.CSRFMiddleware(p_cfg),
+			md.SessionMiddleware(p_dgs), md.AuthenticationHeaderMiddleware(p_cfg)),
+
 		sessionGroup.NewRoute("/logout", session_handler.CreateLogoutHandler(p_dgs),
-			md.PostMethodCheckMiddleware, md.AuthenticationHeaderMiddleware),
+			md.PostMethodCheckMiddleware, md.CSRFMiddleware(p_cfg), md.AuthenticationHeaderMiddleware(p_cfg)),
 
 		sessionGroup.NewRoute("/validate", session_handler.CreateValidateHandler(p_dgs),
-			md.PostMethodCheckMiddleware, md.AuthenticationHeaderMiddleware),
+			md.PostMethodCheckMiddleware, md.AuthenticationHeaderMiddleware(p_cfg)),
 
 		sessionGroup.NewRoute("/refresh", session_handler.CreateRefreshHandler(p_dgs),
-			md.PostMethodCheckMiddleware, md.AuthenticationHeaderMiddleware),
+			md.PostMethodCheckMiddleware, md.RefreshCookieMiddleware(p_cfg)),
+
+		sessionGroup.NewRoute("/revocations", session_handler.CreateRevocationsHandler(p_dgs),
+			md.GetMethodCheckMiddleware, md.StaticKeyMiddleware(p_cfg.GetRevocationFeedKey())),
 	}
 }
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"cerberus/internal/database"
+	wellknown_handler "cerberus/internal/handlers/wellknown"
+	md "cerberus/internal/middleware"
+	"cerberus/internal/tools/logger"
+	"cerberus/pkg/config"
+	"net/http"
+)
+
+// SetupWellKnownRoutes configures the public discovery routes used by other services.
+//
+// Parameters:
+//   - p_mux: A pointer to the http.ServeMux to which the routes will be added.
+//   - p_cfg: A pointer to the ConfigData structure containing application configuration.
+//   - p_dbs: A pointer to the DataRefs structure containing database references.
+//
+// Returns:
+//   - []*Route: A slice of pointers to Route structures representing the configured routes.
+func SetupWellKnownRoutes(p_mux *http.ServeMux, p_cfg *config.ConfigData, p_dbs *database.DataRefs) []*Route {
+	logger.Log("🔭 Setting up Well-Known Routes", logger.INFO)
+
+	var wellKnownGroup *GroupRoute = NewGroupRoute(p_mux, "/.well-known",
+		md.TimeRequestMiddleware, md.CORSMiddleware(p_cfg), md.LogRequestMiddleware)
+
+	return []*Route{
+		wellKnownGroup.NewRoute("/jwks.json", wellknown_handler.CreateJWKSHandler(p_dbs),
+			md.GetMethodCheckMiddleware),
+	}
+}
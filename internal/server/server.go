@@ -3,11 +3,19 @@ package server
 import (
 	"cerberus/internal/database"
 	"cerberus/internal/routes"
+	"cerberus/internal/services"
 	logger "cerberus/internal/tools/logger"
 	"cerberus/pkg/config"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 // Start initializes and runs the server application.
@@ -42,12 +50,75 @@ func Start() {
 		return
 	}
 
+	// Start background workers
+	services.StartAccountPurgeWorker(dbs, time.Hour)
+	services.StartWebhookWorker(dbs, 5*time.Second)
+
 	// Define routes
 	routes.SetupRoutes(mux, cfg, dbs)
 
+	// Start the gRPC server on its own port
+	if addr := cfg.GetGRPCAddressStr(); addr != "" {
+		go startGRPCServer(addr, cfg, dbs)
+	}
+
 	logger.Log(fmt.Sprintf("🍭 Starting server at %s", cfg.GetAddressStr()), logger.INFO)
 	// Start the HTTP server
 	if err := http.ListenAndServe(cfg.GetAddressStr(), mux); err != nil {
 		logger.Log(fmt.Sprintf("💥 Error during serving - %s", err), logger.INFO)
 	}
 }
+
+// region Private
+
+// startGRPCServer serves the gRPC services on the given address until the listener fails.
+// A failure is logged but does not stop the HTTP server.
+func startGRPCServer(p_addr string, p_cfg *config.ConfigData, p_dbs *database.DataRefs) {
+	creds, err := grpcCredentials(p_addr, p_cfg)
+	if err != nil {
+		logger.Log(fmt.Sprintf("💥 Refusing to serve gRPC at %s - %s", p_addr, err), logger.ERROR)
+		return
+	}
+
+	lis, err := net.Listen("tcp", p_addr)
+	if err != nil {
+		logger.Log(fmt.Sprintf("💥 Failed to listen for gRPC at %s - %s", p_addr, err), logger.ERROR)
+		return
+	}
+
+	logger.Log(fmt.Sprintf("🍭 Starting gRPC server at %s", p_addr), logger.INFO)
+	if err := routes.SetupGRPCServer(p_cfg, p_dbs, creds...).Serve(lis); err != nil {
+		logger.Log(fmt.Sprintf("💥 Error during gRPC serving - %s", err), logger.ERROR)
+	}
+}
+
+// grpcCredentials returns the server options securing the gRPC transport.
+//
+// When GRPC_TLS_CERT_FILE and GRPC_TLS_KEY_FILE are set, the server uses TLS with this key
+// pair. Otherwise plaintext is only allowed on a loopback address, where a local proxy is
+// expected to terminate TLS: tokens and passwords must not cross the network in clear.
+func grpcCredentials(p_addr string, p_cfg *config.ConfigData) ([]grpc.ServerOption, error) {
+	if p_cfg.GRPCTLSCertFile != "" || p_cfg.GRPCTLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(p_cfg.GRPCTLSCertFile, p_cfg.GRPCTLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the TLS key pair: %w", err)
+		}
+		tlsCfg := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+		return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsCfg))}, nil
+	}
+
+	host, _, err := net.SplitHostPort(p_addr)
+	if err != nil {
+		return nil, err
+	}
+	if host != "localhost" {
+		if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+			return nil, errors.New("TLS is required on a non-loopback address, set GRPC_TLS_CERT_FILE and GRPC_TLS_KEY_FILE")
+		}
+	}
+
+	logger.Log("⚠️ gRPC server uses plaintext on a loopback address", logger.WARN)
+	return nil, nil
+}
+
+// endregion Private
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"cerberus/pkg/config"
+	"testing"
+)
+
+func TestGRPCCredentialsAllowsPlaintextOnLoopback(t *testing.T) {
+	for _, addr := range []string{"localhost:9191", "127.0.0.1:9191", "[::1]:9191"} {
+		opts, err := grpcCredentials(addr, &config.ConfigData{})
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", addr, err)
+		}
+		if len(opts) != 0 {
+			t.Fatalf("%s: expected no transport credentials", addr)
+		}
+	}
+}
+
+func TestGRPCCredentialsRefusesPlaintextElsewhere(t *testing.T) {
+	for _, addr := range []string{":9191", "0.0.0.0:9191", "10.0.0.5:9191", "auth.example.com:9191"} {
+		if _, err := grpcCredentials(addr, &config.ConfigData{}); err == nil {
+			t.Fatalf("%s: expected plaintext to be refused", addr)
+		}
+	}
+}
+
+func TestGRPCCredentialsFailsOnMissingKeyPair(t *testing.T) {
+	cfg := &config.ConfigData{GRPCTLSCertFile: "missing.crt", GRPCTLSKeyFile: "missing.key"}
+	if _, err := grpcCredentials("localhost:9191", cfg); err == nil {
+		t.Fatal("expected an error for a missing key pair")
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/audit_dto"
+	"cerberus/internal/dto/profile_dto"
+	"cerberus/internal/models"
+	"cerberus/internal/repository"
+	"cerberus/internal/tools/logger"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// region Public
+
+// DeleteAccount soft-deletes the account of a user after re-confirming their password.
+// Accounts without a local password, authenticated by a directory or identity provider, have
+// no password to confirm: the route is guarded by RequireRecentAuth, so the user proved their
+// identity moments ago.
+//
+// All session tokens of the user are revoked immediately. The account data is kept for the
+// configured grace period and then permanently removed by PurgeDeletedAccounts.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database, mailer and configuration references.
+//   - p_usrId: The unique ID (string) of the user to delete.
+//   - p_password: The current password of the user, ignored for accounts without one.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - time.Time: When the account data will be permanently removed.
+//   - error: ErrInvalidPassword if the password does not match, or the database error.
+func DeleteAccount(p_db *database.DataRefs, p_usrId string, p_password string, p_info RequestInfo) (_ time.Time, err error) {
+	defer func() { auditResult(p_db.Postgres, p_info, models.AuditAccountDeletion, p_usrId, err, nil) }()
+
+	usr, err := GetUserById(p_db.Postgres, p_usrId)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if usr.Password != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(usr.Password), []byte(p_password)); err != nil {
+			return time.Time{}, ErrInvalidPassword
+		}
+	}
+
+	if err := repository.SoftDeleteUser(p_db.Postgres, usr); err != nil {
+		logger.Log("Failed to delete user - "+err.Error(), logger.ERROR)
+		return time.Time{}, err
+	}
+
+	RevokeAllSessionTokensToUser(p_db, p_usrId, "account_deletion")
+	EmitEvent(p_db, models.EventUserDeleted, map[string]string{"user_id": p_usrId})
+
+	purgeAt := time.Now().Add(p_db.ConfigData.GetAccountDeletionGrace())
+	body := fmt.Sprintf("Hello %s,\n\nYour account was deleted. All of its data will be permanently "+
+		"removed on %s.", usr.Name, purgeAt.Format(time.RFC1123))
+	if err := p_db.Mailer.Send(usr.Email, "Your account was deleted", body); err != nil {
+		logger.Log("Failed to send account deletion notice - "+err.Error(), logger.WARN)
+	}
+
+	return purgeAt, nil
+}
+
+// ExportUserData gathers every piece of data stored about a user.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database references.
+//   - p_usrId: The unique ID (string) of the user.
+//
+// Returns:
+//   - *profile_dto.ExportResponse: The exported data.
+//   - error: An error if the user cannot be loaded, nil otherwise.
+func ExportUserData(p_db *database.DataRefs, p_usrId string) (*profile_dto.ExportResponse, error) {
+	usr, err := GetUserById(p_db.Postgres, p_usrId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessions := make([]profile_dto.SessionExport, 0, 2)
+	if ttl, err := repository.GetJWTTokenTTL(p_db.Redis, p_usrId); err == nil {
+		sessions = append(sessions, profile_dto.SessionExport{Type: "access", ExpiresAt: now.Add(ttl)})
+	}
+	if ttl, err := repository.GetRefreshTokenTTL(p_db.Redis, p_usrId); err == nil {
+		sessions = append(sessions, profile_dto.SessionExport{Type: "refresh", ExpiresAt: now.Add(ttl)})
+	}
+
+	tkns, err := repository.ListPersonalTokens(p_db.Postgres, p_usrId)
+	if err != nil {
+		return nil, err
+	}
+
+	personalTokens := make([]profile_dto.PersonalTokenResponse, 0, len(tkns))
+	for i := range tkns {
+		personalTokens = append(personalTokens, profile_dto.NewPersonalTokenResponse(&tkns[i]))
+	}
+
+	// A negative limit disables the limit, the export holds the whole history
+	events, _, err := repository.ListAuditEvents(p_db.Postgres, &repository.AuditFilter{SubjectID: p_usrId}, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	securityEvents := make([]audit_dto.AuditEventResponse, 0, len(events))
+	for i := range events {
+		securityEvents = append(securityEvents, audit_dto.NewAuditEventResponse(&events[i]))
+	}
+
+	return &profile_dto.ExportResponse{
+		ExportedAt:     now,
+		Profile:        profile_dto.NewProfileResponse(usr),
+		Sessions:       sessions,
+		PersonalTokens: personalTokens,
+		SecurityEvents: securityEvents,
+	}, nil
+}
+
+// PurgeDeletedAccounts permanently removes the accounts whose deletion grace period is over.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//
+// Returns:
+//   - int64: The number of removed accounts.
+//   - error: An error if the delete operation fails, nil otherwise.
+func PurgeDeletedAccounts(p_db *database.DataRefs) (int64, error) {
+	cutoff := time.Now().Add(-p_db.ConfigData.GetAccountDeletionGrace())
+	return repository.PurgeDeletedUsers(p_db.Postgres, cutoff)
+}
+
+// StartAccountPurgeWorker runs PurgeDeletedAccounts in the background every p_interval.
+// Failures are logged and retried on the next tick.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_interval: The time between two purge runs.
+func StartAccountPurgeWorker(p_db *database.DataRefs, p_interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(p_interval)
+		defer ticker.Stop()
+
+		for {
+			n, err := PurgeDeletedAccounts(p_db)
+			if err != nil {
+				logger.Log("Failed to purge deleted accounts - "+err.Error(), logger.ERROR)
+			} else if n > 0 {
+				logger.Log(fmt.Sprintf("🧹 Purged %d deleted accounts", n), logger.INFO)
+			}
+
+			<-ticker.C
+		}
+	}()
+}
+
+// endregion Public
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/models"
+	"cerberus/internal/repository"
+	"cerberus/internal/tools/logger"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrUserNotFound is returned by the administration functions when the target user does not exist.
+var ErrUserNotFound = errors.New("user not found")
+
+// region Public
+
+// ListUsers returns a page of users, optionally filtered by email or name.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection.
+//   - p_page: The 1-based page number.
+//   - p_pageSize: The number of users per page.
+//   - p_search: An optional case-insensitive filter matched against the email and the name.
+//
+// Returns:
+//   - []models.User: The users of the requested page.
+//   - int64: The total number of users matching the filter.
+//   - error: An error if the query fails, nil otherwise.
+func ListUsers(p_db *gorm.DB, p_page int, p_pageSize int, p_search string) ([]models.User, int64, error) {
+	users, total, err := repository.ListUsers(p_db, (p_page-1)*p_pageSize, p_pageSize, p_search)
+	if err != nil {
+		logger.Log("Failed to list users - "+err.Error(), logger.ERROR)
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// GetUserForAdmin retrieves a user by ID, mapping a malformed ID or a missing row to ErrUserNotFound.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection.
+//   - p_usrId: The unique ID (string) of the user.
+//
+// Returns:
+//   - *models.User: The user if found.
+//   - error: ErrUserNotFound if the user does not exist, or the database error.
+func GetUserForAdmin(p_db *gorm.DB, p_usrId string) (*models.User, error) {
+	if _, err := uuid.Parse(p_usrId); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	usr, err := repository.FindUserById(p_db, p_usrId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	} else if err != nil {
+		logger.Log("Failed to fetch user - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+
+	return usr, nil
+}
+
+// ForceLogout revokes every session and personal access token of a user.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database references.
+//   - p_usrId: The unique ID (string) of the target user.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - error: ErrUserNotFound if the user does not exist, or the database error.
+func ForceLogout(p_db *database.DataRefs, p_usrId string, p_info RequestInfo) (err error) {
+	details := map[string]string{}
+	defer func() { auditResult(p_db.Postgres, p_info, models.AuditAdminForceLogout, p_usrId, err, details) }()
+
+	if _, err := GetUserForAdmin(p_db.Postgres, p_usrId); err != nil {
+		return err
+	}
+
+	RevokeAllSessionTokensToUser(p_db, p_usrId, "force_logout")
+	return revokeUserPersonalTokens(p_db, p_usrId, details)
+}
+
+// ForcePasswordReset requires a user to change their password before logging in again.
+// Every session and personal access token of the user is revoked and the user is notified by
+// email.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and mailer references.
+//   - p_usrId: The unique ID (string) of the target user.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - error: ErrUserNotFound if the user does not exist, or the database error.
+func ForcePasswordReset(p_db *database.DataRefs, p_usrId string, p_info RequestInfo) (err error) {
+	details := map[string]string{}
+	defer func() { auditResult(p_db.Postgres, p_info, models.AuditAdminPasswordReset, p_usrId, err, details) }()
+
+	usr, err := GetUserForAdmin(p_db.Postgres, p_usrId)
+	if err != nil {
+		return err
+	}
+
+	if err := repository.SetPasswordResetRequired(p_db.Postgres, usr, true); err != nil {
+		logger.Log("Failed to flag password reset - "+err.Error(), logger.ERROR)
+		return err
+	}
+
+	RevokeAllSessionTokensToUser(p_db, p_usrId, "password_reset")
+	if err := revokeUserPersonalTokens(p_db, p_usrId, details); err != nil {
+		return err
+	}
+
+	body := "Hello " + usr.Name + ",\n\nAn administrator requires you to change your password. " +
+		"You will not be able to log in until you do so."
+	if err := p_db.Mailer.Send(usr.Email, "Password change required", body); err != nil {
+		logger.Log("Failed to send password reset notice - "+err.Error(), logger.WARN)
+	}
+
+	return nil
+}
+
+// AdminDeleteUser soft-deletes a user account and revokes every session of the user.
+// The data is permanently removed once the deletion grace period is over.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_usrId: The unique ID (string) of the target user.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - time.Time: When the account data will be permanently removed.
+//   - error: ErrUserNotFound if the user does not exist, or the database error.
+func AdminDeleteUser(p_db *database.DataRefs, p_usrId string, p_info RequestInfo) (_ time.Time, err error) {
+	defer func() { auditResult(p_db.Postgres, p_info, models.AuditAdminUserDeletion, p_usrId, err, nil) }()
+
+	usr, err := GetUserForAdmin(p_db.Postgres, p_usrId)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := repository.SoftDeleteUser(p_db.Postgres, usr); err != nil {
+		logger.Log("Failed to delete user - "+err.Error(), logger.ERROR)
+		return time.Time{}, err
+	}
+
+	RevokeAllSessionTokensToUser(p_db, p_usrId, "account_deletion")
+	EmitEvent(p_db, models.EventUserDeleted, map[string]string{"user_id": p_usrId})
+	return time.Now().Add(p_db.ConfigData.GetAccountDeletionGrace()), nil
+}
+
+// SetUserRole grants or revokes a role for the user with the given email address.
+// It is used to bootstrap the first administrators from the command line.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection.
+//   - p_email: The email address of the target user.
+//   - p_role: The role to grant or revoke (e.g., models.RoleAdmin).
+//   - p_granted: true to grant the role, false to revoke it.
+//
+// Returns:
+//   - *models.User: The updated user.
+//   - error: ErrUserNotFound if no user has this email, or the database error.
+func SetUserRole(p_db *gorm.DB, p_email string, p_role string, p_granted bool) (*models.User, error) {
+	usr, err := repository.FindUserByEmail(p_db, strings.TrimSpace(p_email))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	usr.Roles = slices.DeleteFunc(usr.Roles, func(r string) bool { return r == p_role })
+	if p_granted {
+		usr.Roles = append(usr.Roles, p_role)
+	}
+
+	if err := repository.UpdateRoles(p_db, usr); err != nil {
+		logger.Log("Failed to update roles - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+
+	return usr, nil
+}
+
+// endregion Public
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"cerberus/internal/models"
+	"cerberus/internal/repository"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/validator"
+	"cerberus/pkg/config"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RequestInfo carries the request metadata recorded with audit events.
+//
+// Fields:
+//   - IP: The IP address of the client.
+//   - UserAgent: The User-Agent header of the client.
+//   - ActorID: The ID of the authenticated user performing the request, empty if anonymous.
+type RequestInfo struct {
+	IP        string
+	UserAgent string
+	ActorID   string
+}
+
+// region Public
+
+// NewRequestInfo extracts the audit metadata of an HTTP request.
+//
+// The client IP is taken from the connection, unless TrustProxyHeaders is enabled, in which
+// case the first address of the X-Forwarded-For header is used when present.
+//
+// Parameters:
+//   - p_cfg: A pointer to the ConfigData structure containing application configuration.
+//   - r: The incoming *http.Request.
+//
+// Returns:
+//   - RequestInfo: The request metadata, without actor.
+func NewRequestInfo(p_cfg *config.ConfigData, r *http.Request) RequestInfo {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	if p_cfg.TrustProxyHeaders {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			first, _, _ := strings.Cut(fwd, ",")
+			ip = strings.TrimSpace(first)
+		}
+	}
+
+	return RequestInfo{
+		IP:        ip,
+		UserAgent: r.UserAgent(),
+	}
+}
+
+// RecordAuditEvent appends an event to the audit log.
+//
+// Audit failures never interrupt the audited operation: errors are logged and swallowed.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection.
+//   - p_info: The metadata of the request that triggered the event.
+//   - p_type: The kind of event (one of the models.Audit* constants).
+//   - p_targetId: The ID of the affected user, or "" if unknown.
+//   - p_outcome: models.OutcomeSuccess or models.OutcomeFailure.
+//   - p_details: Optional free-form information about the event.
+func RecordAuditEvent(p_db *gorm.DB, p_info RequestInfo, p_type string, p_targetId string, p_outcome string,
+	p_details map[string]string) {
+	event := &models.AuditEvent{
+		Type:      p_type,
+		ActorID:   parseOptionalUUID(p_info.ActorID),
+		TargetID:  parseOptionalUUID(p_targetId),
+		IP:        p_info.IP,
+		UserAgent: p_info.UserAgent,
+		Outcome:   p_outcome,
+		Details:   p_details,
+	}
+
+	if err := repository.CreateAuditEvent(p_db, event); err != nil {
+		logger.Log(fmt.Sprintf("Failed to record audit event %s - %s", p_type, err.Error()), logger.ERROR)
+	}
+}
+
+// ListAuditEvents returns a page of audit events matching a filter.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection.
+//   - p_filter: The criteria the events must match.
+//   - p_page: The 1-based page number.
+//   - p_pageSize: The number of events per page.
+//
+// Returns:
+//   - []models.AuditEvent: The events of the requested page.
+//   - int64: The total number of events matching the filter.
+//   - error: An error if the query fails, nil otherwise.
+func ListAuditEvents(p_db *gorm.DB, p_filter *repository.AuditFilter, p_page int, p_pageSize int) ([]models.AuditEvent, int64, error) {
+	events, total, err := repository.ListAuditEvents(p_db, p_filter, (p_page-1)*p_pageSize, p_pageSize)
+	if err != nil {
+		logger.Log("Failed to list audit events - "+err.Error(), logger.ERROR)
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+// ParseAuditFilter builds an audit filter from the query parameters of a request.
+//
+// Supported parameters are "type", "actor_id", "target_id" and "outcome", plus "since" and
+// "until" as RFC 3339 timestamps.
+//
+// Parameters:
+//   - p_query: The query parameters of the request.
+//
+// Returns:
+//   - *repository.AuditFilter: The parsed filter.
+//   - error: A validator.ValidationErrors listing the malformed parameters, or nil.
+func ParseAuditFilter(p_query url.Values) (*repository.AuditFilter, error) {
+	var errs validator.ValidationErrors
+	filter := &repository.AuditFilter{
+		Type:    p_query.Get("type"),
+		Outcome: p_query.Get("outcome"),
+	}
+
+	for _, field := range []struct {
+		name string
+		dst  *string
+	}{{"actor_id", &filter.ActorID}, {"target_id", &filter.TargetID}} {
+		value := p_query.Get(field.name)
+		if value == "" {
+			continue
+		}
+		if _, err := uuid.Parse(value); err != nil {
+			errs = append(errs, validator.FieldError{Field: field.name, Rule: "uuid",
+				Message: field.name + " must be a valid UUID"})
+			continue
+		}
+		*field.dst = value
+	}
+
+	for _, field := range []struct {
+		name string
+		dst  **time.Time
+	}{{"since", &filter.Since}, {"until", &filter.Until}} {
+		value := p_query.Get(field.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			errs = append(errs, validator.FieldError{Field: field.name, Rule: "rfc3339",
+				Message: field.name + " must be an RFC 3339 timestamp"})
+			continue
+		}
+		*field.dst = &t
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return filter, nil
+}
+
+// endregion Public
+// region Private
+
+// auditResult records an event whose outcome is derived from the error returned by the
+// audited operation. On failure the error message is added to the details.
+func auditResult(p_db *gorm.DB, p_info RequestInfo, p_type string, p_targetId string, p_err error,
+	p_details map[string]string) {
+	outcome := models.OutcomeSuccess
+	if p_err != nil {
+		outcome = models.OutcomeFailure
+
+		if p_details == nil {
+			p_details = make(map[string]string)
+		}
+		p_details["error"] = p_err.Error()
+	}
+
+	RecordAuditEvent(p_db, p_info, p_type, p_targetId, outcome, p_details)
+}
+
+// parseOptionalUUID parses a user ID, returning nil for empty or malformed values.
+func parseOptionalUUID(p_id string) *uuid.UUID {
+	id, err := uuid.Parse(p_id)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+// endregion Private
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/models"
+	"cerberus/internal/repository"
+	"cerberus/internal/tools/directory"
+	"cerberus/internal/tools/logger"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	AuthBackendLocal string = "local" // AuthBackendLocal checks the passwords stored in the users table.
+	AuthBackendLDAP  string = "ldap"  // AuthBackendLDAP checks passwords against the configured LDAP directory.
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")                  // ErrInvalidCredentials is returned when no backend accepts the credentials.
+	ErrNotHandled         = errors.New("user unknown to the authentication backend") // ErrNotHandled is returned by an Authenticator that does not know the user, so the next one is tried.
+)
+
+// Authenticator is a backend checking the credentials of a user. AuthenticateUser tries the
+// backends listed in AUTH_BACKENDS in order, until one of them knows the user.
+type Authenticator interface {
+	// Authenticate checks the password of a user and returns their local account.
+	//
+	// Parameters:
+	//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+	//   - p_email: The canonical email address the user logs in with.
+	//   - p_password: The password of the user.
+	//   - p_info: The request metadata recorded in the audit log.
+	//
+	// Returns:
+	//   - *models.User: The user, also set along with an error when it is known, so the
+	//     failure can be audited.
+	//   - error: ErrNotHandled if the backend does not know the user, ErrInvalidCredentials if
+	//     the password is wrong, or the error of the backend.
+	Authenticate(p_db *database.DataRefs, p_email string, p_password string, p_info RequestInfo) (*models.User, error)
+}
+
+// AuthenticatorFunc adapts a function to the Authenticator interface.
+type AuthenticatorFunc func(p_db *database.DataRefs, p_email string, p_password string, p_info RequestInfo) (*models.User, error)
+
+// authenticators holds the available backends, by name.
+var authenticators = map[string]Authenticator{
+	AuthBackendLocal: AuthenticatorFunc(authenticateLocal),
+	AuthBackendLDAP:  AuthenticatorFunc(authenticateDirectory),
+}
+
+// region Public
+
+// Authenticate calls f(p_db, p_email, p_password, p_info).
+func (f AuthenticatorFunc) Authenticate(p_db *database.DataRefs, p_email string, p_password string,
+	p_info RequestInfo) (*models.User, error) {
+	return f(p_db, p_email, p_password, p_info)
+}
+
+// RegisterAuthenticator makes a backend available under the given name, replacing any backend
+// with the same name. It must be called before the server starts, typically from an init function.
+//
+// Parameters:
+//   - p_name: The name of the backend in AUTH_BACKENDS.
+//   - p_auth: The backend.
+func RegisterAuthenticator(p_name string, p_auth Authenticator) {
+	authenticators[p_name] = p_auth
+}
+
+// endregion Public
+
+// region Private
+
+// authenticateLocal checks the password against the bcrypt hash of the local account.
+// Accounts without a password, created by an external backend or identity provider, are not
+// handled so the next backend is tried.
+func authenticateLocal(p_db *database.DataRefs, p_email string, p_password string, _ RequestInfo) (*models.User, error) {
+	usr, err := repository.FindUserByEmail(p_db.Postgres, p_email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotHandled
+	} else if err != nil {
+		logger.Log("Failed to find user - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+
+	if usr.Password == "" {
+		return usr, ErrNotHandled
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(usr.Password), []byte(p_password)); err != nil {
+		logger.Log("Invalid credentials - "+err.Error(), logger.ERROR)
+		return usr, ErrInvalidCredentials
+	}
+
+	return usr, nil
+}
+
+// authenticateDirectory binds to the LDAP directory as the user, then provisions or updates
+// their local account. Users missing from the directory are not handled.
+func authenticateDirectory(p_db *database.DataRefs, p_email string, p_password string,
+	p_info RequestInfo) (*models.User, error) {
+	client := directory.NewClient(&p_db.ConfigData.LDAPData)
+	if client == nil {
+		logger.Log("LDAP backend enabled without LDAP_URL", logger.WARN)
+		return nil, ErrNotHandled
+	}
+
+	entry, err := client.Authenticate(p_email, p_password)
+	switch {
+	case errors.Is(err, directory.ErrUserNotFound):
+		return nil, ErrNotHandled
+	case errors.Is(err, directory.ErrInvalidCredentials):
+		return nil, ErrInvalidCredentials
+	case err != nil:
+		logger.Log("LDAP authentication failed - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+
+	return provisionDirectoryUser(p_db, entry, client.ManagedRoles(), p_info)
+}
+
+// provisionDirectoryUser returns the local account of a directory entry, creating it on first
+// login. Accounts are linked to their entry through an identity holding its DN, or found by email
+// address. The name is synchronized, and the roles of the group-to-role mapping are granted or
+// revoked to match the groups of the entry; other roles are left untouched.
+func provisionDirectoryUser(p_db *database.DataRefs, p_entry *directory.Entry, p_managed []string,
+	p_info RequestInfo) (*models.User, error) {
+	mail, err := NormalizeEmail(p_db, p_entry.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	var usr *models.User
+	link, err := repository.FindIdentity(p_db.Postgres, AuthBackendLDAP, p_entry.DN)
+	if err == nil {
+		if usr, err = GetUserById(p_db.Postgres, link.UserID.String()); err != nil {
+			return nil, err
+		}
+		if err := repository.TouchIdentity(p_db.Postgres, link, p_entry.Email); err != nil {
+			logger.Log("Failed to update identity - "+err.Error(), logger.WARN)
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	} else {
+		if usr, err = repository.FindUserByEmail(p_db.Postgres, mail); errors.Is(err, gorm.ErrRecordNotFound) {
+			usr, err = createFederatedUser(p_db, mail, p_entry.Name)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		link = &models.Identity{UserID: usr.ID, Provider: AuthBackendLDAP, Subject: p_entry.DN, Email: p_entry.Email,
+			LastLoginAt: time.Now()}
+		if err := repository.CreateIdentity(p_db.Postgres, link); err != nil {
+			logger.Log("Failed to link identity - "+err.Error(), logger.ERROR)
+			return nil, err
+		}
+
+		p_info.ActorID = usr.ID.String()
+		RecordAuditEvent(p_db.Postgres, p_info, models.AuditIdentityLink,
+			usr.ID.String(), models.OutcomeSuccess, map[string]string{"provider": AuthBackendLDAP, "subject": p_entry.DN})
+	}
+
+	if p_entry.Name != "" && p_entry.Name != usr.Name {
+		usr.Name = p_entry.Name
+		if err := repository.UpdateProfile(p_db.Postgres, usr); err != nil {
+			logger.Log("Failed to update name - "+err.Error(), logger.WARN)
+		}
+	}
+
+	roles := slices.DeleteFunc(slices.Clone(usr.Roles), func(r string) bool { return slices.Contains(p_managed, r) })
+	roles = append(roles, p_entry.Roles...)
+	if !sameRoles(usr.Roles, roles) {
+		logger.Log(fmt.Sprintf("Directory roles of %s changed from %v to %v", usr.ID, usr.Roles, roles), logger.INFO)
+		usr.Roles = roles
+		if err := repository.UpdateRoles(p_db.Postgres, usr); err != nil {
+			logger.Log("Failed to update roles - "+err.Error(), logger.ERROR)
+			return nil, err
+		}
+	}
+
+	return usr, nil
+}
+
+// sameRoles reports whether two role lists hold the same roles, in any order.
+func sameRoles(p_a []string, p_b []string) bool {
+	a, b := slices.Clone(p_a), slices.Clone(p_b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+// endregion Private
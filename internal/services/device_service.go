@@ -0,0 +1,323 @@
+package services
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/oauth2_dto"
+	"cerberus/internal/models"
+	"cerberus/internal/repository"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/random"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DeviceCodeGrantType string = "urn:ietf:params:oauth:grant-type:device_code" // DeviceCodeGrantType is the grant_type polling for the token of a device authorization request.
+
+	DeviceStatusPending  string = "pending"  // DeviceStatusPending marks requests waiting for the decision of the user.
+	DeviceStatusApproved string = "approved" // DeviceStatusApproved marks requests approved by the user.
+	DeviceStatusDenied   string = "denied"   // DeviceStatusDenied marks requests denied by the user.
+
+	deviceUserCodeAlphabet string = "BCDFGHJKLMNPQRSTVWXZ" // Consonants only: no vowels to spell words, no look-alike digits.
+	deviceUserCodeLength   int    = 8                      // Characters of a user code, shown as XXXX-XXXX.
+	deviceCodeSize         int    = 32                     // Random bytes of a device code.
+	deviceInterval         int    = 5                      // Seconds a device waits between two polls.
+	deviceSlowDown         int    = 5                      // Seconds added to the interval of a device polling too fast.
+	deviceStoreAttempts    int    = 5                      // Attempts at drawing a free user code.
+)
+
+var (
+	ErrInvalidClient        = errors.New("unknown client")                                // ErrInvalidClient is returned for clients not allowed to use the device grant.
+	ErrInvalidScope         = errors.New("invalid scope")                                 // ErrInvalidScope is returned for scopes that cannot be granted.
+	ErrInvalidGrant         = errors.New("the device code was issued to another client")  // ErrInvalidGrant is returned when a client polls with the device code of another.
+	ErrAuthorizationPending = errors.New("the user has not decided yet")                  // ErrAuthorizationPending is returned while the request waits for the user.
+	ErrSlowDown             = errors.New("polling too fast, increase the interval by 5s") // ErrSlowDown is returned when a device polls sooner than the interval.
+	ErrAccessDenied         = errors.New("the user denied the request")                   // ErrAccessDenied is returned when the user denied the request.
+	ErrExpiredToken         = errors.New("the device code expired")                       // ErrExpiredToken is returned for unknown, expired or already used device codes.
+	ErrUnknownUserCode      = errors.New("invalid or expired code")                       // ErrUnknownUserCode is returned for unknown or expired user codes.
+	ErrDeviceRequestDecided = errors.New("the request was already approved or denied")    // ErrDeviceRequestDecided is returned when the user decides twice.
+)
+
+// region Public
+
+// StartDeviceAuthorization creates a device authorization request (RFC 8628) for a client
+// without a browser or keyboard. The device shows the user code and polls the token endpoint
+// with the device code while the user approves the request from another device.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_clientId: The ID of the client, one of DEVICE_CLIENTS.
+//   - p_scope: The space separated scopes requested.
+//
+// Returns:
+//   - *oauth2_dto.DeviceAuthorizationResponse: The codes of the request.
+//   - error: ErrInvalidClient, an error wrapping ErrInvalidScope, or an error if the request
+//     cannot be stored.
+func StartDeviceAuthorization(p_db *database.DataRefs, p_clientId string, p_scope string) (*oauth2_dto.DeviceAuthorizationResponse, error) {
+	if p_clientId == "" || !slices.Contains(p_db.ConfigData.GetDeviceClients(), p_clientId) {
+		return nil, ErrInvalidClient
+	}
+
+	scopes, err := parseScopes(p_db, strings.Fields(p_scope))
+	if err != nil {
+		return nil, fmt.Errorf("%w - %s", ErrInvalidScope, err)
+	}
+
+	deviceCode, err := random.Token(deviceCodeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := p_db.ConfigData.RedisData.GetDeviceCodeDuration()
+	fields := map[string]string{
+		"client_id": p_clientId,
+		"scope":     strings.Join(scopes, " "),
+		"status":    DeviceStatusPending,
+		"interval":  strconv.Itoa(deviceInterval),
+		"expires":   strconv.FormatInt(time.Now().Add(duration).Unix(), 10),
+	}
+
+	for range deviceStoreAttempts {
+		userCode, err := random.Chars(deviceUserCodeLength, deviceUserCodeAlphabet)
+		if err != nil {
+			return nil, err
+		}
+		fields["user_code"] = userCode
+
+		ok, err := repository.StoreDeviceCode(p_db.Redis, hashDeviceCode(deviceCode), userCode, fields, duration)
+		if err != nil {
+			logger.Log("Failed to store device authorization request - "+err.Error(), logger.ERROR)
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		uri := strings.TrimSuffix(p_db.ConfigData.MailData.GetLinkBaseURL(), "/") + "/device"
+		return &oauth2_dto.DeviceAuthorizationResponse{
+			DeviceCode:              deviceCode,
+			UserCode:                formatUserCode(userCode),
+			VerificationURI:         uri,
+			VerificationURIComplete: uri + "?user_code=" + url.QueryEscape(formatUserCode(userCode)),
+			ExpiresIn:               int(duration.Seconds()),
+			Interval:                deviceInterval,
+		}, nil
+	}
+
+	return nil, errors.New("no free user code")
+}
+
+// GetDeviceRequest retrieves a device authorization request by its user code, for the user to
+// review it before deciding.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database references.
+//   - p_userCode: The user code, dashes, spaces and case ignored.
+//
+// Returns:
+//   - *oauth2_dto.DeviceRequestResponse: The request.
+//   - error: ErrUnknownUserCode for unknown or expired codes.
+func GetDeviceRequest(p_db *database.DataRefs, p_userCode string) (*oauth2_dto.DeviceRequestResponse, error) {
+	_, fields, err := repository.FindDeviceCodeByUserCode(p_db.Redis, normalizeUserCode(p_userCode))
+	if err != nil {
+		return nil, ErrUnknownUserCode
+	}
+	return newDeviceRequestResponse(fields), nil
+}
+
+// DecideDeviceRequest records the decision of a user on a pending device authorization
+// request. An approved request grants the device a personal access token of the user.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database references.
+//   - p_usrId: The unique ID (string) of the authenticated user.
+//   - p_dto: A pointer to the validated DeviceDecisionRequest.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - *oauth2_dto.DeviceRequestResponse: The request, with its new status.
+//   - error: ErrUnknownUserCode, ErrDeviceRequestDecided, an error wrapping ErrInvalidScope when
+//     the user may not grant the scopes, or the database error.
+func DecideDeviceRequest(p_db *database.DataRefs, p_usrId string, p_dto *oauth2_dto.DeviceDecisionRequest,
+	p_info RequestInfo) (_ *oauth2_dto.DeviceRequestResponse, err error) {
+	approve := p_dto.Action == "approve"
+	auditType, status := models.AuditDeviceDeny, DeviceStatusDenied
+	if approve {
+		auditType, status = models.AuditDeviceApprove, DeviceStatusApproved
+	}
+
+	details := map[string]string{"user_code": normalizeUserCode(p_dto.UserCode)}
+	defer func() {
+		auditResult(p_db.Postgres, p_info, auditType, p_usrId, err, details)
+	}()
+
+	key, fields, err := repository.FindDeviceCodeByUserCode(p_db.Redis, details["user_code"])
+	if err != nil {
+		return nil, ErrUnknownUserCode
+	}
+	details["client_id"], details["scope"] = fields["client_id"], fields["scope"]
+
+	if approve {
+		usr, err := GetUserById(p_db.Postgres, p_usrId)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorizeScopes(usr, strings.Fields(fields["scope"])); err != nil {
+			return nil, fmt.Errorf("%w - %s", ErrInvalidScope, err)
+		}
+	}
+
+	ok, err := repository.DecideDeviceCode(p_db.Redis, key, status, p_usrId, DeviceStatusPending)
+	if err != nil {
+		logger.Log("Failed to record device authorization decision - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrDeviceRequestDecided
+	}
+
+	fields["status"] = status
+	return newDeviceRequestResponse(fields), nil
+}
+
+// PollDeviceToken answers a device polling for the outcome of its authorization request. Once
+// approved, the device gets a personal access token of the user, named after the client and
+// the user code, listed and revocable with the other tokens of the user. The device code is
+// then consumed.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_clientId: The ID of the client polling.
+//   - p_deviceCode: The device code of the request.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - *oauth2_dto.TokenResponse: The access token.
+//   - error: ErrInvalidClient, ErrInvalidGrant, ErrAuthorizationPending, ErrSlowDown,
+//     ErrAccessDenied, ErrExpiredToken, or the error of the token creation.
+func PollDeviceToken(p_db *database.DataRefs, p_clientId string, p_deviceCode string,
+	p_info RequestInfo) (*oauth2_dto.TokenResponse, error) {
+	if p_clientId == "" || !slices.Contains(p_db.ConfigData.GetDeviceClients(), p_clientId) {
+		return nil, ErrInvalidClient
+	}
+
+	key := hashDeviceCode(p_deviceCode)
+	fields, err := repository.GetDeviceCode(p_db.Redis, key)
+	if err != nil {
+		return nil, ErrExpiredToken
+	}
+	if fields["client_id"] != p_clientId {
+		logger.Log("Device code polled by another client - "+p_clientId, logger.WARN)
+		return nil, ErrInvalidGrant
+	}
+
+	status, err := repository.PollDeviceCode(p_db.Redis, key, time.Now(), deviceSlowDown)
+	if err != nil {
+		logger.Log("Failed to poll device authorization request - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+
+	switch status {
+	case "slow_down":
+		return nil, ErrSlowDown
+	case DeviceStatusPending:
+		return nil, ErrAuthorizationPending
+	case DeviceStatusDenied:
+		repository.DeleteDeviceCode(p_db.Redis, key, fields["user_code"])
+		return nil, ErrAccessDenied
+	case DeviceStatusApproved:
+	default:
+		return nil, ErrExpiredToken
+	}
+
+	if ok, err := repository.DeleteDeviceCode(p_db.Redis, key, fields["user_code"]); err != nil || !ok {
+		return nil, ErrExpiredToken
+	}
+
+	return issueDeviceToken(p_db, fields["user_id"], fields, p_info)
+}
+
+// endregion Public
+
+// region Private
+
+// issueDeviceToken creates the personal access token of an approved device authorization
+// request, provided its user is still active.
+func issueDeviceToken(p_db *database.DataRefs, p_usrId string, p_fields map[string]string,
+	p_info RequestInfo) (_ *oauth2_dto.TokenResponse, err error) {
+	name := fmt.Sprintf("%s (%s)", p_fields["client_id"], formatUserCode(p_fields["user_code"]))
+	p_info.ActorID = p_usrId
+	defer func() {
+		auditResult(p_db.Postgres, p_info, models.AuditTokenCreate, p_usrId, err,
+			map[string]string{"name": name, "scopes": p_fields["scope"], "client_id": p_fields["client_id"]})
+	}()
+
+	usr, err := GetUserById(p_db.Postgres, p_usrId)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckUserActive(usr); err != nil {
+		return nil, err
+	}
+
+	duration := p_db.ConfigData.GetDeviceTokenDuration()
+	expiresAt := time.Now().Add(duration)
+	_, raw, err := issuePersonalToken(p_db, usr, name, strings.Fields(p_fields["scope"]), &expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2_dto.TokenResponse{
+		AccessToken: raw,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(duration.Seconds()),
+		Scope:       p_fields["scope"],
+	}, nil
+}
+
+// newDeviceRequestResponse describes a stored device authorization request.
+func newDeviceRequestResponse(p_fields map[string]string) *oauth2_dto.DeviceRequestResponse {
+	expires, _ := strconv.ParseInt(p_fields["expires"], 10, 64)
+	return &oauth2_dto.DeviceRequestResponse{
+		UserCode:  formatUserCode(p_fields["user_code"]),
+		ClientId:  p_fields["client_id"],
+		Scope:     p_fields["scope"],
+		Status:    p_fields["status"],
+		ExpiresAt: time.Unix(expires, 0).UTC(),
+	}
+}
+
+// normalizeUserCode returns the canonical form of a user code as typed: upper case, without
+// dashes or spaces.
+func normalizeUserCode(p_code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(p_code))
+}
+
+// formatUserCode splits a user code in two halves for display.
+func formatUserCode(p_code string) string {
+	if len(p_code) != deviceUserCodeLength {
+		return p_code
+	}
+	return p_code[:deviceUserCodeLength/2] + "-" + p_code[deviceUserCodeLength/2:]
+}
+
+// hashDeviceCode returns the SHA-256 hash of a device code, hex encoded, under which its
+// request is stored.
+func hashDeviceCode(p_code string) string {
+	sum := sha256.Sum256([]byte(p_code))
+	return hex.EncodeToString(sum[:])
+}
+
+// endregion Private
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/event_dto"
+	"cerberus/internal/models"
+	"cerberus/internal/repository"
+	"cerberus/internal/tools/logger"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// region Public
+
+// EmitEvent publishes an identity event to the internal consumers and to the webhooks.
+//
+// The event is appended to the configured Redis Stream, then queued for the webhook
+// subscriptions if its type can be subscribed to (see models.WebhookEventTypes). Failures are
+// logged and never returned, so that a broken consumer setup cannot fail the operation that
+// triggered the event.
+//
+// Each stream entry holds the following fields, the JSON payload following docs/events.md:
+//   - event_id: The unique ID of the event, identical in the webhook payloads.
+//   - type: The type of the event, so consumers can filter without decoding the payload.
+//   - payload: The event_dto.Event encoded as JSON.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_type: The event type (one of the models.Event* constants).
+//   - p_data: The event-specific information, which should include the "user_id".
+func EmitEvent(p_db *database.DataRefs, p_type string, p_data map[string]string) {
+	event := &event_dto.Event{
+		EventId:    uuid.NewString(),
+		Type:       p_type,
+		OccurredAt: time.Now().UTC(),
+		Data:       p_data,
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logger.Log(fmt.Sprintf("Failed to encode event %s - %s", p_type, err.Error()), logger.ERROR)
+		return
+	}
+
+	_, err = repository.AppendStreamEvent(p_db.Redis, p_db.ConfigData.RedisData.GetEventsStream(),
+		p_db.ConfigData.RedisData.GetEventsStreamMaxLen(), map[string]interface{}{
+			"event_id": event.EventId,
+			"type":     event.Type,
+			"payload":  string(payload),
+		})
+	if err != nil {
+		logger.Log(fmt.Sprintf("Failed to append event %s to the stream - %s", p_type, err.Error()), logger.ERROR)
+	}
+
+	if slices.Contains(models.WebhookEventTypes, p_type) {
+		PublishWebhookEvent(p_db.Postgres, event.Type, payload)
+	}
+}
+
+// endregion Public
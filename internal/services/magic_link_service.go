@@ -0,0 +1,212 @@
+package services
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/auth_dto"
+	"cerberus/internal/dto/session_dto"
+	"cerberus/internal/models"
+	"cerberus/internal/repository"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/random"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidMagicLink     = errors.New("invalid or expired login link")                   // ErrInvalidMagicLink is returned for unknown, expired, consumed or foreign login links.
+	ErrMagicLinkRateLimited = errors.New("too many login links requested, try again later") // ErrMagicLinkRateLimited is returned once an email or IP address requested too many links.
+)
+
+// region Public
+
+// RequestMagicLink emails a one-time login link to the owner of an account.
+//
+// A random nonce is always returned, to be set in a cookie of the requesting browser: only
+// this browser can consume the link. The outcome does not tell whether the account exists, so
+// unknown and non-active accounts get a nonce but no email. The account is looked up and the
+// email sent in the background, so the response time does not tell either.
+//
+// Requests are limited per email address and per client IP address, whether the account
+// exists or not.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_dto: A pointer to the MagicLinkRequest holding the email address.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - string: The nonce binding the link to the browser.
+//   - error: ErrMagicLinkRateLimited, or an error if the address is invalid or the rate limits
+//     cannot be checked; nil otherwise.
+func RequestMagicLink(p_db *database.DataRefs, p_dto *session_dto.MagicLinkRequest, p_info RequestInfo) (string, error) {
+	nonce, err := random.Token(16)
+	if err != nil {
+		return "", err
+	}
+
+	mail, err := NormalizeEmail(p_db, p_dto.Email)
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkMagicLinkRate(p_db, mail, p_info.IP); err != nil {
+		return "", err
+	}
+
+	go sendMagicLink(p_db, mail, nonce, p_dto.UseCookies, p_info)
+	return nonce, nil
+}
+
+// ConsumeMagicLink checks a login link and returns the user to log in.
+//
+// The nonce must match the one of the browser that requested the link. The link is only
+// consumed once the nonce matched, so a link opened elsewhere, for instance by a mail scanner,
+// remains usable by the right browser.
+//
+// Failures are recorded in the audit log; the successful login is recorded by LoginUser.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_token: The token of the link.
+//   - p_nonce: The nonce read from the cookie of the browser.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - *models.User: The user to log in.
+//   - *auth_dto.MagicLink: The consumed link, telling whether it is a browser session.
+//   - error: ErrInvalidMagicLink, an error wrapping ErrAccountNotActive, or the database error.
+func ConsumeMagicLink(p_db *database.DataRefs, p_token string, p_nonce string,
+	p_info RequestInfo) (_ *models.User, _ *auth_dto.MagicLink, err error) {
+	var usrId string
+	defer func() {
+		if err != nil {
+			auditResult(p_db.Postgres, p_info, models.AuditLoginFailure, usrId, err,
+				map[string]string{"method": "magic_link"})
+		}
+	}()
+
+	data, err := repository.GetMagicLink(p_db.Redis, p_token)
+	if err != nil {
+		return nil, nil, ErrInvalidMagicLink
+	}
+
+	var link auth_dto.MagicLink
+	if err := json.Unmarshal([]byte(data), &link); err != nil {
+		return nil, nil, ErrInvalidMagicLink
+	}
+	usrId = link.UserID
+
+	if p_nonce == "" || subtle.ConstantTimeCompare([]byte(link.Nonce), []byte(p_nonce)) != 1 {
+		logger.Log("Login link refused - nonce mismatch", logger.WARN)
+		return nil, nil, ErrInvalidMagicLink
+	}
+
+	if removed, err := repository.DeleteMagicLink(p_db.Redis, p_token); err != nil {
+		return nil, nil, err
+	} else if !removed {
+		return nil, nil, ErrInvalidMagicLink
+	}
+
+	usr, err := GetUserById(p_db.Postgres, link.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := CheckUserActive(usr); err != nil {
+		return nil, nil, err
+	}
+
+	return usr, &link, nil
+}
+
+// endregion Public
+
+// region Private
+
+// checkMagicLinkRate counts a login link request against the limits of the email address and
+// of the client IP address. Every request is counted, whether the account exists or not.
+func checkMagicLinkRate(p_db *database.DataRefs, p_mail string, p_ip string) error {
+	window := p_db.ConfigData.GetMagicLinkRateWindow()
+
+	sum := sha256.Sum256([]byte(strings.ToLower(p_mail)))
+	hits, err := repository.CountRateLimitHit(p_db.Redis, "magic_link:email:"+hex.EncodeToString(sum[:]), window)
+	if err != nil {
+		logger.Log("Failed to count login link request - "+err.Error(), logger.ERROR)
+		return err
+	}
+	if hits > int64(p_db.ConfigData.GetMagicLinkEmailLimit()) {
+		return ErrMagicLinkRateLimited
+	}
+
+	if p_ip == "" {
+		return nil
+	}
+	hits, err = repository.CountRateLimitHit(p_db.Redis, "magic_link:ip:"+p_ip, window)
+	if err != nil {
+		logger.Log("Failed to count login link request - "+err.Error(), logger.ERROR)
+		return err
+	}
+	if hits > int64(p_db.ConfigData.GetMagicLinkIPLimit()) {
+		return ErrMagicLinkRateLimited
+	}
+	return nil
+}
+
+// sendMagicLink looks up the account of p_mail and emails it a login link bound to p_nonce.
+// It runs in the background of RequestMagicLink, so failures are only logged.
+func sendMagicLink(p_db *database.DataRefs, p_mail string, p_nonce string, p_useCookies bool, p_info RequestInfo) {
+	usr, err := repository.FindUserByEmail(p_db.Postgres, p_mail)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Log("Login link requested for an unknown account", logger.INFO)
+		return
+	} else if err != nil {
+		logger.Log("Failed to look up login link account - "+err.Error(), logger.ERROR)
+		return
+	}
+
+	if err := CheckUserActive(usr); err != nil {
+		auditResult(p_db.Postgres, p_info, models.AuditMagicLinkRequest, usr.ID.String(), err, nil)
+		return
+	}
+
+	tkn, err := random.Token(32)
+	if err != nil {
+		logger.Log("Failed to generate login link token - "+err.Error(), logger.ERROR)
+		return
+	}
+
+	data, err := json.Marshal(auth_dto.MagicLink{UserID: usr.ID.String(), Nonce: p_nonce, UseCookies: p_useCookies})
+	if err != nil {
+		logger.Log("Failed to encode login link - "+err.Error(), logger.ERROR)
+		return
+	}
+
+	duration := p_db.ConfigData.RedisData.GetMagicLinkDuration()
+	if err := repository.StoreMagicLink(p_db.Redis, tkn, string(data), duration); err != nil {
+		logger.Log("Failed to store login link - "+err.Error(), logger.ERROR)
+		return
+	}
+
+	link := fmt.Sprintf("%s/magic-link?token=%s", p_db.ConfigData.MailData.GetLinkBaseURL(), url.QueryEscape(tkn))
+	body := fmt.Sprintf("Hello %s,\n\nLog in by opening the link below, in the browser you requested it from:\n%s\n\n"+
+		"The link expires in %s and can only be used once. If you did not request it, ignore this email.",
+		usr.Name, link, duration)
+
+	if err := p_db.Mailer.Send(usr.Email, "Your login link", body); err != nil {
+		repository.DeleteMagicLink(p_db.Redis, tkn)
+		logger.Log("Failed to send login link - "+err.Error(), logger.ERROR)
+		auditResult(p_db.Postgres, p_info, models.AuditMagicLinkRequest, usr.ID.String(), err, nil)
+		return
+	}
+
+	auditResult(p_db.Postgres, p_info, models.AuditMagicLinkRequest, usr.ID.String(), nil, nil)
+}
+
+// endregion Private
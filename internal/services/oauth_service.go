@@ -0,0 +1,275 @@
+package services
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/auth_dto"
+	"cerberus/internal/models"
+	"cerberus/internal/repository"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/oauth"
+	"cerberus/internal/tools/random"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownProvider   = errors.New("unknown identity provider")                  // ErrUnknownProvider is returned for providers that are not configured.
+	ErrInvalidOAuthState = errors.New("invalid or expired login state")             // ErrInvalidOAuthState is returned when a callback does not match a pending login.
+	ErrEmailNotVerified  = errors.New("email address not verified by the provider") // ErrEmailNotVerified is returned when an unknown identity has no verified email address.
+	ErrSignupDisabled    = errors.New("no account is linked to this identity")      // ErrSignupDisabled is returned for unknown identities of providers that cannot create accounts.
+)
+
+// region Public
+
+// StartOAuthLogin starts a federated login with an identity provider.
+//
+// A random state, nonce and PKCE verifier are generated and stored in Redis for the duration
+// of the login. The state is sent to the provider and comes back on the callback.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_ctx: The context of the request.
+//   - p_provider: The name of the provider.
+//   - p_useCookies: Whether the session will be a browser session, set in cookies.
+//
+// Returns:
+//   - string: The URL of the provider the browser must be redirected to.
+//   - string: The state of the login.
+//   - error: ErrUnknownProvider, or an error if the provider or Redis cannot be reached.
+func StartOAuthLogin(p_db *database.DataRefs, p_ctx context.Context, p_provider string, p_useCookies bool) (string, string, error) {
+	provider, ok := p_db.OAuth[p_provider]
+	if !ok {
+		return "", "", ErrUnknownProvider
+	}
+
+	state, err := random.Token(32)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := random.Token(16)
+	if err != nil {
+		return "", "", err
+	}
+
+	pending := auth_dto.OAuthState{
+		Provider:   p_provider,
+		Nonce:      nonce,
+		Verifier:   oauth.GenerateVerifier(),
+		UseCookies: p_useCookies,
+	}
+
+	authURL, err := provider.AuthCodeURL(p_ctx, state, pending.Nonce, pending.Verifier)
+	if err != nil {
+		logger.Log(fmt.Sprintf("Failed to reach the %s provider - %s", p_provider, err.Error()), logger.ERROR)
+		return "", "", err
+	}
+
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return "", "", err
+	}
+	if err := repository.StoreOAuthState(p_db.Redis, state, string(data),
+		p_db.ConfigData.OAuthData.GetStateDuration()); err != nil {
+		logger.Log("Failed to store the OAuth state - "+err.Error(), logger.ERROR)
+		return "", "", err
+	}
+
+	return authURL, state, nil
+}
+
+// CompleteOAuthLogin handles the callback of a federated login and returns the user to log in.
+//
+// The state is consumed, so a callback cannot be replayed. The authorization code is then
+// exchanged with the PKCE verifier, and the identity returned by the provider is resolved:
+//  1. A linked identity logs in as its user.
+//  2. Otherwise, an account using the same email address is linked, if the provider verified it.
+//  3. Otherwise, an account is created, unless signup is disabled for the provider.
+//
+// Failures are recorded in the audit log; the successful login is recorded by LoginUser.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_ctx: The context of the request.
+//   - p_provider: The name of the provider of the callback route.
+//   - p_state: The state received on the callback.
+//   - p_code: The authorization code received on the callback.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - *models.User: The user to log in.
+//   - *auth_dto.OAuthState: The pending login, telling whether it is a browser session.
+//   - error: ErrUnknownProvider, ErrInvalidOAuthState, ErrEmailNotVerified, ErrSignupDisabled,
+//     an error wrapping ErrAccountNotActive, or the error of the provider.
+func CompleteOAuthLogin(p_db *database.DataRefs, p_ctx context.Context, p_provider string, p_state string,
+	p_code string, p_info RequestInfo) (_ *models.User, _ *auth_dto.OAuthState, err error) {
+	var usrId string
+	defer func() {
+		if err != nil {
+			auditResult(p_db.Postgres, p_info, models.AuditLoginFailure, usrId, err,
+				map[string]string{"provider": p_provider})
+		}
+	}()
+
+	provider, ok := p_db.OAuth[p_provider]
+	if !ok {
+		return nil, nil, ErrUnknownProvider
+	}
+
+	data, err := repository.TakeOAuthState(p_db.Redis, p_state)
+	if err != nil {
+		return nil, nil, ErrInvalidOAuthState
+	}
+
+	var pending auth_dto.OAuthState
+	if err := json.Unmarshal([]byte(data), &pending); err != nil || pending.Provider != p_provider {
+		return nil, nil, ErrInvalidOAuthState
+	}
+
+	idt, err := provider.Identify(p_ctx, p_code, pending.Nonce, pending.Verifier)
+	if err != nil {
+		logger.Log(fmt.Sprintf("Federated login with %s failed - %s", p_provider, err.Error()), logger.ERROR)
+		return nil, nil, err
+	}
+
+	disableSignup := p_db.ConfigData.OAuthData.Providers[p_provider].DisableSignup
+	usr, err := resolveIdentity(p_db, p_provider, idt, disableSignup, false, p_info)
+	if usr != nil {
+		usrId = usr.ID.String()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return usr, &pending, nil
+}
+
+// endregion Public
+
+// region Private
+
+// resolveIdentity returns the user of a provider identity, linking it to an existing account
+// with the same verified email address, or creating the account, when it is not linked yet.
+// With p_explicitLink, existing accounts that have a password or another identity are not
+// linked automatically: their user must link the identity while logged in.
+// The returned user is set along with ErrAccountNotActive so the failure can be audited.
+func resolveIdentity(p_db *database.DataRefs, p_provider string, p_idt *oauth.Identity, p_disableSignup bool,
+	p_explicitLink bool, p_info RequestInfo) (*models.User, error) {
+	link, err := repository.FindIdentity(p_db.Postgres, p_provider, p_idt.Subject)
+	if err == nil {
+		usr, err := GetUserById(p_db.Postgres, link.UserID.String())
+		if err != nil {
+			return nil, err
+		}
+		if err := CheckUserActive(usr); err != nil {
+			return usr, err
+		}
+
+		if err := repository.TouchIdentity(p_db.Postgres, link, p_idt.Email); err != nil {
+			logger.Log("Failed to update identity - "+err.Error(), logger.WARN)
+		}
+		return usr, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	mail, err := verifiedEmail(p_db, p_idt)
+	if err != nil {
+		return nil, err
+	}
+
+	usr, err := repository.FindUserByEmail(p_db.Postgres, mail)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if p_disableSignup {
+			return nil, ErrSignupDisabled
+		}
+		if usr, err = createFederatedUser(p_db, mail, p_idt.Name); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else if err := CheckUserActive(usr); err != nil {
+		return usr, err
+	} else if p_explicitLink {
+		if err := checkAutoLink(p_db, usr); err != nil {
+			return usr, err
+		}
+	}
+
+	if err := createIdentityLink(p_db, usr, p_provider, p_idt, p_info); err != nil {
+		return nil, err
+	}
+	return usr, nil
+}
+
+// checkAutoLink refuses to link an identity automatically to an account that has a password or
+// another identity, as the provider asserting its email address would take it over.
+func checkAutoLink(p_db *database.DataRefs, p_usr *models.User) error {
+	if p_usr.Password != "" {
+		return ErrIdentityLinkRequired
+	}
+	linked, err := repository.UserHasIdentities(p_db.Postgres, p_usr.ID.String())
+	if err != nil {
+		return err
+	}
+	if linked {
+		return ErrIdentityLinkRequired
+	}
+	return nil
+}
+
+// createIdentityLink links a provider identity to a user and audits it.
+func createIdentityLink(p_db *database.DataRefs, p_usr *models.User, p_provider string, p_idt *oauth.Identity,
+	p_info RequestInfo) error {
+	link := &models.Identity{UserID: p_usr.ID, Provider: p_provider, Subject: p_idt.Subject, Email: p_idt.Email,
+		LastLoginAt: time.Now()}
+	if err := repository.CreateIdentity(p_db.Postgres, link); err != nil {
+		logger.Log("Failed to link identity - "+err.Error(), logger.ERROR)
+		return err
+	}
+
+	p_info.ActorID = p_usr.ID.String()
+	RecordAuditEvent(p_db.Postgres, p_info, models.AuditIdentityLink, p_usr.ID.String(), models.OutcomeSuccess,
+		map[string]string{"provider": p_provider, "subject": p_idt.Subject})
+	return nil
+}
+
+// verifiedEmail returns the canonical email address of an identity that is not linked yet.
+// Only an address verified by the provider may be linked to an account, or create one:
+// otherwise anyone could take over an account by claiming its address at the provider.
+func verifiedEmail(p_db *database.DataRefs, p_idt *oauth.Identity) (string, error) {
+	if p_idt.Email == "" || !p_idt.EmailVerified {
+		return "", ErrEmailNotVerified
+	}
+	return NormalizeEmail(p_db, p_idt.Email)
+}
+
+// createFederatedUser creates the account of a user logging in with an identity provider.
+// The account has no password, so it can only log in through its linked identities.
+func createFederatedUser(p_db *database.DataRefs, p_email string, p_name string) (*models.User, error) {
+	if p_name = strings.TrimSpace(p_name); p_name == "" {
+		p_name, _, _ = strings.Cut(p_email, "@")
+	}
+
+	usr := &models.User{
+		Name:   p_name,
+		Email:  p_email,
+		Status: models.StatusActive,
+	}
+	if err := repository.CreateUser(p_db.Postgres, usr); err != nil {
+		logger.Log("Failed to Create user - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+
+	EmitEvent(p_db, models.EventUserRegistered, map[string]string{
+		"user_id": usr.ID.String(), "email": usr.Email, "name": usr.Name,
+	})
+	return usr, nil
+}
+
+// endregion Private
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/tools/oauth"
+	"cerberus/pkg/config"
+	"errors"
+	"testing"
+)
+
+func TestVerifiedEmailRefusesUnverifiedAddresses(t *testing.T) {
+	db := &database.DataRefs{ConfigData: &config.ConfigData{}}
+
+	tests := []struct {
+		name    string
+		idt     oauth.Identity
+		want    string
+		wantErr error
+	}{
+		{"verified", oauth.Identity{Subject: "1", Email: " Ada@Example.COM ", EmailVerified: true}, "Ada@example.com", nil},
+		{"unverified", oauth.Identity{Subject: "1", Email: "ada@example.com"}, "", ErrEmailNotVerified},
+		{"missing", oauth.Identity{Subject: "1", EmailVerified: true}, "", ErrEmailNotVerified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verifiedEmail(db, &tt.idt)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("email = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
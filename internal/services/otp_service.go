@@ -0,0 +1,457 @@
+package services
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/session_dto"
+	"cerberus/internal/models"
+	"cerberus/internal/repository"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/otp"
+	"cerberus/internal/tools/random"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	OTPPurposeLogin  string = "login"   // OTPPurposeLogin marks codes logging a user in.
+	OTPPurposeStepUp string = "step_up" // OTPPurposeStepUp marks codes reauthenticating the user of a session.
+	OTPPurposePhone  string = "phone"   // OTPPurposePhone marks codes verifying a new phone number.
+
+	otpResendInterval time.Duration = 30 * time.Second // otpResendInterval is the minimum delay between two codes of the same purpose.
+)
+
+var (
+	ErrInvalidOTP            = errors.New("invalid or expired code")                   // ErrInvalidOTP is returned for wrong, unknown or expired codes.
+	ErrOTPAttemptsExceeded   = errors.New("too many attempts, request a new code")     // ErrOTPAttemptsExceeded is returned once a code was guessed too many times.
+	ErrOTPChannelUnavailable = errors.New("channel not available")                     // ErrOTPChannelUnavailable is returned for unknown or unconfigured channels, or users without a phone number.
+	ErrOTPResendTooSoon      = errors.New("a code was sent recently, try again later") // ErrOTPResendTooSoon is returned when a new code is requested too soon.
+	ErrOTPLocked             = errors.New("too many wrong codes, try again later")     // ErrOTPLocked is returned while a user is locked out after too many wrong codes.
+)
+
+// region Public
+
+// RequestLoginOTP sends a one-time login code to the owner of an account.
+//
+// The outcome does not tell whether the account exists: unknown and non-active accounts, users
+// without a phone number and repeated requests succeed without sending a code. Only channels
+// that are not configured are reported.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_dto: A pointer to the OTPLoginRequest holding the email address and the channel.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - error: ErrOTPChannelUnavailable, or an error if the code cannot be stored or sent; nil otherwise.
+func RequestLoginOTP(p_db *database.DataRefs, p_dto *session_dto.OTPLoginRequest, p_info RequestInfo) error {
+	channel := otpChannel(p_dto.Channel)
+	if _, ok := p_db.OTP[channel]; !ok {
+		return ErrOTPChannelUnavailable
+	}
+
+	mail, err := NormalizeEmail(p_db, p_dto.Email)
+	if err != nil {
+		return err
+	}
+
+	usr, err := repository.FindUserByEmail(p_db.Postgres, mail)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Log("Login code requested for an unknown account", logger.INFO)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	details := map[string]string{"purpose": OTPPurposeLogin, "channel": channel}
+	if err := CheckUserActive(usr); err != nil {
+		auditResult(p_db.Postgres, p_info, models.AuditOTPRequest, usr.ID.String(), err, details)
+		return nil
+	}
+
+	err = sendUserOTP(p_db, usr, OTPPurposeLogin, channel,
+		map[string]string{"use_cookies": strconv.FormatBool(p_dto.UseCookies)})
+	auditResult(p_db.Postgres, p_info, models.AuditOTPRequest, usr.ID.String(), err, details)
+
+	if errors.Is(err, ErrOTPChannelUnavailable) || errors.Is(err, ErrOTPResendTooSoon) || errors.Is(err, ErrOTPLocked) {
+		logger.Log("Login code not sent - "+err.Error(), logger.INFO)
+		return nil
+	}
+	return err
+}
+
+// VerifyLoginOTP checks a one-time login code and returns the user to log in.
+//
+// Failures are recorded in the audit log; the successful login is recorded by LoginUser.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_email: The email address of the account.
+//   - p_code: The code received by the user.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - *models.User: The user to log in.
+//   - *session_dto.OTPLoginData: Whether the code was requested for a browser session, and the
+//     authentication methods it proves.
+//   - error: ErrInvalidOTP, ErrOTPAttemptsExceeded, ErrOTPLocked, an error wrapping
+//     ErrAccountNotActive, or the database error.
+func VerifyLoginOTP(p_db *database.DataRefs, p_email string, p_code string,
+	p_info RequestInfo) (_ *models.User, _ *session_dto.OTPLoginData, err error) {
+	var usrId string
+	defer func() {
+		if err != nil {
+			auditResult(p_db.Postgres, p_info, models.AuditLoginFailure, usrId, err,
+				map[string]string{"method": "otp"})
+		}
+	}()
+
+	mail, err := NormalizeEmail(p_db, p_email)
+	if err != nil {
+		return nil, nil, ErrInvalidOTP
+	}
+
+	usr, err := repository.FindUserByEmail(p_db.Postgres, mail)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrInvalidOTP
+	} else if err != nil {
+		return nil, nil, err
+	}
+	usrId = usr.ID.String()
+
+	fields, err := verifyOTP(p_db, OTPPurposeLogin, usrId, p_code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := CheckUserActive(usr); err != nil {
+		return nil, nil, err
+	}
+
+	return usr, &session_dto.OTPLoginData{
+		UseCookies: fields["use_cookies"] == "true",
+		Methods:    otpMethods(fields["channel"]),
+	}, nil
+}
+
+// RequestStepUpOTP sends a code to the authenticated user, to reauthenticate with (see
+// Reauthenticate).
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_usrId: The unique ID (string) of the authenticated user.
+//   - p_channel: The channel of the code, "email" when empty.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - *session_dto.OTPSentResponse: The channel and the masked recipient of the code.
+//   - error: ErrOTPChannelUnavailable, ErrOTPResendTooSoon, ErrOTPLocked, or an error if the
+//     code cannot be stored or sent; nil otherwise.
+func RequestStepUpOTP(p_db *database.DataRefs, p_usrId string, p_channel string,
+	p_info RequestInfo) (_ *session_dto.OTPSentResponse, err error) {
+	channel := otpChannel(p_channel)
+	defer func() {
+		auditResult(p_db.Postgres, p_info, models.AuditOTPRequest, p_usrId, err,
+			map[string]string{"purpose": OTPPurposeStepUp, "channel": channel})
+	}()
+
+	usr, err := GetUserById(p_db.Postgres, p_usrId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sendUserOTP(p_db, usr, OTPPurposeStepUp, channel, nil); err != nil {
+		return nil, err
+	}
+
+	to, _ := otpRecipient(usr, channel)
+	return &session_dto.OTPSentResponse{
+		Message: "Verification code sent",
+		Channel: channel,
+		SentTo:  maskRecipient(channel, to),
+	}, nil
+}
+
+// RequestPhoneVerification sends a code by SMS to the new phone number of a user. The number
+// is only saved once ConfirmPhone is called with that code.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_usrId: The unique ID (string) of the authenticated user.
+//   - p_phone: The new phone number, in E.164 format.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - error: ErrOTPChannelUnavailable if SMS is not configured, ErrOTPResendTooSoon,
+//     ErrOTPLocked, or an error if the code cannot be stored or sent; nil otherwise.
+func RequestPhoneVerification(p_db *database.DataRefs, p_usrId string, p_phone string, p_info RequestInfo) (err error) {
+	defer func() {
+		auditResult(p_db.Postgres, p_info, models.AuditOTPRequest, p_usrId, err,
+			map[string]string{"purpose": OTPPurposePhone, "channel": otp.ChannelSMS})
+	}()
+
+	return sendOTP(p_db, OTPPurposePhone, p_usrId, otp.ChannelSMS, p_phone, nil)
+}
+
+// ConfirmPhone checks the code sent to a pending phone number and saves the number.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_usrId: The unique ID (string) of the authenticated user.
+//   - p_code: The code received on the new number.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - *models.User: The updated user.
+//   - error: ErrInvalidOTP, ErrOTPAttemptsExceeded, ErrOTPLocked, or the database error.
+func ConfirmPhone(p_db *database.DataRefs, p_usrId string, p_code string, p_info RequestInfo) (_ *models.User, err error) {
+	var phone string
+	defer func() {
+		auditResult(p_db.Postgres, p_info, models.AuditPhoneChange, p_usrId, err,
+			map[string]string{"phone": maskRecipient(otp.ChannelSMS, phone)})
+	}()
+
+	fields, err := verifyOTP(p_db, OTPPurposePhone, p_usrId, p_code)
+	if err != nil {
+		return nil, err
+	}
+	phone = fields["to"]
+
+	usr, err := GetUserById(p_db.Postgres, p_usrId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repository.UpdatePhone(p_db.Postgres, usr, phone); err != nil {
+		logger.Log("Failed to update phone - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+	usr.Phone = phone
+
+	return usr, nil
+}
+
+// RemovePhone removes the phone number of a user. Codes can no longer be sent by SMS.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database references.
+//   - p_usrId: The unique ID (string) of the authenticated user.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - *models.User: The updated user.
+//   - error: The database error, nil otherwise.
+func RemovePhone(p_db *database.DataRefs, p_usrId string, p_info RequestInfo) (_ *models.User, err error) {
+	defer func() {
+		auditResult(p_db.Postgres, p_info, models.AuditPhoneChange, p_usrId, err,
+			map[string]string{"phone": ""})
+	}()
+
+	usr, err := GetUserById(p_db.Postgres, p_usrId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repository.UpdatePhone(p_db.Postgres, usr, ""); err != nil {
+		logger.Log("Failed to remove phone - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+	usr.Phone = ""
+
+	return usr, nil
+}
+
+// endregion Public
+
+// region Private
+
+// otpChannel returns the channel of a request, email by default.
+func otpChannel(p_channel string) string {
+	if p_channel == "" {
+		return otp.ChannelEmail
+	}
+	return p_channel
+}
+
+// otpRecipient returns where the codes of a channel are sent for a user.
+func otpRecipient(p_usr *models.User, p_channel string) (string, error) {
+	switch p_channel {
+	case otp.ChannelEmail:
+		return p_usr.Email, nil
+	case otp.ChannelSMS:
+		if p_usr.Phone == "" {
+			return "", ErrOTPChannelUnavailable
+		}
+		return p_usr.Phone, nil
+	default:
+		return "", ErrOTPChannelUnavailable
+	}
+}
+
+// sendUserOTP sends a code to the email address or the phone number of a user.
+func sendUserOTP(p_db *database.DataRefs, p_usr *models.User, p_purpose string, p_channel string,
+	p_extra map[string]string) error {
+	to, err := otpRecipient(p_usr, p_channel)
+	if err != nil {
+		return err
+	}
+	return sendOTP(p_db, p_purpose, p_usr.ID.String(), p_channel, to, p_extra)
+}
+
+// sendOTP generates a code, stores its hash with the extra fields and sends it. A new code
+// replaces the pending one, unless it was sent less than otpResendInterval ago. No code is sent
+// to a subject locked out by checkOTPLockout.
+func sendOTP(p_db *database.DataRefs, p_purpose string, p_subject string, p_channel string, p_to string,
+	p_extra map[string]string) error {
+	sender, ok := p_db.OTP[p_channel]
+	if !ok {
+		return ErrOTPChannelUnavailable
+	}
+
+	if err := checkOTPLockout(p_db, p_subject); err != nil {
+		return err
+	}
+
+	if pending, err := repository.GetOTP(p_db.Redis, p_purpose, p_subject); err == nil {
+		issued, _ := strconv.ParseInt(pending["issued"], 10, 64)
+		if time.Since(time.Unix(issued, 0)) < otpResendInterval {
+			return ErrOTPResendTooSoon
+		}
+	}
+
+	code, err := random.Digits(p_db.ConfigData.GetOTPDigits())
+	if err != nil {
+		logger.Log("Failed to generate one-time code - "+err.Error(), logger.ERROR)
+		return err
+	}
+
+	fields := map[string]string{
+		"code":     hashOTP(code),
+		"channel":  p_channel,
+		"to":       p_to,
+		"issued":   strconv.FormatInt(time.Now().Unix(), 10),
+		"attempts": "0",
+	}
+	for k, v := range p_extra {
+		fields[k] = v
+	}
+
+	ttl := p_db.ConfigData.RedisData.GetOTPDuration()
+	if err := repository.StoreOTP(p_db.Redis, p_purpose, p_subject, fields, ttl); err != nil {
+		logger.Log("Failed to store one-time code - "+err.Error(), logger.ERROR)
+		return err
+	}
+
+	if err := sender.Send(p_to, code, ttl); err != nil {
+		repository.DeleteOTP(p_db.Redis, p_purpose, p_subject)
+		logger.Log("Failed to send one-time code - "+err.Error(), logger.ERROR)
+		return err
+	}
+
+	return nil
+}
+
+// verifyOTP checks a code and consumes it. Every guess is counted before the comparison; the
+// code is discarded once the maximum number of attempts is reached.
+//
+// Wrong codes are also counted per subject, whatever the code they were sent for, so that
+// requesting new codes does not allow more guesses: once GetOTPMaxFailures is reached, the
+// subject is locked out until the window started by the first failure ends.
+func verifyOTP(p_db *database.DataRefs, p_purpose string, p_subject string, p_code string) (map[string]string, error) {
+	if err := checkOTPLockout(p_db, p_subject); err != nil {
+		repository.DeleteOTP(p_db.Redis, p_purpose, p_subject)
+		return nil, err
+	}
+
+	attempts, err := repository.CountOTPAttempt(p_db.Redis, p_purpose, p_subject)
+	if err != nil {
+		return nil, ErrInvalidOTP
+	}
+
+	maxAttempts := int64(p_db.ConfigData.GetOTPMaxAttempts())
+	if attempts > maxAttempts {
+		repository.DeleteOTP(p_db.Redis, p_purpose, p_subject)
+		return nil, ErrOTPAttemptsExceeded
+	}
+
+	fields, err := repository.GetOTP(p_db.Redis, p_purpose, p_subject)
+	if err != nil {
+		return nil, ErrInvalidOTP
+	}
+
+	if subtle.ConstantTimeCompare([]byte(fields["code"]), []byte(hashOTP(p_code))) != 1 {
+		failures, err := repository.CountOTPFailure(p_db.Redis, p_subject, p_db.ConfigData.GetOTPLockout())
+		if err != nil {
+			logger.Log("Failed to count wrong code - "+err.Error(), logger.ERROR)
+		}
+		if failures >= int64(p_db.ConfigData.GetOTPMaxFailures()) {
+			repository.DeleteOTP(p_db.Redis, p_purpose, p_subject)
+			return nil, ErrOTPLocked
+		}
+		if attempts == maxAttempts {
+			repository.DeleteOTP(p_db.Redis, p_purpose, p_subject)
+			return nil, ErrOTPAttemptsExceeded
+		}
+		return nil, ErrInvalidOTP
+	}
+
+	if removed, err := repository.DeleteOTP(p_db.Redis, p_purpose, p_subject); err != nil {
+		return nil, err
+	} else if !removed {
+		return nil, ErrInvalidOTP
+	}
+
+	if err := repository.ClearOTPFailures(p_db.Redis, p_subject); err != nil {
+		logger.Log("Failed to reset wrong code counter - "+err.Error(), logger.WARN)
+	}
+	return fields, nil
+}
+
+// checkOTPLockout returns ErrOTPLocked if a subject sent too many wrong codes in the current
+// window.
+func checkOTPLockout(p_db *database.DataRefs, p_subject string) error {
+	failures, err := repository.GetOTPFailures(p_db.Redis, p_subject)
+	if err != nil {
+		logger.Log("Failed to read wrong code counter - "+err.Error(), logger.ERROR)
+		return err
+	}
+	if failures >= int64(p_db.ConfigData.GetOTPMaxFailures()) {
+		return ErrOTPLocked
+	}
+	return nil
+}
+
+// hashOTP returns the SHA-256 digest of a code, so that pending codes are not stored in clear.
+func hashOTP(p_code string) string {
+	sum := sha256.Sum256([]byte(p_code))
+	return hex.EncodeToString(sum[:])
+}
+
+// maskRecipient hides most of an email address or phone number, to show the user where a code
+// was sent without disclosing it.
+func maskRecipient(p_channel string, p_to string) string {
+	if p_to == "" {
+		return ""
+	}
+
+	if p_channel == otp.ChannelEmail {
+		local, domain, _ := strings.Cut(p_to, "@")
+		if runes := []rune(local); len(runes) > 1 {
+			local = string(runes[:1]) + strings.Repeat("*", len(runes)-1)
+		}
+		return local + "@" + domain
+	}
+
+	if len(p_to) <= 4 {
+		return p_to
+	}
+	return strings.Repeat("*", len(p_to)-4) + p_to[len(p_to)-4:]
+}
+
+// endregion Private
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/profile_dto"
+	"cerberus/internal/models"
+	"cerberus/internal/repository"
+	"cerberus/internal/tools/jwt"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/random"
+	"cerberus/internal/tools/validator"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	PersonalTokenPrefix string = "cpat_" // PersonalTokenPrefix starts every personal access token, telling them apart from JWTs and secret scanners where to look.
+
+	personalTokenLookupSize    int           = 6           // Random bytes of the lookup prefix, hex encoded.
+	personalTokenSecretSize    int           = 32          // Random bytes of the secret part of a token.
+	maxPersonalTokens          int           = 50          // Personal access tokens a user may hold.
+	personalTokenTouchInterval time.Duration = time.Minute // Minimum delay between two updates of the last use of a token.
+)
+
+var (
+	ErrPersonalTokenNotFound  = errors.New("personal access token not found")             // ErrPersonalTokenNotFound is returned for malformed, unknown or foreign token IDs.
+	ErrPersonalTokenNameTaken = errors.New("a personal access token has this name")       // ErrPersonalTokenNameTaken is returned when the user already has a token of that name.
+	ErrPersonalTokenLimit     = errors.New("too many personal access tokens, revoke one") // ErrPersonalTokenLimit is returned when the user holds the maximum number of tokens.
+)
+
+// region Public
+
+// CreatePersonalToken creates a personal access token for a user. The token is returned once:
+// only its prefix and hash are stored.
+//
+// The scopes must be scopes of Cerberus (models.PersonalTokenScopes) or of other services
+// (PERSONAL_TOKEN_SCOPES); admin scopes are only granted to admins.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_usrId: The unique ID (string) of the authenticated user.
+//   - p_dto: A pointer to the validated CreatePersonalTokenRequest.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - *models.PersonalToken: The created token.
+//   - string: The token itself, to be shown to the user.
+//   - error: A validator.ValidationErrors for invalid scopes or expiry, ErrPersonalTokenNameTaken,
+//     ErrPersonalTokenLimit, or the database error.
+func CreatePersonalToken(p_db *database.DataRefs, p_usrId string, p_dto *profile_dto.CreatePersonalTokenRequest,
+	p_info RequestInfo) (_ *models.PersonalToken, _ string, err error) {
+	name := strings.TrimSpace(p_dto.Name)
+	defer func() {
+		auditResult(p_db.Postgres, p_info, models.AuditTokenCreate, p_usrId, err,
+			map[string]string{"name": name, "scopes": strings.Join(p_dto.Scopes, " ")})
+	}()
+
+	usr, err := GetUserById(p_db.Postgres, p_usrId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	scopes, err := parseScopes(p_db, p_dto.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := authorizeScopes(usr, scopes); err != nil {
+		return nil, "", err
+	}
+
+	if p_dto.ExpiresAt != nil && !p_dto.ExpiresAt.After(time.Now()) {
+		return nil, "", validator.ValidationErrors{{Field: "expires_at", Rule: "min",
+			Message: "expires_at must be in the future"}}
+	}
+
+	return issuePersonalToken(p_db, usr, name, scopes, p_dto.ExpiresAt)
+}
+
+// ListPersonalTokens retrieves the personal access tokens of a user, expired ones included.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_usrId: The unique ID (string) of the user.
+//
+// Returns:
+//   - []models.PersonalToken: The tokens, oldest first.
+//   - error: An error if the query fails; nil otherwise.
+func ListPersonalTokens(p_db *gorm.DB, p_usrId string) ([]models.PersonalToken, error) {
+	return repository.ListPersonalTokens(p_db, p_usrId)
+}
+
+// RevokePersonalToken deletes a personal access token of a user. It is refused from then on.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database references.
+//   - p_usrId: The unique ID (string) of the authenticated user.
+//   - p_id: The unique ID (string) of the token.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - error: ErrPersonalTokenNotFound if the user owns no such token, or the database error.
+func RevokePersonalToken(p_db *database.DataRefs, p_usrId string, p_id string, p_info RequestInfo) (err error) {
+	defer func() {
+		auditResult(p_db.Postgres, p_info, models.AuditTokenRevoke, p_usrId, err, map[string]string{"token_id": p_id})
+	}()
+
+	if _, err := uuid.Parse(p_id); err != nil {
+		return ErrPersonalTokenNotFound
+	}
+
+	err = repository.DeletePersonalToken(p_db.Postgres, p_usrId, p_id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrPersonalTokenNotFound
+	}
+	return err
+}
+
+// ValidatePersonalToken checks a personal access token like ValidateSession checks access
+// tokens: the token must be known, unexpired, and its user active. Its last use is recorded.
+//
+// The returned claims describe the token as a session would: the "jti" is the ID of the token
+// and the scope is the one granted to it, never empty.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database references.
+//   - p_tkn: The personal access token.
+//
+// Returns:
+//   - *jwt.Claims: The claims of the token.
+//   - *models.User: The user of the token.
+//   - error: ErrInvalidToken, an error wrapping ErrAccountNotActive, or the error of the user lookup.
+func ValidatePersonalToken(p_db *database.DataRefs, p_tkn string) (*jwt.Claims, *models.User, error) {
+	size := len(PersonalTokenPrefix) + 2*personalTokenLookupSize
+	if len(p_tkn) <= size+1 || !strings.HasPrefix(p_tkn, PersonalTokenPrefix) || p_tkn[size] != '_' {
+		return nil, nil, ErrInvalidToken
+	}
+
+	tkn, err := repository.FindPersonalTokenByPrefix(p_db.Postgres, p_tkn[:size])
+	if err != nil {
+		logger.Log("Unknown personal access token", logger.ERROR)
+		return nil, nil, ErrInvalidToken
+	}
+
+	if subtle.ConstantTimeCompare([]byte(tkn.Hash), []byte(hashPersonalToken(p_tkn))) != 1 || tkn.Expired() {
+		logger.Log("Invalid or expired personal access token - "+tkn.Prefix, logger.ERROR)
+		return nil, nil, ErrInvalidToken
+	}
+
+	usr, err := GetUserById(p_db.Postgres, tkn.UserID.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := CheckUserActive(usr); err != nil {
+		logger.Log("Inactive account - "+err.Error(), logger.ERROR)
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if tkn.LastUsedAt == nil || now.Sub(*tkn.LastUsedAt) >= personalTokenTouchInterval {
+		if err := repository.TouchPersonalToken(p_db.Postgres, tkn.ID.String(), now); err != nil {
+			logger.Log("Failed to record personal access token use - "+err.Error(), logger.WARN)
+		}
+	}
+
+	claims := p_db.JWTGen.OpaqueClaims(tkn.ID.String(), usr.ID.String(), strings.Join(tkn.Scopes, " "),
+		tkn.CreatedAt, tkn.ExpiresAt)
+	return claims, usr, nil
+}
+
+// endregion Public
+
+// region Private
+
+// revokeUserPersonalTokens removes every personal access token of a user, when an admin cuts
+// their access. The number of revoked tokens is added to the audit details.
+func revokeUserPersonalTokens(p_db *database.DataRefs, p_usrId string, p_details map[string]string) error {
+	n, err := repository.DeleteUserPersonalTokens(p_db.Postgres, p_usrId)
+	if err != nil {
+		logger.Log("Failed to revoke personal access tokens - "+err.Error(), logger.ERROR)
+		return err
+	}
+	p_details["personal_tokens"] = strconv.FormatInt(n, 10)
+	return nil
+}
+
+// issuePersonalToken creates a personal access token with checked scopes. The name must be
+// free among the tokens of the user, who must hold less than maxPersonalTokens.
+func issuePersonalToken(p_db *database.DataRefs, p_usr *models.User, p_name string, p_scopes []string,
+	p_expiresAt *time.Time) (*models.PersonalToken, string, error) {
+	existing, err := repository.ListPersonalTokens(p_db.Postgres, p_usr.ID.String())
+	if err != nil {
+		return nil, "", err
+	}
+	if len(existing) >= maxPersonalTokens {
+		return nil, "", ErrPersonalTokenLimit
+	}
+	if slices.ContainsFunc(existing, func(t models.PersonalToken) bool { return t.Name == p_name }) {
+		return nil, "", ErrPersonalTokenNameTaken
+	}
+
+	lookup, err := random.Hex(personalTokenLookupSize)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := random.Token(personalTokenSecretSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	prefix := PersonalTokenPrefix + lookup
+	raw := prefix + "_" + secret
+
+	tkn := &models.PersonalToken{
+		UserID:    p_usr.ID,
+		Name:      p_name,
+		Prefix:    prefix,
+		Hash:      hashPersonalToken(raw),
+		Scopes:    p_scopes,
+		ExpiresAt: p_expiresAt,
+	}
+	if err := repository.CreatePersonalToken(p_db.Postgres, tkn); err != nil {
+		logger.Log("Failed to create personal access token - "+err.Error(), logger.ERROR)
+		return nil, "", err
+	}
+
+	return tkn, raw, nil
+}
+
+// parseScopes checks that scopes may be granted to personal access tokens, and removes
+// duplicates.
+func parseScopes(p_db *database.DataRefs, p_scopes []string) ([]string, error) {
+	allowed := slices.Concat(models.PersonalTokenScopes, p_db.ConfigData.GetPersonalTokenScopes())
+
+	scopes := make([]string, 0, len(p_scopes))
+	for _, s := range p_scopes {
+		s = strings.TrimSpace(s)
+		if !slices.Contains(allowed, s) {
+			return nil, validator.ValidationErrors{{Field: "scopes", Rule: "oneof",
+				Message: fmt.Sprintf("scopes must only contain [%s]", strings.Join(allowed, " "))}}
+		}
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+
+	if len(scopes) == 0 {
+		return nil, validator.ValidationErrors{{Field: "scopes", Rule: "required", Message: "scopes is required"}}
+	}
+	return scopes, nil
+}
+
+// authorizeScopes checks that a user may grant scopes: admin scopes are reserved to admins.
+func authorizeScopes(p_usr *models.User, p_scopes []string) error {
+	for _, s := range p_scopes {
+		if (s == models.ScopeAdminRead || s == models.ScopeAdminWrite) && !p_usr.HasRole(models.RoleAdmin) {
+			return validator.ValidationErrors{{Field: "scopes", Rule: "oneof",
+				Message: "admin scopes are only granted to admins"}}
+		}
+	}
+	return nil
+}
+
+// hashPersonalToken returns the SHA-256 hash of a personal access token, hex encoded. Tokens
+// carry 256 random bits, so a fast hash is enough.
+func hashPersonalToken(p_tkn string) string {
+	sum := sha256.Sum256([]byte(p_tkn))
+	return hex.EncodeToString(sum[:])
+}
+
+// endregion Private
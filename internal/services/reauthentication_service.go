@@ -0,0 +1,223 @@
+package services
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/session_dto"
+	"cerberus/internal/models"
+	"cerberus/internal/repository"
+	"cerberus/internal/tools/jwt"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/otp"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	AMRPassword    string = "pwd"   // AMRPassword is the method of password logins, local or directory.
+	AMROTP         string = "otp"   // AMROTP is the method of one-time codes.
+	AMRSMS         string = "sms"   // AMRSMS is added to AMROTP when the code was sent by SMS.
+	AMREmail       string = "email" // AMREmail is the method of login links sent by email.
+	AMRFederated   string = "fed"   // AMRFederated is the method of logins through an OAuth or SAML identity provider.
+	AMRMultiFactor string = "mfa"   // AMRMultiFactor is added when the session proved factors of different kinds.
+
+	ACRSingleFactor string = "aal1" // ACRSingleFactor is the class of sessions that proved a single kind of factor.
+	ACRMultiFactor  string = "aal2" // ACRMultiFactor is the class of sessions that proved factors of different kinds.
+)
+
+var (
+	ErrReauthenticationMethod = errors.New("either a password or a code is required") // ErrReauthenticationMethod is returned when a reauthentication holds no proof, or both.
+)
+
+// amrFactors maps the authentication methods to the kind of factor they prove. Proving two
+// kinds makes a multi-factor session.
+var amrFactors = map[string]string{
+	AMRPassword:  "knowledge",
+	AMROTP:       "possession",
+	AMRSMS:       "possession",
+	AMREmail:     "possession",
+	AMRFederated: "federated",
+}
+
+// region Public
+
+// Reauthenticate proves the identity of the user of a session again, without starting a new
+// session.
+//
+// The user proves either their password, checked by the backends of AUTH_BACKENDS, or a code
+// sent with RequestStepUpOTP. The authentication time of the session is refreshed and the
+// method added to the methods of the session; the access token is replaced by one carrying
+// the new claims, while the refresh token is kept.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_usrId: The unique ID (string) of the authenticated user.
+//   - p_dto: A pointer to the ReauthenticateRequest holding the password or the code.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - *session_dto.ReauthenticateData: The new access token and authentication of the session.
+//   - error: ErrReauthenticationMethod, ErrInvalidCredentials, ErrInvalidOTP,
+//     ErrOTPAttemptsExceeded, ErrOTPLocked, or the storage error.
+func Reauthenticate(p_db *database.DataRefs, p_usrId string, p_dto *session_dto.ReauthenticateRequest,
+	p_info RequestInfo) (_ *session_dto.ReauthenticateData, err error) {
+	var methods []string
+	defer func() {
+		auditResult(p_db.Postgres, p_info, models.AuditStepUp, p_usrId, err,
+			map[string]string{"amr": strings.Join(methods, " ")})
+	}()
+
+	switch {
+	case (p_dto.Password == "") == (p_dto.Code == ""):
+		return nil, ErrReauthenticationMethod
+
+	case p_dto.Password != "":
+		if err := verifyPassword(p_db, p_usrId, p_dto.Password, p_info); err != nil {
+			return nil, err
+		}
+		methods = []string{AMRPassword}
+
+	default:
+		fields, err := verifyOTP(p_db, OTPPurposeStepUp, p_usrId, p_dto.Code)
+		if err != nil {
+			return nil, err
+		}
+		methods = otpMethods(fields["channel"])
+	}
+
+	auth := getSessionAuth(p_db, p_usrId)
+	if auth == nil {
+		auth = &jwt.Authentication{}
+	}
+	*auth = newAuthentication(append(auth.Methods, methods...)...)
+
+	ttl, err := repository.GetRefreshTokenTTL(p_db.Redis, p_usrId)
+	if err != nil {
+		ttl = p_db.ConfigData.RedisData.GetRefreshJWTDuration()
+	}
+	if err := storeSessionAuth(p_db, p_usrId, auth, ttl); err != nil {
+		return nil, err
+	}
+
+	publishRevocation(p_db, p_usrId)
+
+	tkn, err := p_db.JWTGen.GenerateJWT(p_usrId, auth)
+	if err != nil {
+		logger.Log("Failed to generate the JWT token - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+
+	err = repository.StoreJWTToken(p_db.Redis, p_usrId, tkn, p_db.ConfigData.RedisData.GetJWTDuration())
+	if err != nil {
+		logger.Log("Failed to store JWTToken - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+
+	return &session_dto.ReauthenticateData{
+		AccessToken: tkn,
+		AuthTime:    auth.Time,
+		AMR:         auth.Methods,
+		ACR:         auth.Class,
+	}, nil
+}
+
+// endregion Public
+
+// region Private
+
+// newAuthentication describes an authentication happening now with the given methods. The
+// methods are deduplicated, and AMRMultiFactor is added when they prove factors of different
+// kinds.
+func newAuthentication(p_methods ...string) jwt.Authentication {
+	methods := make([]string, 0, len(p_methods)+1)
+	kinds := make(map[string]bool)
+	for _, m := range p_methods {
+		if m == AMRMultiFactor || slices.Contains(methods, m) {
+			continue
+		}
+		methods = append(methods, m)
+		if kind, ok := amrFactors[m]; ok {
+			kinds[kind] = true
+		}
+	}
+
+	class := ACRSingleFactor
+	if len(kinds) > 1 {
+		methods = append(methods, AMRMultiFactor)
+		class = ACRMultiFactor
+	}
+
+	return jwt.Authentication{Time: time.Now(), Methods: methods, Class: class}
+}
+
+// getSessionAuth returns how the user of a session authenticated, or nil if unknown.
+func getSessionAuth(p_db *database.DataRefs, p_usrId string) *jwt.Authentication {
+	data, err := repository.GetSessionAuth(p_db.Redis, p_usrId)
+	if err != nil {
+		return nil
+	}
+
+	var auth jwt.Authentication
+	if err := json.Unmarshal([]byte(data), &auth); err != nil {
+		logger.Log("Malformed session authentication - "+err.Error(), logger.WARN)
+		return nil
+	}
+	return &auth
+}
+
+// storeSessionAuth records how the user of a session authenticated, for the lifetime of the
+// session.
+func storeSessionAuth(p_db *database.DataRefs, p_usrId string, p_auth *jwt.Authentication, p_ttl time.Duration) error {
+	data, err := json.Marshal(p_auth)
+	if err != nil {
+		return err
+	}
+
+	if err := repository.StoreSessionAuth(p_db.Redis, p_usrId, string(data), p_ttl); err != nil {
+		logger.Log("Failed to store session authentication - "+err.Error(), logger.ERROR)
+		return err
+	}
+	return nil
+}
+
+// verifyPassword checks the password of a user with the backends of AUTH_BACKENDS, like
+// AuthenticateUser. The backend deciding must resolve the same account.
+func verifyPassword(p_db *database.DataRefs, p_usrId string, p_password string, p_info RequestInfo) error {
+	usr, err := GetUserById(p_db.Postgres, p_usrId)
+	if err != nil {
+		return err
+	}
+
+	for _, backend := range p_db.ConfigData.GetAuthBackends() {
+		auth, ok := authenticators[backend]
+		if !ok {
+			continue
+		}
+
+		found, err := auth.Authenticate(p_db, usr.Email, p_password, p_info)
+		if errors.Is(err, ErrNotHandled) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if found.ID != usr.ID {
+			return ErrInvalidCredentials
+		}
+		return nil
+	}
+
+	return ErrInvalidCredentials
+}
+
+// otpMethods returns the authentication methods proven by a code sent on a channel.
+func otpMethods(p_channel string) []string {
+	if p_channel == otp.ChannelSMS {
+		return []string{AMROTP, AMRSMS}
+	}
+	return []string{AMROTP}
+}
+
+// endregion Private
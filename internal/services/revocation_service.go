@@ -0,0 +1,119 @@
+package services
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/session_dto"
+	"cerberus/internal/repository"
+	"cerberus/internal/tools/logger"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// region Public
+
+// ListRevocations retrieves the access tokens revoked at or after an instant, so that
+// verifiers checking tokens locally can maintain a deny list.
+//
+// Only tokens that have not expired yet are listed. The same revocation may be returned by
+// two consecutive polls, verifiers must treat the feed as a set.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database references.
+//   - p_since: The earliest revocation time to return.
+//
+// Returns:
+//   - *session_dto.RevocationsResponse: The revocations, oldest first.
+//   - error: An error if the feed cannot be read; nil otherwise.
+func ListRevocations(p_db *database.DataRefs, p_since time.Time) (*session_dto.RevocationsResponse, error) {
+	entries, err := repository.ListRevocationsSince(p_db.Redis, p_since)
+	if err != nil {
+		logger.Log("Failed to read the revocation feed - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+
+	res := &session_dto.RevocationsResponse{
+		Revocations: make([]session_dto.Revocation, 0, len(entries)),
+		NextSince:   p_since,
+	}
+	now := time.Now()
+	for _, entry := range entries {
+		var rev session_dto.Revocation
+		if err := json.Unmarshal([]byte(entry), &rev); err != nil {
+			logger.Log("Skipping malformed revocation - "+err.Error(), logger.WARN)
+			continue
+		}
+		if rev.RevokedAt.After(res.NextSince) {
+			res.NextSince = rev.RevokedAt
+		}
+		if rev.ExpiresAt.After(now) {
+			res.Revocations = append(res.Revocations, rev)
+		}
+	}
+
+	return res, nil
+}
+
+// endregion Public
+// region Private
+
+// publishRevocation adds the access token currently stored for a user to the revocation
+// feed and broadcasts it on the revocation channel. It must be called before the token is
+// removed from the store. Expired or missing tokens are ignored, and failures are logged.
+func publishRevocation(p_db *database.DataRefs, p_usrId string) {
+	tkn, err := repository.GetJWTToken(p_db.Redis, p_usrId)
+	if err != nil {
+		return
+	}
+
+	claims, err := p_db.JWTGen.ValidateJWT(tkn)
+	if err != nil || claims.ID == "" || claims.ExpiresAt == nil {
+		return
+	}
+
+	publishRevocationEntry(p_db, session_dto.Revocation{
+		TokenId:   claims.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+		RevokedAt: time.Now().UTC(),
+	})
+}
+
+// revokeExchangedTokens revokes the tokens issued by token exchange for a user, and publishes
+// their revocation like publishRevocation does. Failures are logged.
+func revokeExchangedTokens(p_db *database.DataRefs, p_usrId string) {
+	tkns, err := repository.RevokeExchangedTokens(p_db.Redis, p_usrId)
+	if err != nil {
+		logger.Log("Failed to revoke exchanged tokens - "+err.Error(), logger.ERROR)
+		return
+	}
+
+	now := time.Now().UTC()
+	for id, exp := range tkns {
+		if exp.After(now) {
+			publishRevocationEntry(p_db, session_dto.Revocation{TokenId: id, ExpiresAt: exp, RevokedAt: now})
+		}
+	}
+}
+
+// publishRevocationEntry adds a revoked token to the revocation feed and broadcasts it on the
+// revocation channel. Failures are logged.
+func publishRevocationEntry(p_db *database.DataRefs, rev session_dto.Revocation) {
+	entry, err := json.Marshal(rev)
+	if err != nil {
+		logger.Log("Failed to encode revocation - "+err.Error(), logger.ERROR)
+		return
+	}
+
+	err = repository.StoreRevocation(p_db.Redis, string(entry), rev.RevokedAt,
+		p_db.ConfigData.RedisData.GetJWTDuration())
+	if err != nil {
+		logger.Log(fmt.Sprintf("Failed to store revocation of %s - %s", rev.TokenId, err.Error()), logger.ERROR)
+	}
+
+	err = repository.PublishRevocation(p_db.Redis, p_db.ConfigData.RedisData.GetRevocationChannel(), string(entry))
+	if err != nil {
+		logger.Log(fmt.Sprintf("Failed to publish revocation of %s - %s", rev.TokenId, err.Error()), logger.ERROR)
+	}
+}
+
+// endregion Private
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/auth_dto"
+	"cerberus/internal/models"
+	"cerberus/internal/repository"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/oauth"
+	"cerberus/internal/tools/random"
+	"cerberus/internal/tools/samlsp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// samlIdentityPrefix prefixes the provider name of SAML identities, so they never collide with
+// the OAuth providers.
+const samlIdentityPrefix string = "saml:"
+
+var (
+	ErrInvalidSAMLRequest    = errors.New("invalid or expired SAML request")                                 // ErrInvalidSAMLRequest is returned when a response does not match a pending login.
+	ErrEmailDomainNotAllowed = errors.New("email domain not allowed for provider")                           // ErrEmailDomainNotAllowed is returned when a provider asserts an address outside of its domains.
+	ErrIdentityLinkRequired  = errors.New("an account uses this email address, log in to link the identity") // ErrIdentityLinkRequired is returned when an identity must be linked explicitly to an existing account.
+	ErrIdentityAlreadyLinked = errors.New("identity already linked to another account")                      // ErrIdentityAlreadyLinked is returned when linking an identity used by another user.
+)
+
+// region Public
+
+// StartSAMLLogin starts a login with a SAML identity provider.
+//
+// An authentication request is created and stored in Redis under a random relay state for the
+// duration of the login. The relay state comes back with the response.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_ctx: The context of the request.
+//   - p_provider: The name of the provider.
+//   - p_useCookies: Whether the session will be a browser session, set in cookies.
+//
+// Returns:
+//   - string: The URL of the provider the browser must be redirected to.
+//   - string: The relay state of the login.
+//   - error: ErrUnknownProvider, or an error if the provider or Redis cannot be reached.
+func StartSAMLLogin(p_db *database.DataRefs, p_ctx context.Context, p_provider string, p_useCookies bool) (string, string, error) {
+	return startSAMLRequest(p_db, p_ctx, auth_dto.SAMLRequest{Provider: p_provider, UseCookies: p_useCookies})
+}
+
+// StartSAMLLink starts linking an identity of a SAML identity provider to the account of a
+// logged in user. The request goes through the provider like StartSAMLLogin, and the ACS links
+// the asserted identity to the user instead of logging in.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_ctx: The context of the request.
+//   - p_provider: The name of the provider.
+//   - p_usrId: The unique ID (string) of the user linking the identity.
+//
+// Returns:
+//   - string: The URL of the provider the browser must be redirected to.
+//   - string: The relay state of the request.
+//   - error: ErrUnknownProvider, or an error if the provider or Redis cannot be reached.
+func StartSAMLLink(p_db *database.DataRefs, p_ctx context.Context, p_provider string, p_usrId string) (string, string, error) {
+	return startSAMLRequest(p_db, p_ctx, auth_dto.SAMLRequest{Provider: p_provider, LinkUserID: p_usrId})
+}
+
+// CompleteSAMLLogin handles the response posted to the ACS and returns the user to log in.
+//
+// The pending login is consumed, so a response cannot be replayed. The response is then
+// validated and its identity resolved like a federated login (see CompleteOAuthLogin). The
+// mapped attributes are copied to the profile of the user on every login.
+//
+// Identity providers are trusted to assert email addresses within their domains only, and an
+// existing account is only linked automatically when it has neither a password nor another
+// identity. Other accounts link the identity explicitly, with StartSAMLLink: the response then
+// links the identity to the user who started the request, who is returned without logging in.
+//
+// Failures are recorded in the audit log; the successful login is recorded by LoginUser.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - r: The request posted to the ACS, with its form parsed.
+//   - p_provider: The name of the provider of the ACS route.
+//   - p_relayState: The relay state received with the response.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - *models.User: The user to log in.
+//   - *auth_dto.SAMLRequest: The pending login, telling whether it is a browser session or a link.
+//   - error: ErrUnknownProvider, ErrInvalidSAMLRequest, ErrEmailDomainNotAllowed, ErrEmailNotVerified,
+//     ErrSignupDisabled, ErrIdentityLinkRequired, ErrIdentityAlreadyLinked, an error wrapping
+//     ErrAccountNotActive, or an error wrapping samlsp.ErrInvalidResponse.
+func CompleteSAMLLogin(p_db *database.DataRefs, r *http.Request, p_provider string, p_relayState string,
+	p_info RequestInfo) (_ *models.User, _ *auth_dto.SAMLRequest, err error) {
+	var usrId string
+	auditType := models.AuditLoginFailure
+	defer func() {
+		if err != nil {
+			auditResult(p_db.Postgres, p_info, auditType, usrId, err,
+				map[string]string{"provider": samlIdentityPrefix + p_provider})
+		}
+	}()
+
+	provider, ok := p_db.SAML[p_provider]
+	if !ok {
+		return nil, nil, ErrUnknownProvider
+	}
+
+	data, err := repository.TakeSAMLRequest(p_db.Redis, p_relayState)
+	if err != nil {
+		return nil, nil, ErrInvalidSAMLRequest
+	}
+
+	var pending auth_dto.SAMLRequest
+	if err := json.Unmarshal([]byte(data), &pending); err != nil || pending.Provider != p_provider {
+		return nil, nil, ErrInvalidSAMLRequest
+	}
+	if pending.LinkUserID != "" {
+		auditType, usrId = models.AuditIdentityLink, pending.LinkUserID
+	}
+
+	idt, err := provider.ParseResponse(r, pending.RequestID)
+	if err != nil {
+		logger.Log(fmt.Sprintf("SAML login with %s failed - %s", p_provider, err.Error()), logger.ERROR)
+		return nil, nil, err
+	}
+
+	pCfg := p_db.ConfigData.SAMLData.Providers[p_provider]
+	if idt.Email != "" && !pCfg.AllowsEmail(idt.Email) {
+		return nil, nil, ErrEmailDomainNotAllowed
+	}
+
+	asserted := &oauth.Identity{Subject: idt.Subject, Email: idt.Email, EmailVerified: true, Name: idt.Name}
+	if pending.LinkUserID != "" {
+		usr, err := linkIdentity(p_db, pending.LinkUserID, samlIdentityPrefix+p_provider, asserted, p_info)
+		if err != nil {
+			return nil, nil, err
+		}
+		return usr, &pending, nil
+	}
+
+	usr, err := resolveIdentity(p_db, samlIdentityPrefix+p_provider, asserted, pCfg.DisableSignup, true, p_info)
+	if usr != nil {
+		usrId = usr.ID.String()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	syncSAMLAttributes(p_db, usr, idt)
+	return usr, &pending, nil
+}
+
+// endregion Public
+
+// region Private
+
+// linkIdentity links a provider identity to the account of a logged in user. Linking an
+// identity the user already has is a no-op.
+func linkIdentity(p_db *database.DataRefs, p_usrId string, p_provider string, p_idt *oauth.Identity,
+	p_info RequestInfo) (*models.User, error) {
+	usr, err := GetUserById(p_db.Postgres, p_usrId)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckUserActive(usr); err != nil {
+		return nil, err
+	}
+
+	link, err := repository.FindIdentity(p_db.Postgres, p_provider, p_idt.Subject)
+	if err == nil {
+		if link.UserID != usr.ID {
+			return nil, ErrIdentityAlreadyLinked
+		}
+		return usr, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := createIdentityLink(p_db, usr, p_provider, p_idt, p_info); err != nil {
+		return nil, err
+	}
+	return usr, nil
+}
+
+// startSAMLRequest sends an authentication request for a pending login or link, stored in
+// Redis under a random relay state.
+func startSAMLRequest(p_db *database.DataRefs, p_ctx context.Context, p_pending auth_dto.SAMLRequest) (string, string, error) {
+	provider, ok := p_db.SAML[p_pending.Provider]
+	if !ok {
+		return "", "", ErrUnknownProvider
+	}
+
+	relayState, err := random.Token(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL, requestID, err := provider.AuthnRequestURL(p_ctx, relayState)
+	if err != nil {
+		logger.Log(fmt.Sprintf("Failed to reach the %s SAML provider - %s", p_pending.Provider, err.Error()), logger.ERROR)
+		return "", "", err
+	}
+
+	p_pending.RequestID = requestID
+	data, err := json.Marshal(p_pending)
+	if err != nil {
+		return "", "", err
+	}
+	if err := repository.StoreSAMLRequest(p_db.Redis, relayState, string(data),
+		p_db.ConfigData.SAMLData.GetRequestDuration()); err != nil {
+		logger.Log("Failed to store the SAML request - "+err.Error(), logger.ERROR)
+		return "", "", err
+	}
+
+	return authURL, relayState, nil
+}
+
+// syncSAMLAttributes copies the mapped attributes of an assertion to the profile of the user.
+// Values longer than the profile limits are ignored.
+func syncSAMLAttributes(p_db *database.DataRefs, p_usr *models.User, p_idt *samlsp.Identity) {
+	attrs := maps.Clone(p_usr.Attributes)
+	if attrs == nil {
+		attrs = make(map[string]string)
+	}
+
+	for k, v := range p_idt.Attributes {
+		if utf8.RuneCountInString(k) > maxAttributeKeyLength || utf8.RuneCountInString(v) > maxAttributeValueLength {
+			continue
+		}
+		if _, ok := attrs[k]; !ok && len(attrs) >= maxProfileAttributes {
+			continue
+		}
+		attrs[k] = v
+	}
+
+	if maps.Equal(attrs, p_usr.Attributes) {
+		return
+	}
+
+	p_usr.Attributes = attrs
+	if err := repository.UpdateProfile(p_db.Postgres, p_usr); err != nil {
+		logger.Log("Failed to update SAML attributes - "+err.Error(), logger.WARN)
+	}
+}
+
+// endregion Private
@@ -5,7 +5,19 @@ import (
 	"cerberus/internal/dto/session_dto"
 	"cerberus/internal/models"
 	"cerberus/internal/repository"
+	"cerberus/internal/tools/jwt"
 	"cerberus/internal/tools/logger"
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrInvalidRefreshToken is returned when a refresh token does not match the stored one.
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrInvalidToken is returned when an access token is malformed, expired or badly signed.
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrRevokedToken is returned when an access token is no longer the active token of its user.
+	ErrRevokedToken = errors.New("revoked token")
 )
 
 // LoginUser generates and stores JWT and refresh tokens for a user.
@@ -15,19 +27,37 @@ import (
 // 2. Generates a refresh token for the user.
 // 3. Stores the JWT token in Redis.
 // 4. Stores the refresh token in Redis.
+// 5. Records how the user authenticated, carried by the "auth_time", "amr" and "acr" claims
+// of the access tokens of the session.
 //
 // If any step fails, the function will log the error and return nil with the error.
 // If the refresh token storage fails, it will also revoke the previously stored JWT token.
+// The outcome is recorded in the audit log.
 //
 // Parameters:
 //   - p_db: A pointer to the DataRefs structure containing database and configuration references.
 //   - p_usr: A pointer to the User model representing the user to be logged in.
+//   - p_info: The request metadata recorded in the audit log.
+//   - p_methods: The authentication methods the user logged in with (e.g., AMRPassword).
 //
 // Returns:
 //   - *session_dto.LoginData: A pointer to LoginData containing the generated tokens if successful.
 //   - error: An error if any step in the login process fails, nil otherwise.
-func LoginUser(p_db *database.DataRefs, p_usr *models.User) (*session_dto.LoginData, error) {
-	tkn, err := p_db.JWTGen.GenerateJWT(p_usr.ID.String())
+func LoginUser(p_db *database.DataRefs, p_usr *models.User, p_info RequestInfo,
+	p_methods ...string) (_ *session_dto.LoginData, err error) {
+	auth := newAuthentication(p_methods...)
+
+	p_info.ActorID = p_usr.ID.String()
+	defer func() {
+		outcome := models.AuditLoginSuccess
+		if err != nil {
+			outcome = models.AuditLoginFailure
+		}
+		auditResult(p_db.Postgres, p_info, outcome, p_usr.ID.String(), err,
+			map[string]string{"amr": strings.Join(auth.Methods, " ")})
+	}()
+
+	tkn, err := p_db.JWTGen.GenerateJWT(p_usr.ID.String(), &auth)
 	if err != nil {
 		logger.Log("Failed to generate the JWT token - "+err.Error(), logger.ERROR)
 		return nil, err
@@ -53,6 +83,12 @@ func LoginUser(p_db *database.DataRefs, p_usr *models.User) (*session_dto.LoginD
 		return nil, err
 	}
 
+	if err := storeSessionAuth(p_db, p_usr.ID.String(), &auth, p_db.ConfigData.RedisData.GetRefreshJWTDuration()); err != nil {
+		RevokeAllSessionTokensToUser(p_db, p_usr.ID.String(), "login_failure")
+		return nil, err
+	}
+
+	EmitEvent(p_db, models.EventSessionCreated, map[string]string{"user_id": p_usr.ID.String(), "ip": p_info.IP})
 	return &session_dto.LoginData{
 		AccessToken:  tkn,
 		RefreshToken: rTkn,
@@ -64,15 +100,144 @@ func LoginUser(p_db *database.DataRefs, p_usr *models.User) (*session_dto.LoginD
 // This function is typically used when logging out a user or invalidating their session.
 // It attempts to revoke both tokens, even if one revocation fails.
 //
+// The tokens issued by token exchange for the user are revoked as well. The revoked access
+// tokens are published on the revocation feed (see ListRevocations) and a
+// "session.revoked" event is emitted so that internal consumers can drop cached sessions.
+//
 // Parameters:
-//   - p_db: A pointer to the RedisPack structure for database operations.
+//   - p_db: A pointer to the DataRefs structure containing database references.
 //   - p_usr_id: The unique ID (string) of the user whose token is being checked
+//   - p_reason: Why the session is revoked (e.g., "logout"), forwarded in the event.
 //
 // Note: This function does not return any error. Failures in token revocation are handled silently.
 // TODO: This should also return an error
-func RevokeAllSessionTokensToUser(p_db *database.RedisPack, p_usr_id string) {
-	repository.RevokeJWTToken(p_db, p_usr_id)
-	repository.RevokeRefreshToken(p_db, p_usr_id)
+func RevokeAllSessionTokensToUser(p_db *database.DataRefs, p_usr_id string, p_reason string) {
+	publishRevocation(p_db, p_usr_id)
+	revokeExchangedTokens(p_db, p_usr_id)
+
+	repository.RevokeJWTToken(p_db.Redis, p_usr_id)
+	repository.RevokeRefreshToken(p_db.Redis, p_usr_id)
+
+	EmitEvent(p_db, models.EventSessionRevoked, map[string]string{"user_id": p_usr_id, "reason": p_reason})
+}
+
+// rotateSessionTokens revokes the access and refresh tokens of a session being refreshed. Unlike
+// RevokeAllSessionTokensToUser, it leaves the tokens issued by token exchange alone and emits no
+// event, since the session itself goes on.
+func rotateSessionTokens(p_db *database.DataRefs, p_usrId string) {
+	publishRevocation(p_db, p_usrId)
+
+	repository.RevokeJWTToken(p_db.Redis, p_usrId)
+	repository.RevokeRefreshToken(p_db.Redis, p_usrId)
+}
+
+// LogoutUser ends the session of a user by revoking all of their session tokens, and records
+// the logout in the audit log.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database references.
+//   - p_usrId: The unique ID (string) of the user logging out.
+//   - p_info: The request metadata recorded in the audit log.
+func LogoutUser(p_db *database.DataRefs, p_usrId string, p_info RequestInfo) {
+	RevokeAllSessionTokensToUser(p_db, p_usrId, "logout")
+	repository.RevokeSessionAuth(p_db.Redis, p_usrId)
+
+	p_info.ActorID = p_usrId
+	RecordAuditEvent(p_db.Postgres, p_info, models.AuditLogout, p_usrId, models.OutcomeSuccess, nil)
+}
+
+// RefreshSession exchanges a refresh token for a new pair of tokens.
+//
+// The refresh token must match the one stored for the user and the account must still be
+// active. The previous tokens are revoked before the new ones are issued, and the outcome is
+// recorded in the audit log. The session goes on: the tokens issued by token exchange are kept,
+// and no "session.revoked" event is emitted.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_usrId: The unique ID (string) of the user refreshing their session.
+//   - p_refreshToken: The refresh token presented by the client.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - *session_dto.RefreshData: The new tokens if successful.
+//   - error: ErrInvalidRefreshToken, an error wrapping ErrAccountNotActive, or the storage error.
+func RefreshSession(p_db *database.DataRefs, p_usrId string, p_refreshToken string, p_info RequestInfo) (_ *session_dto.RefreshData, err error) {
+	p_info.ActorID = p_usrId
+	defer func() { auditResult(p_db.Postgres, p_info, models.AuditRefresh, p_usrId, err, nil) }()
+
+	valid, err := ValidateRefreshToken(p_db, p_usrId, p_refreshToken)
+	if err != nil || !valid {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if err := EnsureUserActive(p_db.Postgres, p_usrId); err != nil {
+		RevokeAllSessionTokensToUser(p_db, p_usrId, "account_inactive")
+		return nil, err
+	}
+
+	rotateSessionTokens(p_db, p_usrId)
+	return GenerateTokensAndSave(p_db, p_usrId)
+}
+
+// ValidateSession checks that an access token belongs to a live session: the token must be
+// valid, still be the active token of its user, and the account must be active.
+//
+// Personal access tokens, recognized by PersonalTokenPrefix, are accepted as well and checked
+// with ValidatePersonalToken. Their claims always hold a scope, unlike those of sessions.
+//
+// Tokens issued by token exchange (see ExchangeToken) are recognized by their scope and must
+// not have been revoked since. Impersonation tokens are refused once their actor is no longer
+// an active admin.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database references.
+//   - p_tkn: The access token.
+//
+// Returns:
+//   - *jwt.Claims: The claims of the token.
+//   - *models.User: The user of the session.
+//   - error: ErrInvalidToken, ErrRevokedToken, an error wrapping ErrAccountNotActive, or the
+//     error of the user lookup.
+func ValidateSession(p_db *database.DataRefs, p_tkn string) (*jwt.Claims, *models.User, error) {
+	if strings.HasPrefix(p_tkn, PersonalTokenPrefix) {
+		return ValidatePersonalToken(p_db, p_tkn)
+	}
+
+	claims, err := p_db.JWTGen.ValidateJWT(p_tkn)
+	if err != nil {
+		logger.Log("Invalid token - "+err.Error(), logger.ERROR)
+		return nil, nil, ErrInvalidToken
+	}
+
+	var isValid bool
+	if claims.Scope != "" {
+		isValid, err = repository.IsExchangedTokenActive(p_db.Redis, claims.UserID, claims.ID)
+	} else {
+		isValid, err = IsTokenActive(p_db, claims.UserID, p_tkn)
+	}
+	if err != nil || !isValid {
+		logger.Log("Revoked token", logger.ERROR)
+		return nil, nil, ErrRevokedToken
+	}
+
+	usr, err := GetUserById(p_db.Postgres, claims.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := CheckUserActive(usr); err != nil {
+		logger.Log("Inactive account - "+err.Error(), logger.ERROR)
+		return nil, nil, err
+	}
+
+	if claims.Act != nil {
+		if err := checkImpersonator(p_db, claims.Act.Subject); err != nil {
+			logger.Log("Impersonation token refused - "+err.Error(), logger.ERROR)
+			return nil, nil, ErrRevokedToken
+		}
+	}
+
+	return claims, usr, nil
 }
 
 // IsTokenActive checks if a given JWT token is active for a specific user.
@@ -112,9 +277,13 @@ func ValidateRefreshToken(p_db *database.DataRefs, p_userId string, p_token stri
 
 // GenerateTokensAndSave generates a new JWT access token and a refresh token for the given user.
 // It stores both tokens in Redis and returns the generated tokens in a RefreshData struct.
+// The access token carries the authentication recorded for the session by LoginUser, which
+// is kept as long as the new refresh token.
 // If any step fails, it logs the error and returns nil and the error.
 func GenerateTokensAndSave(p_db *database.DataRefs, p_userId string) (*session_dto.RefreshData, error) {
-	tkn, err := p_db.JWTGen.GenerateJWT(p_userId)
+	auth := getSessionAuth(p_db, p_userId)
+
+	tkn, err := p_db.JWTGen.GenerateJWT(p_userId, auth)
 	if err != nil {
 		logger.Log("Failed to generate the JWT token - "+err.Error(), logger.ERROR)
 		return nil, err
@@ -140,6 +309,10 @@ func GenerateTokensAndSave(p_db *database.DataRefs, p_userId string) (*session_d
 		return nil, err
 	}
 
+	if auth != nil {
+		storeSessionAuth(p_db, p_userId, auth, p_db.ConfigData.RedisData.GetRefreshJWTDuration())
+	}
+
 	return &session_dto.RefreshData{
 		AccessToken:  tkn,
 		RefreshToken: rTkn,
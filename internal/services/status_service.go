@@ -0,0 +1,138 @@
+package services
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/models"
+	"cerberus/internal/repository"
+	"cerberus/internal/tools/logger"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// statusTransitions lists, for each account status, the statuses it can move to.
+var statusTransitions = map[string][]string{
+	models.StatusPending:  {models.StatusActive, models.StatusDisabled},
+	models.StatusActive:   {models.StatusDisabled, models.StatusLocked},
+	models.StatusLocked:   {models.StatusActive, models.StatusDisabled},
+	models.StatusDisabled: {models.StatusActive},
+}
+
+var (
+	ErrInvalidStatus           = errors.New("invalid status")            // ErrInvalidStatus is returned for unknown status values.
+	ErrInvalidStatusTransition = errors.New("invalid status transition") // ErrInvalidStatusTransition is returned when a transition is not allowed.
+	ErrAccountNotActive        = errors.New("account not active")        // ErrAccountNotActive is wrapped by errors returned for non-active accounts.
+)
+
+// region Public
+
+// CanTransition reports whether an account can move from one status to another.
+//
+// Parameters:
+//   - p_from: The current status.
+//   - p_to: The requested status.
+//
+// Returns:
+//   - bool: true if the transition is allowed.
+func CanTransition(p_from string, p_to string) bool {
+	return slices.Contains(statusTransitions[p_from], p_to)
+}
+
+// ChangeUserStatus moves a user to a new status, recording the reason and the time of the change.
+//
+// Only the transitions listed in statusTransitions are accepted. When the new status is not
+// active, every session of the user is revoked so the change takes effect immediately.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database references.
+//   - p_usrId: The unique ID (string) of the target user.
+//   - p_status: The new status.
+//   - p_reason: Why the status is being changed.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - *models.User: The updated user.
+//   - error: ErrUserNotFound, ErrInvalidStatus, ErrInvalidStatusTransition or the database error.
+func ChangeUserStatus(p_db *database.DataRefs, p_usrId string, p_status string, p_reason string,
+	p_info RequestInfo) (_ *models.User, err error) {
+	defer func() {
+		auditResult(p_db.Postgres, p_info, models.AuditAdminStatusChange, p_usrId, err,
+			map[string]string{"status": p_status, "reason": p_reason})
+	}()
+
+	if _, ok := statusTransitions[p_status]; !ok {
+		return nil, fmt.Errorf("%w - %s", ErrInvalidStatus, p_status)
+	}
+
+	usr, err := GetUserForAdmin(p_db.Postgres, p_usrId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !CanTransition(usr.Status, p_status) {
+		return nil, fmt.Errorf("%w - %s to %s", ErrInvalidStatusTransition, usr.Status, p_status)
+	}
+
+	now := time.Now()
+	usr.Status = p_status
+	usr.StatusReason = p_reason
+	usr.StatusChangedAt = &now
+
+	if err := repository.UpdateStatus(p_db.Postgres, usr); err != nil {
+		logger.Log("Failed to update user status - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+
+	if p_status != models.StatusActive {
+		RevokeAllSessionTokensToUser(p_db, p_usrId, "status_change")
+	}
+
+	EmitEvent(p_db, models.EventUserStatusChanged, map[string]string{
+		"user_id": p_usrId, "status": p_status, "reason": p_reason,
+	})
+	if p_status == models.StatusDisabled {
+		EmitEvent(p_db, models.EventUserDisabled, map[string]string{
+			"user_id": p_usrId, "reason": p_reason,
+		})
+	}
+
+	logger.Log(fmt.Sprintf("User %s status changed to %s - %s", p_usrId, p_status, p_reason), logger.INFO)
+	return usr, nil
+}
+
+// CheckUserActive returns an error wrapping ErrAccountNotActive if the user is not active.
+//
+// Parameters:
+//   - p_usr: A pointer to the user model.
+//
+// Returns:
+//   - error: nil if the user is active.
+func CheckUserActive(p_usr *models.User) error {
+	if p_usr.Status != models.StatusActive {
+		return fmt.Errorf("%w - %s", ErrAccountNotActive, p_usr.Status)
+	}
+	return nil
+}
+
+// EnsureUserActive loads a user and checks that the account is active. It is used by the
+// session checks so that live sessions stop working as soon as an account is suspended.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection.
+//   - p_usrId: The unique ID (string) of the user.
+//
+// Returns:
+//   - error: An error if the user does not exist or is not active, nil otherwise.
+func EnsureUserActive(p_db *gorm.DB, p_usrId string) error {
+	usr, err := GetUserById(p_db, p_usrId)
+	if err != nil {
+		return err
+	}
+
+	return CheckUserActive(usr)
+}
+
+// endregion Public
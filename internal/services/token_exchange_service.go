@@ -0,0 +1,235 @@
+package services
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/oauth2_dto"
+	"cerberus/internal/models"
+	"cerberus/internal/repository"
+	"cerberus/internal/tools/jwt"
+	"cerberus/internal/tools/logger"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	TokenExchangeGrantType string = "urn:ietf:params:oauth:grant-type:token-exchange" // TokenExchangeGrantType is the grant_type exchanging a token for another (RFC 8693).
+	AccessTokenType        string = "urn:ietf:params:oauth:token-type:access_token"   // AccessTokenType identifies access tokens in token exchange.
+	JWTTokenType           string = "urn:ietf:params:oauth:token-type:jwt"            // JWTTokenType identifies JWTs in token exchange; the issued tokens are both.
+	UserIdTokenType        string = "urn:cerberus:params:oauth:token-type:user_id"    // UserIdTokenType identifies the user impersonated by an admin in token exchange.
+)
+
+var (
+	ErrInvalidRequest      = errors.New("invalid request")           // ErrInvalidRequest is returned for missing parameters, unsupported token types, or invalid tokens to exchange.
+	ErrInvalidTarget       = errors.New("invalid audience")          // ErrInvalidTarget is returned for audiences the exchanged token cannot target.
+	ErrImpersonationDenied = errors.New("impersonation not allowed") // ErrImpersonationDenied is returned when the actor or the impersonated user is not allowed.
+)
+
+// region Public
+
+// ExchangeToken issues a token in exchange for another (RFC 8693), in one of two modes:
+//
+//   - Delegation: the subject token is an access token (session, personal access token or
+//     exchanged token). The new token acts as the same user, with at most the scopes and the
+//     audiences of the subject token, and never outlives it. Services use it to call other
+//     services with no more rights than needed.
+//   - Impersonation: the actor token is the session of an admin and the subject token the ID
+//     of a user (UserIdTokenType). The new token acts as the user, names the admin in its
+//     "act" claim, and is recorded in the audit log. Admins cannot be impersonated.
+//
+// Exchanged tokens always carry a scope, so that they are refused wherever a session is
+// required. They last JWT_DURATION at most and are revoked with the sessions of their user.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_dto: A pointer to the TokenExchangeRequest.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - *oauth2_dto.TokenResponse: The issued token.
+//   - error: An error wrapping ErrInvalidRequest, ErrInvalidScope, ErrInvalidTarget or
+//     ErrImpersonationDenied, or an error if the token cannot be issued.
+func ExchangeToken(p_db *database.DataRefs, p_dto *oauth2_dto.TokenExchangeRequest,
+	p_info RequestInfo) (*oauth2_dto.TokenResponse, error) {
+	if p_dto.SubjectToken == "" || p_dto.SubjectTokenType == "" {
+		return nil, fmt.Errorf("%w - subject_token and subject_token_type are required", ErrInvalidRequest)
+	}
+	switch p_dto.RequestedTokenType {
+	case "", AccessTokenType, JWTTokenType:
+	default:
+		return nil, fmt.Errorf("%w - unsupported requested_token_type", ErrInvalidRequest)
+	}
+
+	if p_dto.ActorToken != "" || p_dto.SubjectTokenType == UserIdTokenType {
+		return impersonate(p_db, p_dto, p_info)
+	}
+	return delegate(p_db, p_dto)
+}
+
+// endregion Public
+
+// region Private
+
+// delegate issues a token acting as the user of the subject token, with narrower rights.
+func delegate(p_db *database.DataRefs, p_dto *oauth2_dto.TokenExchangeRequest) (*oauth2_dto.TokenResponse, error) {
+	if p_dto.SubjectTokenType != AccessTokenType && p_dto.SubjectTokenType != JWTTokenType {
+		return nil, fmt.Errorf("%w - unsupported subject_token_type", ErrInvalidRequest)
+	}
+
+	claims, usr, err := ValidateSession(p_db, p_dto.SubjectToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w - invalid subject_token", ErrInvalidRequest)
+	}
+
+	scopes, granted := strings.Fields(p_dto.Scope), strings.Fields(claims.Scope)
+	if len(granted) == 0 {
+		if scopes, err = parseScopes(p_db, scopes); err != nil {
+			return nil, fmt.Errorf("%w - %s", ErrInvalidScope, err)
+		}
+		if err := authorizeScopes(usr, scopes); err != nil {
+			return nil, fmt.Errorf("%w - %s", ErrInvalidScope, err)
+		}
+	} else if len(scopes) == 0 {
+		scopes = granted
+	} else if i := slices.IndexFunc(scopes, func(s string) bool { return !slices.Contains(granted, s) }); i >= 0 {
+		return nil, fmt.Errorf("%w - %s is not granted to subject_token", ErrInvalidScope, scopes[i])
+	}
+
+	// Personal access tokens carry no audience: they may target any of Cerberus.
+	allowed := []string(claims.Audience)
+	if len(allowed) == 0 {
+		allowed = p_db.JWTGen.Audiences
+	}
+	audiences, err := exchangeAudiences(p_db, p_dto.Audience, allowed)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(p_db.JWTGen.Duration)
+	if claims.ExpiresAt != nil && claims.ExpiresAt.Before(expiresAt) {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	return issueExchangedToken(p_db, usr.ID.String(), scopes, audiences, claims.Act, expiresAt)
+}
+
+// impersonate issues a token acting as a user on behalf of an admin, and audits it.
+func impersonate(p_db *database.DataRefs, p_dto *oauth2_dto.TokenExchangeRequest,
+	p_info RequestInfo) (_ *oauth2_dto.TokenResponse, err error) {
+	if p_dto.SubjectTokenType != UserIdTokenType || p_dto.ActorToken == "" {
+		return nil, fmt.Errorf("%w - impersonation requires a user_id subject_token and an actor_token", ErrInvalidRequest)
+	}
+	if p_dto.ActorTokenType != AccessTokenType && p_dto.ActorTokenType != JWTTokenType {
+		return nil, fmt.Errorf("%w - unsupported actor_token_type", ErrInvalidRequest)
+	}
+
+	actorClaims, actor, err := ValidateSession(p_db, p_dto.ActorToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w - invalid actor_token", ErrInvalidRequest)
+	}
+
+	p_info.ActorID = actor.ID.String()
+	// The target is only recorded once resolved: the subject_token is never written to the log.
+	var targetId string
+	details := map[string]string{"scope": p_dto.Scope, "audience": strings.Join(p_dto.Audience, " ")}
+	defer func() {
+		auditResult(p_db.Postgres, p_info, models.AuditImpersonation, targetId, err, details)
+	}()
+
+	if actorClaims.Scope != "" || !actor.HasRole(models.RoleAdmin) {
+		return nil, fmt.Errorf("%w - actor_token must be the session of an admin", ErrImpersonationDenied)
+	}
+
+	usr, err := GetUserForAdmin(p_db.Postgres, p_dto.SubjectToken)
+	if err != nil {
+		return nil, err
+	}
+	targetId = usr.ID.String()
+	if usr.HasRole(models.RoleAdmin) {
+		return nil, fmt.Errorf("%w - admins cannot be impersonated", ErrImpersonationDenied)
+	}
+	if err := CheckUserActive(usr); err != nil {
+		return nil, err
+	}
+
+	scopes, err := parseScopes(p_db, strings.Fields(p_dto.Scope))
+	if err != nil {
+		return nil, fmt.Errorf("%w - %s", ErrInvalidScope, err)
+	}
+	if err := authorizeScopes(usr, scopes); err != nil {
+		return nil, fmt.Errorf("%w - %s", ErrInvalidScope, err)
+	}
+
+	audiences, err := exchangeAudiences(p_db, p_dto.Audience, p_db.JWTGen.Audiences)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := issueExchangedToken(p_db, usr.ID.String(), scopes, audiences,
+		&jwt.Actor{Subject: actor.ID.String()}, time.Now().Add(p_db.JWTGen.Duration))
+	if err != nil {
+		return nil, err
+	}
+	details["scope"] = res.Scope
+	return res, nil
+}
+
+// exchangeAudiences checks the audiences requested for an exchanged token against those
+// allowed, which it defaults to.
+func exchangeAudiences(p_db *database.DataRefs, p_requested []string, p_allowed []string) ([]string, error) {
+	if len(p_requested) == 0 {
+		return p_allowed, nil
+	}
+
+	for _, a := range p_requested {
+		if !slices.Contains(p_allowed, a) || !slices.Contains(p_db.JWTGen.Audiences, a) {
+			return nil, fmt.Errorf("%w - %s", ErrInvalidTarget, a)
+		}
+	}
+	return slices.Compact(slices.Sorted(slices.Values(p_requested))), nil
+}
+
+// issueExchangedToken signs an exchanged token and records it so that ValidateSession
+// accepts it.
+func issueExchangedToken(p_db *database.DataRefs, p_usrId string, p_scopes []string, p_audiences []string,
+	p_act *jwt.Actor, p_expiresAt time.Time) (*oauth2_dto.TokenResponse, error) {
+	scope := strings.Join(p_scopes, " ")
+	tkn, claims, err := p_db.JWTGen.GenerateExchangedJWT(p_usrId, scope, p_audiences, p_act, p_expiresAt)
+	if err != nil {
+		logger.Log("Failed to generate the exchanged token - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+
+	err = repository.StoreExchangedToken(p_db.Redis, p_usrId, claims.ID, p_expiresAt, p_db.JWTGen.Duration)
+	if err != nil {
+		logger.Log("Failed to store the exchanged token - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+
+	return &oauth2_dto.TokenResponse{
+		AccessToken:     tkn,
+		TokenType:       "Bearer",
+		ExpiresIn:       int(time.Until(p_expiresAt).Seconds()),
+		Scope:           scope,
+		IssuedTokenType: AccessTokenType,
+	}, nil
+}
+
+// checkImpersonator checks that the actor of an impersonation token is still an active admin.
+func checkImpersonator(p_db *database.DataRefs, p_actorId string) error {
+	actor, err := GetUserById(p_db.Postgres, p_actorId)
+	if err != nil {
+		return err
+	}
+	if err := CheckUserActive(actor); err != nil {
+		return err
+	}
+	if !actor.HasRole(models.RoleAdmin) {
+		return ErrImpersonationDenied
+	}
+	return nil
+}
+
+// endregion Private
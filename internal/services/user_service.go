@@ -1,18 +1,42 @@
 package services
 
 import (
+	"cerberus/internal/database"
 	"cerberus/internal/dto/auth_dto"
+	"cerberus/internal/dto/profile_dto"
 	"cerberus/internal/dto/session_dto"
 	"cerberus/internal/models"
 	"cerberus/internal/repository"
+	"cerberus/internal/tools/email"
 	logger "cerberus/internal/tools/logger"
+	"cerberus/internal/tools/random"
 	"errors"
 	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+const (
+	maxProfileAttributes    int = 32  // maxProfileAttributes is the maximum number of attributes a profile can hold.
+	maxAttributeKeyLength   int = 64  // maxAttributeKeyLength is the maximum length of an attribute name.
+	maxAttributeValueLength int = 512 // maxAttributeValueLength is the maximum length of an attribute value.
+)
+
+var (
+	ErrEmailTaken         = errors.New("email already in use")                  // ErrEmailTaken is returned when the email belongs to another account.
+	ErrInvalidPassword    = errors.New("invalid password")                      // ErrInvalidPassword is returned when a password re-confirmation fails.
+	ErrInvalidEmailChange = errors.New("invalid or expired email change token") // ErrInvalidEmailChange is returned for unknown or foreign email change tokens.
+	ErrInvalidProfile     = errors.New("invalid profile")                       // ErrInvalidProfile wraps profile update validation failures.
+
+	ErrPasswordResetRequired = errors.New("password reset required")           // ErrPasswordResetRequired is returned when the user must change their password first.
+	ErrPasswordUnchanged     = errors.New("password must be different")        // ErrPasswordUnchanged is returned when the new password is the current one.
+	ErrNoLocalPassword       = errors.New("account has no password to change") // ErrNoLocalPassword is returned for accounts authenticated by a directory or identity provider.
+)
+
 // IsUserRegistered checks if a user with the given email is already registered in the database.
 //
 // Parameters:
@@ -36,21 +60,27 @@ func IsUserRegistered(p_dg *gorm.DB, p_email string) (bool, error) {
 
 // RegisterUser creates a new user account in the database.
 //
+// The email address is canonicalized with NormalizeEmail before the duplication check
+// and is stored in its canonical form.
+//
 // Parameters:
-//   - p_dg: A pointer to the GORM database connection.
-//   - p_email: The email address of the user to be registered.
-//   - p_pwd: The password for the new user account.
-//   - p_name: The name of the user to be registered.
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_register_dto: A pointer to the RegisterRequest containing the email, name and password.
 //
 // Returns:
 //   - A pointer to the newly created User object if registration is successful.
 //   - An error if registration fails (e.g., duplicate email, password hashing error, or database error).
 //
 // If any step fails, an appropriate error is logged and returned.
-func RegisterUser(p_dg *gorm.DB, p_register_dto *auth_dto.RegisterRequest) (*models.User, error) {
-	r, _ := IsUserRegistered(p_dg, p_register_dto.Email)
+func RegisterUser(p_db *database.DataRefs, p_register_dto *auth_dto.RegisterRequest) (*models.User, error) {
+	mail, err := NormalizeEmail(p_db, p_register_dto.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	r, _ := IsUserRegistered(p_db.Postgres, mail)
 	if r {
-		msg := fmt.Sprintf("Failed to registered, duplication - %s", p_register_dto.Email)
+		msg := fmt.Sprintf("Failed to registered, duplication - %s", mail)
 		logger.Log(msg, logger.ERROR)
 		return nil, errors.New(msg)
 	}
@@ -63,27 +93,32 @@ func RegisterUser(p_dg *gorm.DB, p_register_dto *auth_dto.RegisterRequest) (*mod
 
 	var user *models.User = &models.User{
 		Name:     p_register_dto.Name,
-		Email:    p_register_dto.Email,
+		Email:    mail,
 		Password: string(hashedPwd),
+		Status:   models.StatusActive,
 	}
 
-	err = repository.CreateUser(p_dg, user)
+	err = repository.CreateUser(p_db.Postgres, user)
 	if err != nil {
 		logger.Log("Failed to Create user - "+err.Error(), logger.ERROR)
 		return nil, err
 	}
 
+	EmitEvent(p_db, models.EventUserRegistered, map[string]string{
+		"user_id": user.ID.String(), "email": user.Email, "name": user.Name,
+	})
 	return user, nil
 }
 
 // ChangePassword updates a user's password in the database.
 //
 // Parameters:
-//   - p_db: A pointer to a gorm.DB instance representing the database connection.
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
 //   - p_change_pwd_dto: A pointer to an auth_dto.ChangePasswordRequest struct containing:
 //   - Email: The user's email address
 //   - CurrentPassword: The user's current password
 //   - NewPassword: The desired new password
+//   - p_info: The request metadata recorded in the audit log.
 //
 // Returns:
 //   - error: An error if any step fails, or nil if the password change is successful.
@@ -95,11 +130,20 @@ func RegisterUser(p_dg *gorm.DB, p_register_dto *auth_dto.RegisterRequest) (*mod
 //   - "failed to update password - [error details]"
 //
 // Note: This function uses bcrypt for password hashing and comparison.
-func ChangePassword(p_db *gorm.DB, p_change_pwd_dto *auth_dto.ChangePasswordRequest) error {
-	usr, err := repository.FindUserByEmail(p_db, p_change_pwd_dto.Email)
+func ChangePassword(p_db *database.DataRefs, p_change_pwd_dto *auth_dto.ChangePasswordRequest, p_info RequestInfo) (err error) {
+	var usrId string
+	defer func() { auditResult(p_db.Postgres, p_info, models.AuditPasswordChange, usrId, err, nil) }()
+
+	mail, err := NormalizeEmail(p_db, p_change_pwd_dto.Email)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	usr, err := repository.FindUserByEmail(p_db.Postgres, mail)
 	if err != nil {
 		return errors.New("user not found")
 	}
+	usrId = usr.ID.String()
 
 	if err = bcrypt.CompareHashAndPassword([]byte(usr.Password), []byte(p_change_pwd_dto.CurrentPassword)); err != nil {
 		return errors.New("invalid password")
@@ -115,37 +159,127 @@ func ChangePassword(p_db *gorm.DB, p_change_pwd_dto *auth_dto.ChangePasswordRequ
 		return errors.New("failed to hash the new password - " + err.Error())
 	}
 
-	err = repository.UpdatePassword(p_db, usr, string(hashedPwd))
+	err = repository.UpdatePassword(p_db.Postgres, usr, string(hashedPwd))
 	if err != nil {
 		logger.Log("Failed to update password - "+err.Error(), logger.ERROR)
 		return errors.New("failed to update password - " + err.Error())
 	}
 
+	EmitEvent(p_db, models.EventUserPasswordChanged, map[string]string{"user_id": usrId})
+	return nil
+}
+
+// SetPassword replaces the password of the user of a session. Unlike ChangePassword, the
+// current password is not asked for: the route is guarded by RequireRecentAuth, so
+// the user proved their identity moments ago.
+//
+// Accounts without a local password, authenticated by a directory or identity provider, are
+// refused.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_usrId: The unique ID (string) of the authenticated user.
+//   - p_dto: A pointer to the SetPasswordRequest holding the new password.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - error: ErrNoLocalPassword, ErrPasswordUnchanged, or the hashing or database error.
+func SetPassword(p_db *database.DataRefs, p_usrId string, p_dto *profile_dto.SetPasswordRequest, p_info RequestInfo) (err error) {
+	defer func() { auditResult(p_db.Postgres, p_info, models.AuditPasswordChange, p_usrId, err, nil) }()
+
+	usr, err := GetUserById(p_db.Postgres, p_usrId)
+	if err != nil {
+		return err
+	}
+
+	if usr.Password == "" {
+		return ErrNoLocalPassword
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(usr.Password), []byte(p_dto.NewPassword)) == nil {
+		return ErrPasswordUnchanged
+	}
+
+	hashedPwd, err := bcrypt.GenerateFromPassword([]byte(p_dto.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		logger.Log("Failed to hash password - "+err.Error(), logger.ERROR)
+		return err
+	}
+
+	if err = repository.UpdatePassword(p_db.Postgres, usr, string(hashedPwd)); err != nil {
+		logger.Log("Failed to update password - "+err.Error(), logger.ERROR)
+		return err
+	}
+
+	EmitEvent(p_db, models.EventUserPasswordChanged, map[string]string{"user_id": p_usrId})
 	return nil
 }
 
-// AuthenticateUser verifies a user's login credentials against the database.
+// AuthenticateUser verifies a user's login credentials.
+//
+// The backends listed in AUTH_BACKENDS (see Authenticator) are tried in order; the first one
+// knowing the user decides. Valid credentials are still rejected with an error wrapping
+// ErrAccountNotActive if the account is not active, or ErrPasswordResetRequired if an operator
+// forced a password reset of a local account.
+//
+// Failures are recorded in the audit log; successful logins are recorded by LoginUser once
+// the session is issued.
 //
 // Parameters:
-//   - p_db: A pointer to the gorm.DB instance for database operations.
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
 //   - p_login_dto: A pointer to the LoginRequest containing the user's email and password.
+//   - p_info: The request metadata recorded in the audit log.
 //
 // Returns:
 //   - *postgres_models.User: A pointer to the User model if authentication is successful.
-//   - error: An error if authentication fails, nil otherwise.
-func AuthenticateUser(p_db *gorm.DB, p_login_dto *session_dto.LoginRequest) (*models.User, error) {
-	usr, err := repository.FindUserByEmail(p_db, p_login_dto.Email)
+//   - error: ErrInvalidCredentials if no backend accepts the credentials, or the error of the
+//     backend, nil otherwise.
+func AuthenticateUser(p_db *database.DataRefs, p_login_dto *session_dto.LoginRequest, p_info RequestInfo) (*models.User, error) {
+	var usrId, backend string
+	fail := func(p_err error) (*models.User, error) {
+		details := map[string]string{"email": p_login_dto.Email}
+		if backend != "" {
+			details["backend"] = backend
+		}
+		auditResult(p_db.Postgres, p_info, models.AuditLoginFailure, usrId, p_err, details)
+		return nil, p_err
+	}
+
+	mail, err := NormalizeEmail(p_db, p_login_dto.Email)
 	if err != nil {
-		logger.Log("User not found - "+err.Error(), logger.ERROR)
-		return nil, err
+		return fail(err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(usr.Password), []byte(p_login_dto.Password)); err != nil {
-		logger.Log("Invalid credentials - "+err.Error(), logger.ERROR)
-		return nil, err
+	for _, backend = range p_db.ConfigData.GetAuthBackends() {
+		auth, ok := authenticators[backend]
+		if !ok {
+			logger.Log("Unknown authentication backend - "+backend, logger.WARN)
+			continue
+		}
+
+		usr, err := auth.Authenticate(p_db, mail, p_login_dto.Password, p_info)
+		if usr != nil {
+			usrId = usr.ID.String()
+		}
+		if errors.Is(err, ErrNotHandled) {
+			continue
+		} else if err != nil {
+			return fail(err)
+		}
+
+		if err := CheckUserActive(usr); err != nil {
+			return fail(err)
+		}
+
+		if backend == AuthBackendLocal && usr.PasswordResetRequired {
+			return fail(ErrPasswordResetRequired)
+		}
+
+		return usr, nil
 	}
 
-	return usr, nil
+	backend = ""
+	return fail(ErrInvalidCredentials)
 }
 
 // GetUserById retrieves a user from the database by their unique ID.
@@ -169,3 +303,204 @@ func GetUserById(p_db *gorm.DB, p_usrId string) (*models.User, error) {
 
 	return usr, nil
 }
+
+// NormalizeEmail returns the canonical form of an email address according to the
+// application configuration (see email.Normalize).
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing the configuration references.
+//   - p_email: The raw email address as provided by the client.
+//
+// Returns:
+//   - string: The canonical email address.
+//   - error: An error if the address is malformed.
+func NormalizeEmail(p_db *database.DataRefs, p_email string) (string, error) {
+	mail, err := email.Normalize(p_email, p_db.ConfigData.LowercaseEmailLocal)
+	if err != nil {
+		logger.Log(fmt.Sprintf("Failed to normalize email - %s", err.Error()), logger.ERROR)
+		return "", err
+	}
+
+	return mail, nil
+}
+
+// UpdateProfile applies a partial profile update to the user identified by p_usrId.
+//
+// The name is replaced when provided. Attributes are merged into the existing ones, and
+// attributes set to null are removed. The number and size of attributes are limited.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection.
+//   - p_usrId: The unique ID (string) of the user to update.
+//   - p_dto: A pointer to the UpdateProfileRequest holding the changes.
+//
+// Returns:
+//   - *models.User: The updated user.
+//   - error: An error wrapping ErrInvalidProfile if the update is invalid, or the database error.
+func UpdateProfile(p_db *gorm.DB, p_usrId string, p_dto *profile_dto.UpdateProfileRequest) (*models.User, error) {
+	usr, err := GetUserById(p_db, p_usrId)
+	if err != nil {
+		return nil, err
+	}
+
+	if p_dto.Name != nil {
+		name := strings.TrimSpace(*p_dto.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w - name must not be empty", ErrInvalidProfile)
+		}
+		usr.Name = name
+	}
+
+	if usr.Attributes == nil {
+		usr.Attributes = make(map[string]string)
+	}
+	for k, v := range p_dto.Attributes {
+		if k == "" || utf8.RuneCountInString(k) > maxAttributeKeyLength {
+			return nil, fmt.Errorf("%w - attribute names must have 1 to %d characters", ErrInvalidProfile, maxAttributeKeyLength)
+		}
+
+		if v == nil {
+			delete(usr.Attributes, k)
+			continue
+		}
+
+		if utf8.RuneCountInString(*v) > maxAttributeValueLength {
+			return nil, fmt.Errorf("%w - attribute %s exceeds %d characters", ErrInvalidProfile, k, maxAttributeValueLength)
+		}
+		usr.Attributes[k] = *v
+	}
+
+	if len(usr.Attributes) > maxProfileAttributes {
+		return nil, fmt.Errorf("%w - at most %d attributes are allowed", ErrInvalidProfile, maxProfileAttributes)
+	}
+
+	if err := repository.UpdateProfile(p_db, usr); err != nil {
+		logger.Log("Failed to update profile - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+
+	return usr, nil
+}
+
+// RequestEmailChange starts the email change flow of a user.
+//
+// The current password is verified, the new address is canonicalized and checked for
+// availability, then a verification token is stored in Redis and emailed to the new address.
+// The email is only switched once ConfirmEmailChange is called with that token.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database, mailer and configuration references.
+//   - p_usrId: The unique ID (string) of the user requesting the change.
+//   - p_dto: A pointer to the ChangeEmailRequest holding the new address and the current password.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - error: ErrInvalidPassword, ErrEmailTaken, or an error if any other step fails; nil otherwise.
+func RequestEmailChange(p_db *database.DataRefs, p_usrId string, p_dto *profile_dto.ChangeEmailRequest,
+	p_info RequestInfo) (err error) {
+	defer func() {
+		auditResult(p_db.Postgres, p_info, models.AuditEmailChangeRequest, p_usrId, err,
+			map[string]string{"new_email": p_dto.NewEmail})
+	}()
+
+	usr, err := GetUserById(p_db.Postgres, p_usrId)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(usr.Password), []byte(p_dto.Password)); err != nil {
+		return ErrInvalidPassword
+	}
+
+	mail, err := NormalizeEmail(p_db, p_dto.NewEmail)
+	if err != nil {
+		return err
+	}
+
+	if registered, err := IsUserRegistered(p_db.Postgres, mail); err != nil {
+		return err
+	} else if registered {
+		return ErrEmailTaken
+	}
+
+	tkn, err := random.Token(32)
+	if err != nil {
+		logger.Log("Failed to generate email change token - "+err.Error(), logger.ERROR)
+		return err
+	}
+
+	err = repository.StorePendingEmailChange(p_db.Redis, tkn, p_usrId, mail,
+		p_db.ConfigData.RedisData.GetEmailChangeDuration())
+	if err != nil {
+		logger.Log("Failed to store pending email change - "+err.Error(), logger.ERROR)
+		return err
+	}
+
+	link := fmt.Sprintf("%s/confirm-email?token=%s", p_db.ConfigData.MailData.GetLinkBaseURL(), url.QueryEscape(tkn))
+	body := fmt.Sprintf("Hello %s,\n\nConfirm your new email address by opening the link below:\n%s\n\n"+
+		"Verification code: %s\n\nThe link expires in %s.", usr.Name, link, tkn,
+		p_db.ConfigData.RedisData.GetEmailChangeDuration())
+
+	if err := p_db.Mailer.Send(mail, "Confirm your new email address", body); err != nil {
+		repository.DeletePendingEmailChange(p_db.Redis, tkn)
+		logger.Log("Failed to send email change verification - "+err.Error(), logger.ERROR)
+		return err
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange completes a pending email change.
+//
+// The token must belong to the user identified by p_usrId. The address availability is
+// checked again before switching, and the previous address is notified of the change.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database, mailer and configuration references.
+//   - p_usrId: The unique ID (string) of the authenticated user.
+//   - p_token: The verification token sent to the new address.
+//   - p_info: The request metadata recorded in the audit log.
+//
+// Returns:
+//   - *models.User: The updated user.
+//   - error: ErrInvalidEmailChange, ErrEmailTaken, or an error if the update fails; nil otherwise.
+func ConfirmEmailChange(p_db *database.DataRefs, p_usrId string, p_token string, p_info RequestInfo) (_ *models.User, err error) {
+	defer func() { auditResult(p_db.Postgres, p_info, models.AuditEmailChange, p_usrId, err, nil) }()
+
+	usrId, mail, err := repository.GetPendingEmailChange(p_db.Redis, p_token)
+	if err != nil || usrId != p_usrId {
+		return nil, ErrInvalidEmailChange
+	}
+
+	usr, err := GetUserById(p_db.Postgres, p_usrId)
+	if err != nil {
+		return nil, err
+	}
+
+	if registered, err := IsUserRegistered(p_db.Postgres, mail); err != nil {
+		return nil, err
+	} else if registered {
+		repository.DeletePendingEmailChange(p_db.Redis, p_token)
+		return nil, ErrEmailTaken
+	}
+
+	previous := usr.Email
+	if err := repository.UpdateEmail(p_db.Postgres, usr, mail); err != nil {
+		logger.Log("Failed to update email - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+	usr.Email = mail
+	repository.DeletePendingEmailChange(p_db.Redis, p_token)
+
+	EmitEvent(p_db, models.EventUserEmailVerified, map[string]string{
+		"user_id": p_usrId, "email": mail,
+	})
+
+	body := fmt.Sprintf("Hello %s,\n\nThe email address of your account was changed to %s.\n"+
+		"If you did not request this change, contact support immediately.", usr.Name, mail)
+	if err := p_db.Mailer.Send(previous, "Your email address was changed", body); err != nil {
+		logger.Log("Failed to notify previous email address - "+err.Error(), logger.WARN)
+	}
+
+	return usr, nil
+}
@@ -0,0 +1,390 @@
+package services
+
+import (
+	"cerberus/internal/database"
+	"cerberus/internal/dto/webhook_dto"
+	"cerberus/internal/models"
+	"cerberus/internal/repository"
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/random"
+	"cerberus/internal/tools/validator"
+	"cerberus/internal/tools/webhook"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	webhookBatchSize   int           = 50               // Deliveries claimed by a single worker run.
+	webhookBaseBackoff time.Duration = 30 * time.Second // Delay before the first retry, doubled on each attempt.
+	webhookMaxBackoff  time.Duration = 6 * time.Hour    // Upper bound of the delay between two attempts.
+)
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// region Public
+
+// CreateWebhookSubscription subscribes an endpoint to identity events.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_dto: A pointer to the validated CreateWebhookRequest.
+//
+// Returns:
+//   - *models.WebhookSubscription: The created subscription, holding its signing secret.
+//   - error: A validator.ValidationErrors for unknown event types, or the database error.
+func CreateWebhookSubscription(p_db *gorm.DB, p_dto *webhook_dto.CreateWebhookRequest) (*models.WebhookSubscription, error) {
+	types, err := normalizeEventTypes(p_dto.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := p_dto.Secret
+	if secret == "" {
+		if secret, err = random.Token(32); err != nil {
+			return nil, err
+		}
+	}
+
+	sub := &models.WebhookSubscription{
+		URL:        strings.TrimSpace(p_dto.URL),
+		Secret:     secret,
+		EventTypes: types,
+		Active:     true,
+	}
+	if err := repository.CreateWebhookSubscription(p_db, sub); err != nil {
+		logger.Log("Failed to create webhook - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// GetWebhookSubscription retrieves a webhook subscription by its ID.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_id: The unique ID (string) of the subscription.
+//
+// Returns:
+//   - *models.WebhookSubscription: The subscription.
+//   - error: ErrWebhookNotFound if the ID is malformed or unknown, or the database error.
+func GetWebhookSubscription(p_db *gorm.DB, p_id string) (*models.WebhookSubscription, error) {
+	if _, err := uuid.Parse(p_id); err != nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	sub, err := repository.FindWebhookSubscription(p_db, p_id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWebhookNotFound
+	}
+	return sub, err
+}
+
+// ListWebhookSubscriptions retrieves every webhook subscription.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//
+// Returns:
+//   - []models.WebhookSubscription: The subscriptions, oldest first.
+//   - error: An error if the query fails; nil otherwise.
+func ListWebhookSubscriptions(p_db *gorm.DB) ([]models.WebhookSubscription, error) {
+	return repository.ListWebhookSubscriptions(p_db)
+}
+
+// UpdateWebhookSubscription applies a partial update to a webhook subscription. Deliveries
+// already queued keep being sent to the endpoint they were queued for, but are signed with the
+// current secret. Those of a deactivated subscription wait until it is reactivated.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_id: The unique ID (string) of the subscription.
+//   - p_dto: A pointer to the validated UpdateWebhookRequest.
+//
+// Returns:
+//   - *models.WebhookSubscription: The updated subscription.
+//   - error: ErrWebhookNotFound, a validator.ValidationErrors for unknown event types, or the
+//     database error.
+func UpdateWebhookSubscription(p_db *gorm.DB, p_id string, p_dto *webhook_dto.UpdateWebhookRequest) (*models.WebhookSubscription, error) {
+	sub, err := GetWebhookSubscription(p_db, p_id)
+	if err != nil {
+		return nil, err
+	}
+
+	if p_dto.URL != nil {
+		sub.URL = strings.TrimSpace(*p_dto.URL)
+	}
+	if p_dto.EventTypes != nil {
+		if sub.EventTypes, err = normalizeEventTypes(p_dto.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+	if p_dto.Active != nil {
+		sub.Active = *p_dto.Active
+	}
+	if p_dto.RotateSecret {
+		if sub.Secret, err = random.Token(32); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := repository.SaveWebhookSubscription(p_db, sub); err != nil {
+		logger.Log("Failed to update webhook - "+err.Error(), logger.ERROR)
+		return nil, err
+	}
+	return sub, nil
+}
+
+// DeleteWebhookSubscription removes a webhook subscription and its delivery log.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_id: The unique ID (string) of the subscription.
+//
+// Returns:
+//   - error: ErrWebhookNotFound if the subscription does not exist, or the database error.
+func DeleteWebhookSubscription(p_db *gorm.DB, p_id string) error {
+	if _, err := uuid.Parse(p_id); err != nil {
+		return ErrWebhookNotFound
+	}
+
+	err := repository.DeleteWebhookSubscription(p_db, p_id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+// ListWebhookDeliveries retrieves a page of the delivery log of a subscription.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_id: The unique ID (string) of the subscription.
+//   - p_status: Only return deliveries with this status, or "" for every status.
+//   - p_page: The 1-based page number.
+//   - p_pageSize: The number of deliveries per page.
+//
+// Returns:
+//   - []models.WebhookDelivery: The deliveries of the page, newest first.
+//   - int64: The total number of matching deliveries.
+//   - error: ErrWebhookNotFound if the subscription does not exist, or the database error.
+func ListWebhookDeliveries(p_db *gorm.DB, p_id string, p_status string, p_page int,
+	p_pageSize int) ([]models.WebhookDelivery, int64, error) {
+	if _, err := GetWebhookSubscription(p_db, p_id); err != nil {
+		return nil, 0, err
+	}
+	return repository.ListWebhookDeliveries(p_db, p_id, p_status, (p_page-1)*p_pageSize, p_pageSize)
+}
+
+// RetryWebhookDelivery schedules a delivery for an immediate new attempt, resetting its
+// attempt counter. It is typically used on deliveries that exhausted their attempts.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_id: The unique ID (string) of the subscription.
+//   - p_deliveryId: The unique ID (string) of the delivery.
+//
+// Returns:
+//   - error: ErrDeliveryNotFound if the delivery does not belong to the subscription, or the
+//     database error.
+func RetryWebhookDelivery(p_db *gorm.DB, p_id string, p_deliveryId string) error {
+	if _, err := uuid.Parse(p_id); err != nil {
+		return ErrDeliveryNotFound
+	}
+	if _, err := uuid.Parse(p_deliveryId); err != nil {
+		return ErrDeliveryNotFound
+	}
+
+	err := repository.RetryWebhookDelivery(p_db, p_id, p_deliveryId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrDeliveryNotFound
+	}
+	return err
+}
+
+// PublishWebhookEvent queues an event for every active subscription listening to its type.
+// The deliveries are sent by the webhook worker. Failures are logged and never returned, so
+// that a broken webhook setup cannot fail the operation that triggered the event.
+//
+// Events are normally published through EmitEvent, which builds the payload.
+//
+// Parameters:
+//   - p_db: A pointer to the GORM database connection (*gorm.DB) used to execute the query.
+//   - p_type: The event type (one of the models.WebhookEventTypes values).
+//   - p_payload: The JSON encoded event_dto.Event sent to the receivers.
+func PublishWebhookEvent(p_db *gorm.DB, p_type string, p_payload []byte) {
+	subs, err := repository.FindSubscriptionsForEvent(p_db, p_type)
+	if err != nil {
+		logger.Log(fmt.Sprintf("Failed to find webhooks for %s - %s", p_type, err.Error()), logger.ERROR)
+		return
+	}
+	if len(subs) == 0 {
+		return
+	}
+
+	now := time.Now()
+	deliveries := make([]models.WebhookDelivery, 0, len(subs))
+	for _, sub := range subs {
+		deliveries = append(deliveries, newWebhookDelivery(&sub, p_type, p_payload, now))
+	}
+
+	if err := repository.CreateWebhookDeliveries(p_db, deliveries); err != nil {
+		logger.Log(fmt.Sprintf("Failed to queue webhook event %s - %s", p_type, err.Error()), logger.ERROR)
+	}
+}
+
+// ProcessWebhookDeliveries sends every due delivery once. Failed attempts are rescheduled with
+// an exponential backoff until the configured maximum number of attempts is reached.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_client: The HTTP client used to reach the receivers.
+//
+// Returns:
+//   - int: The number of deliveries attempted.
+//   - error: An error if the due deliveries could not be claimed; nil otherwise.
+func ProcessWebhookDeliveries(p_db *database.DataRefs, p_client *http.Client) (int, error) {
+	timeout := p_db.ConfigData.GetWebhookTimeout()
+	maxAttempts := p_db.ConfigData.GetWebhookMaxAttempts()
+
+	deliveries, err := repository.ClaimDueWebhookDeliveries(p_db.Postgres, webhookBatchSize,
+		timeout*time.Duration(webhookBatchSize+1))
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		attemptWebhookDelivery(p_client, delivery, timeout, maxAttempts)
+
+		if err := repository.SaveWebhookDelivery(p_db.Postgres, delivery); err != nil {
+			logger.Log(fmt.Sprintf("Failed to save webhook delivery %s - %s", delivery.ID, err.Error()), logger.ERROR)
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// StartWebhookWorker runs ProcessWebhookDeliveries in the background every p_interval.
+// A full batch is followed immediately by the next one, so a backlog drains without waiting.
+//
+// Parameters:
+//   - p_db: A pointer to the DataRefs structure containing database and configuration references.
+//   - p_interval: The time between two polls when no delivery is due.
+func StartWebhookWorker(p_db *database.DataRefs, p_interval time.Duration) {
+	client := &http.Client{Timeout: p_db.ConfigData.GetWebhookTimeout()}
+
+	go func() {
+		ticker := time.NewTicker(p_interval)
+		defer ticker.Stop()
+
+		for {
+			n, err := ProcessWebhookDeliveries(p_db, client)
+			if err != nil {
+				logger.Log("Failed to process webhook deliveries - "+err.Error(), logger.ERROR)
+			}
+			if n == webhookBatchSize {
+				continue
+			}
+
+			<-ticker.C
+		}
+	}()
+}
+
+// endregion Public
+// region Private
+
+// normalizeEventTypes checks and deduplicates the event types of a subscription.
+func normalizeEventTypes(p_types []string) ([]string, error) {
+	types := make([]string, 0, len(p_types))
+	for _, t := range p_types {
+		t = strings.TrimSpace(t)
+		if !slices.Contains(models.WebhookEventTypes, t) {
+			return nil, validator.ValidationErrors{{Field: "event_types", Rule: "oneof",
+				Message: fmt.Sprintf("event_types must only contain [%s]", strings.Join(models.WebhookEventTypes, " "))}}
+		}
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+
+	if len(types) == 0 {
+		return nil, validator.ValidationErrors{{Field: "event_types", Rule: "required",
+			Message: "event_types is required"}}
+	}
+	return types, nil
+}
+
+// newWebhookDelivery queues an event for a subscription. The delivery records the current URL of
+// the subscription, so that later changes of the subscription do not redirect it.
+func newWebhookDelivery(p_sub *models.WebhookSubscription, p_type string, p_payload []byte,
+	p_now time.Time) models.WebhookDelivery {
+	return models.WebhookDelivery{
+		SubscriptionID: p_sub.ID,
+		URL:            p_sub.URL,
+		EventType:      p_type,
+		Payload:        string(p_payload),
+		Status:         models.DeliveryPending,
+		NextAttemptAt:  p_now,
+	}
+}
+
+// attemptWebhookDelivery sends a delivery once and records the outcome on it: succeeded,
+// failed once p_maxAttempts is reached, or rescheduled with webhookBackoff otherwise. The
+// caller saves it.
+func attemptWebhookDelivery(p_client *http.Client, p_delivery *models.WebhookDelivery,
+	p_timeout time.Duration, p_maxAttempts int) {
+	ctx, cancel := context.WithTimeout(context.Background(), p_timeout)
+	status, err := webhook.Send(ctx, p_client, &webhook.Request{
+		URL:        p_delivery.Endpoint(),
+		Secret:     p_delivery.Subscription.Secret,
+		Event:      p_delivery.EventType,
+		DeliveryID: p_delivery.ID.String(),
+		Body:       []byte(p_delivery.Payload),
+	})
+	cancel()
+
+	now := time.Now()
+	p_delivery.Attempts++
+	p_delivery.ResponseStatus = status
+
+	switch {
+	case err == nil:
+		p_delivery.Status = models.DeliverySucceeded
+		p_delivery.LastError = ""
+		p_delivery.DeliveredAt = &now
+
+	case p_delivery.Attempts >= p_maxAttempts:
+		p_delivery.Status = models.DeliveryFailed
+		p_delivery.LastError = err.Error()
+		logger.Log(fmt.Sprintf("Webhook delivery %s failed after %d attempts - %s",
+			p_delivery.ID, p_delivery.Attempts, err.Error()), logger.WARN)
+
+	default:
+		p_delivery.LastError = err.Error()
+		p_delivery.NextAttemptAt = now.Add(webhookBackoff(p_delivery.Attempts))
+	}
+}
+
+// webhookBackoff returns the delay before the next attempt of a delivery attempted p_attempts times.
+func webhookBackoff(p_attempts int) time.Duration {
+	d := webhookBaseBackoff
+	for i := 1; i < p_attempts && d < webhookMaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, webhookMaxBackoff)
+}
+
+// endregion Private
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"cerberus/internal/models"
+	"cerberus/internal/tools/webhook"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestAttemptWebhookDeliveryRetriesServerErrors(t *testing.T) {
+	const secret = "0123456789abcdef"
+	payload := `{"type":"user.registered","data":{"user_id":"42"}}`
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := webhook.Verify(secret, r.Header, body, time.Minute); err != nil {
+			t.Errorf("delivery %d: %v", calls.Load()+1, err)
+		}
+		if got := r.Header.Get(webhook.HeaderEvent); got != models.EventUserRegistered {
+			t.Errorf("%s = %q, want %q", webhook.HeaderEvent, got, models.EventUserRegistered)
+		}
+		if string(body) != payload {
+			t.Errorf("body = %s, want %s", body, payload)
+		}
+
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	delivery := &models.WebhookDelivery{
+		ID:           uuid.New(),
+		Subscription: models.WebhookSubscription{URL: srv.URL, Secret: secret},
+		EventType:    models.EventUserRegistered,
+		Payload:      payload,
+		Status:       models.DeliveryPending,
+	}
+
+	before := time.Now()
+	attemptWebhookDelivery(srv.Client(), delivery, time.Second, 3)
+	if delivery.Status != models.DeliveryPending || delivery.Attempts != 1 {
+		t.Fatalf("after a 502: status %q, %d attempts, want pending after 1", delivery.Status, delivery.Attempts)
+	}
+	if delivery.ResponseStatus != http.StatusBadGateway || delivery.LastError == "" {
+		t.Errorf("after a 502: response status %d, last error %q", delivery.ResponseStatus, delivery.LastError)
+	}
+	if delivery.NextAttemptAt.Before(before.Add(webhookBaseBackoff)) {
+		t.Errorf("retry scheduled at %s, want at least %s later", delivery.NextAttemptAt, webhookBaseBackoff)
+	}
+
+	attemptWebhookDelivery(srv.Client(), delivery, time.Second, 3)
+	if delivery.Status != models.DeliverySucceeded || delivery.Attempts != 2 {
+		t.Fatalf("after a 204: status %q, %d attempts, want succeeded after 2", delivery.Status, delivery.Attempts)
+	}
+	if delivery.LastError != "" || delivery.DeliveredAt == nil {
+		t.Errorf("after a 204: last error %q, delivered at %v", delivery.LastError, delivery.DeliveredAt)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("receiver called %d times, want 2", calls.Load())
+	}
+}
+
+func TestAttemptWebhookDeliveryGivesUpAfterMaxAttempts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	delivery := &models.WebhookDelivery{
+		ID:           uuid.New(),
+		Subscription: models.WebhookSubscription{URL: srv.URL, Secret: "0123456789abcdef"},
+		EventType:    models.EventUserRegistered,
+		Payload:      `{}`,
+		Status:       models.DeliveryPending,
+		Attempts:     2,
+	}
+
+	attemptWebhookDelivery(srv.Client(), delivery, time.Second, 3)
+	if delivery.Status != models.DeliveryFailed || delivery.Attempts != 3 {
+		t.Fatalf("status %q, %d attempts, want failed after 3", delivery.Status, delivery.Attempts)
+	}
+	if delivery.ResponseStatus != http.StatusInternalServerError {
+		t.Errorf("response status %d, want %d", delivery.ResponseStatus, http.StatusInternalServerError)
+	}
+}
+
+func TestWebhookDeliveryKeepsItsEndpoint(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	sub := models.WebhookSubscription{ID: uuid.New(), URL: srv.URL, Secret: "0123456789abcdef", Active: true}
+	delivery := newWebhookDelivery(&sub, models.EventUserRegistered, []byte(`{}`), time.Now())
+	delivery.ID = uuid.New()
+
+	// The subscription moves to another endpoint before the worker claims the delivery.
+	sub.URL = "http://127.0.0.1:1/moved"
+	delivery.Subscription = sub
+
+	attemptWebhookDelivery(srv.Client(), &delivery, time.Second, 3)
+	if delivery.Status != models.DeliverySucceeded || calls.Load() != 1 {
+		t.Errorf("status %q, receiver called %d times, want succeeded on the queued endpoint",
+			delivery.Status, calls.Load())
+	}
+}
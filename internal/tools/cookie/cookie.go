@@ -0,0 +1,182 @@
+package cookie
+
+import (
+	"cerberus/internal/tools/csrf"
+	"cerberus/pkg/config"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RefreshPath scopes the refresh cookie to the refresh route, so the refresh token is not sent
+// along with every other request.
+const RefreshPath string = "/session/refresh"
+
+// region Public
+
+// SetSession stores the tokens of a browser session in HttpOnly cookies. The CSRF token of the
+// session is returned and also set in a cookie readable by scripts.
+//
+// The access cookie lives as long as the access token, plus the JWT leeway. The refresh cookie
+// lives as long as the refresh token and is only sent to RefreshPath. It holds the user ID along
+// with the refresh token, so the session can be refreshed once the access cookie expired. The
+// CSRF cookie lives as long as the refresh cookie for the same reason.
+//
+// Parameters:
+//   - w: The response the cookies are set on.
+//   - p_cfg: A pointer to the ConfigData structure holding the cookie settings.
+//   - p_usrId: The ID of the user of the session.
+//   - p_access: The access token of the session.
+//   - p_refresh: The refresh token of the session.
+//
+// Returns:
+//   - string: The CSRF token of the session.
+func SetSession(w http.ResponseWriter, p_cfg *config.ConfigData, p_usrId string, p_access string, p_refresh string) string {
+	csrfTkn := csrf.Token(p_cfg.GetCSRFSecret(), p_access, p_refresh)
+	setAccess(w, p_cfg, p_access, csrfTkn)
+	http.SetCookie(w, newCookie(p_cfg, p_cfg.GetRefreshCookieName(), p_usrId+"."+p_refresh, RefreshPath,
+		p_cfg.RedisData.GetRefreshJWTDuration(), true))
+
+	return csrfTkn
+}
+
+// SetAccess replaces the access token of a browser session, keeping its refresh cookie. The
+// CSRF token bound to the new access token is returned and set like in SetSession.
+//
+// Parameters:
+//   - w: The response the cookies are set on.
+//   - p_cfg: A pointer to the ConfigData structure holding the cookie settings.
+//   - p_access: The new access token of the session.
+//   - p_csrf: The current CSRF token of the session, whose refresh part is kept.
+//
+// Returns:
+//   - string: The CSRF token of the session.
+func SetAccess(w http.ResponseWriter, p_cfg *config.ConfigData, p_access string, p_csrf string) string {
+	csrfTkn := csrf.WithAccess(p_cfg.GetCSRFSecret(), p_access, p_csrf)
+	setAccess(w, p_cfg, p_access, csrfTkn)
+
+	return csrfTkn
+}
+
+// ParseRefresh splits the value of a refresh cookie set by SetSession.
+//
+// Parameters:
+//   - p_value: The value of the refresh cookie.
+//
+// Returns:
+//   - string: The ID of the user of the session.
+//   - string: The refresh token of the session.
+//   - bool: false if the value is malformed.
+func ParseRefresh(p_value string) (string, string, bool) {
+	usrId, refresh, ok := strings.Cut(p_value, ".")
+	if !ok || usrId == "" || refresh == "" {
+		return "", "", false
+	}
+	return usrId, refresh, true
+}
+
+// ClearSession removes the cookies set by SetSession.
+//
+// Parameters:
+//   - w: The response the cookies are removed on.
+//   - p_cfg: A pointer to the ConfigData structure holding the cookie settings.
+func ClearSession(w http.ResponseWriter, p_cfg *config.ConfigData) {
+	http.SetCookie(w, newCookie(p_cfg, p_cfg.GetSessionCookieName(), "", "/", -1, true))
+	http.SetCookie(w, newCookie(p_cfg, p_cfg.GetRefreshCookieName(), "", RefreshPath, -1, true))
+	http.SetCookie(w, newCookie(p_cfg, p_cfg.GetCSRFCookieName(), "", "/", -1, false))
+}
+
+// SetFlow stores the value binding a multi-step login (e.g., a federated login) to the browser
+// that started it, in an HttpOnly cookie.
+//
+// Flow cookies use SameSite=Lax whatever the configuration, because the flow comes back with a
+// top-level navigation from another site, which strict cookies are not sent with.
+//
+// Parameters:
+//   - w: The response the cookie is set on.
+//   - p_cfg: A pointer to the ConfigData structure holding the cookie settings.
+//   - p_name: The name of the cookie.
+//   - p_value: The value binding the flow.
+//   - p_path: The path of the routes completing the flow.
+//   - p_age: How long the flow may take.
+func SetFlow(w http.ResponseWriter, p_cfg *config.ConfigData, p_name string, p_value string, p_path string, p_age time.Duration) {
+	c := newCookie(p_cfg, p_name, p_value, p_path, p_age, true)
+	c.SameSite = http.SameSiteLaxMode
+	http.SetCookie(w, c)
+}
+
+// ClearFlow removes a cookie set by SetFlow.
+//
+// Parameters:
+//   - w: The response the cookie is removed on.
+//   - p_cfg: A pointer to the ConfigData structure holding the cookie settings.
+//   - p_name: The name of the cookie.
+//   - p_path: The path the cookie was set with.
+func ClearFlow(w http.ResponseWriter, p_cfg *config.ConfigData, p_name string, p_path string) {
+	SetFlow(w, p_cfg, p_name, "", p_path, -1)
+}
+
+// SetCrossSiteFlow is SetFlow for flows completed by a cross-site POST request, such as a SAML
+// response posted by an identity provider, which SameSite=Lax cookies are not sent with.
+//
+// The cookie uses SameSite=None, which browsers only accept on Secure cookies, so it is always
+// Secure. Browsers treat "localhost" as secure, so it still works on development setups.
+//
+// Parameters:
+//   - w: The response the cookie is set on.
+//   - p_cfg: A pointer to the ConfigData structure holding the cookie settings.
+//   - p_name: The name of the cookie.
+//   - p_value: The value binding the flow.
+//   - p_path: The path of the routes completing the flow.
+//   - p_age: How long the flow may take.
+func SetCrossSiteFlow(w http.ResponseWriter, p_cfg *config.ConfigData, p_name string, p_value string, p_path string,
+	p_age time.Duration) {
+	c := newCookie(p_cfg, p_name, p_value, p_path, p_age, true)
+	c.SameSite = http.SameSiteNoneMode
+	c.Secure = true
+	http.SetCookie(w, c)
+}
+
+// ClearCrossSiteFlow removes a cookie set by SetCrossSiteFlow.
+//
+// Parameters:
+//   - w: The response the cookie is removed on.
+//   - p_cfg: A pointer to the ConfigData structure holding the cookie settings.
+//   - p_name: The name of the cookie.
+//   - p_path: The path the cookie was set with.
+func ClearCrossSiteFlow(w http.ResponseWriter, p_cfg *config.ConfigData, p_name string, p_path string) {
+	SetCrossSiteFlow(w, p_cfg, p_name, "", p_path, -1)
+}
+
+// endregion Public
+
+// region Private
+
+// setAccess sets the access and CSRF cookies of a browser session.
+func setAccess(w http.ResponseWriter, p_cfg *config.ConfigData, p_access string, p_csrf string) {
+	accessAge := p_cfg.RedisData.GetJWTDuration() + p_cfg.GetJWTLeeway()
+	http.SetCookie(w, newCookie(p_cfg, p_cfg.GetSessionCookieName(), p_access, "/", accessAge, true))
+	http.SetCookie(w, newCookie(p_cfg, p_cfg.GetCSRFCookieName(), p_csrf, "/", p_cfg.RedisData.GetRefreshJWTDuration(), false))
+}
+
+// newCookie builds a cookie with the configured domain, SameSite and Secure attributes.
+// A negative age deletes the cookie.
+func newCookie(p_cfg *config.ConfigData, p_name string, p_value string, p_path string, p_age time.Duration, p_httpOnly bool) *http.Cookie {
+	maxAge := int(p_age.Seconds())
+	if p_age < 0 {
+		maxAge = -1
+	}
+
+	return &http.Cookie{
+		Name:     p_name,
+		Value:    p_value,
+		Path:     p_path,
+		Domain:   p_cfg.SessionCookieDomain,
+		MaxAge:   maxAge,
+		Secure:   !p_cfg.SessionCookieInsecure,
+		HttpOnly: p_httpOnly,
+		SameSite: p_cfg.GetSessionCookieSameSite(),
+	}
+}
+
+// endregion Private
@@ -0,0 +1,97 @@
+package csrf
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+)
+
+// HeaderName is the request header carrying the CSRF token of cookie based sessions.
+const HeaderName string = "X-CSRF-Token"
+
+// region Public
+
+// Token computes the CSRF token of a session.
+//
+// The token holds two HMACs separated by a dot: one of the access token, checked by the routes
+// authenticated with the access cookie, and one of the refresh token, checked by the refresh
+// route. The refresh route must stay reachable once the access token expired, so it cannot
+// depend on it. The token changes every time the tokens are refreshed, and does not need to be
+// stored: the server recomputes it from the cookies of each request.
+//
+// Parameters:
+//   - p_secret: The server side key.
+//   - p_accessToken: The access token of the session.
+//   - p_refreshToken: The refresh token of the session.
+//
+// Returns:
+//   - string: The token, made of two values encoded with unpadded base64url.
+func Token(p_secret []byte, p_accessToken string, p_refreshToken string) string {
+	return sum(p_secret, p_accessToken) + "." + sum(p_secret, p_refreshToken)
+}
+
+// WithAccess binds a CSRF token to a new access token, keeping the refresh part of the previous
+// token. It is used when only the access token of a session changes.
+//
+// Parameters:
+//   - p_secret: The server side key.
+//   - p_accessToken: The new access token of the session.
+//   - p_previous: The previous CSRF token of the session.
+//
+// Returns:
+//   - string: The new token.
+func WithAccess(p_secret []byte, p_accessToken string, p_previous string) string {
+	_, refresh, _ := strings.Cut(p_previous, ".")
+	return sum(p_secret, p_accessToken) + "." + refresh
+}
+
+// Verify checks in constant time that a token was issued for the access token of a session.
+//
+// Parameters:
+//   - p_secret: The server side key.
+//   - p_accessToken: The access token of the session.
+//   - p_token: The token sent by the client in the HeaderName header.
+//
+// Returns:
+//   - bool: true if the token matches the session.
+func Verify(p_secret []byte, p_accessToken string, p_token string) bool {
+	access, _, _ := strings.Cut(p_token, ".")
+	return verify(p_secret, p_accessToken, access)
+}
+
+// VerifyRefresh checks in constant time that a token was issued for the refresh token of a
+// session.
+//
+// Parameters:
+//   - p_secret: The server side key.
+//   - p_refreshToken: The refresh token of the session.
+//   - p_token: The token sent by the client in the HeaderName header.
+//
+// Returns:
+//   - bool: true if the token matches the session.
+func VerifyRefresh(p_secret []byte, p_refreshToken string, p_token string) bool {
+	_, refresh, _ := strings.Cut(p_token, ".")
+	return verify(p_secret, p_refreshToken, refresh)
+}
+
+// endregion Public
+
+// region Private
+
+// sum returns the HMAC of a session token, encoded with unpadded base64url.
+func sum(p_secret []byte, p_value string) string {
+	mac := hmac.New(sha256.New, p_secret)
+	mac.Write([]byte(p_value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify compares in constant time the HMAC of a session token with the one sent by a client.
+func verify(p_secret []byte, p_value string, p_sum string) bool {
+	if p_value == "" || p_sum == "" {
+		return false
+	}
+	return hmac.Equal([]byte(sum(p_secret, p_value)), []byte(p_sum))
+}
+
+// endregion Private
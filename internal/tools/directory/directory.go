@@ -0,0 +1,187 @@
+package directory
+
+import (
+	ldap_config "cerberus/pkg/config/ldap"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+var (
+	ErrUserNotFound       = errors.New("user not found in the directory") // ErrUserNotFound is returned when the search matches no entry.
+	ErrInvalidCredentials = errors.New("invalid directory credentials")   // ErrInvalidCredentials is returned when the directory refuses the password of the user.
+	ErrAmbiguousUser      = errors.New("several directory entries match") // ErrAmbiguousUser is returned when the search matches more than one entry.
+	ErrDirectoryFailed    = errors.New("directory request failed")        // ErrDirectoryFailed is returned when the directory cannot be reached or answers an error.
+)
+
+// Entry describes a user authenticated by the directory.
+//
+// Fields:
+//   - DN: The distinguished name of the entry, its stable ID in the directory.
+//   - Email: The email address of the user.
+//   - Name: The display name of the user.
+//   - Roles: The roles granted by the groups of the user, following the group-to-role mapping.
+type Entry struct {
+	DN    string
+	Email string
+	Name  string
+	Roles []string
+}
+
+// Client authenticates users against an LDAP directory. A connection is opened for every
+// authentication, so a Client is safe for concurrent use.
+type Client struct {
+	cfg *ldap_config.LDAPConfigData
+}
+
+// region Public
+
+// NewClient returns a client of the configured directory.
+//
+// Parameters:
+//   - p_cfg: The configuration of the directory.
+//
+// Returns:
+//   - *Client: The client, or nil if no directory URL is configured.
+func NewClient(p_cfg *ldap_config.LDAPConfigData) *Client {
+	if p_cfg.URL == "" {
+		return nil
+	}
+	return &Client{cfg: p_cfg}
+}
+
+// Authenticate looks up the entry of a user and checks their password by binding as them.
+//
+// The entry is searched under the base DN with the user filter, bound as the service account.
+// Empty passwords are refused before reaching the directory, as they would perform an
+// unauthenticated bind that most directories accept.
+//
+// Parameters:
+//   - p_login: The email address the user logs in with.
+//   - p_password: The password of the user.
+//
+// Returns:
+//   - *Entry: The entry of the user, with its mapped attributes and roles.
+//   - error: ErrUserNotFound, ErrInvalidCredentials, ErrAmbiguousUser, or an error wrapping
+//     ErrDirectoryFailed.
+func (c *Client) Authenticate(p_login string, p_password string) (*Entry, error) {
+	conn, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if c.cfg.BindDN != "" {
+		if err := conn.Bind(c.cfg.BindDN, c.cfg.GetBindPassword()); err != nil {
+			return nil, fmt.Errorf("%w - service bind: %s", ErrDirectoryFailed, err.Error())
+		}
+	}
+
+	attrs := []string{c.cfg.GetAttrEmail(), c.cfg.GetAttrName(), c.cfg.GetAttrGroups()}
+	filter := strings.ReplaceAll(c.cfg.GetUserFilter(), "{login}", ldap.EscapeFilter(p_login))
+	res, err := conn.Search(ldap.NewSearchRequest(c.cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		2, int(c.cfg.GetTimeout().Seconds()), false, filter, attrs, nil))
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("%w - search: %s", ErrDirectoryFailed, err.Error())
+	}
+
+	switch {
+	case res == nil || len(res.Entries) == 0:
+		return nil, ErrUserNotFound
+	case len(res.Entries) > 1:
+		return nil, ErrAmbiguousUser
+	}
+	found := res.Entries[0]
+
+	if p_password == "" {
+		return nil, ErrInvalidCredentials
+	}
+	if err := conn.Bind(found.DN, p_password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("%w - user bind: %s", ErrDirectoryFailed, err.Error())
+	}
+
+	entry := &Entry{
+		DN:    found.DN,
+		Email: found.GetAttributeValue(c.cfg.GetAttrEmail()),
+		Name:  found.GetAttributeValue(c.cfg.GetAttrName()),
+		Roles: c.mapRoles(found.GetAttributeValues(c.cfg.GetAttrGroups())),
+	}
+	if entry.Email == "" {
+		entry.Email = p_login
+	}
+
+	return entry, nil
+}
+
+// ManagedRoles returns the roles granted through the group-to-role mapping. The directory is
+// the source of truth for these roles: they are granted and revoked on every login.
+//
+// Returns:
+//   - []string: The roles of the mapping, without duplicates.
+func (c *Client) ManagedRoles() []string {
+	roles := make([]string, 0, len(c.cfg.GroupRoles))
+	for _, gr := range c.cfg.GroupRoles {
+		if !slices.Contains(roles, gr.Role) {
+			roles = append(roles, gr.Role)
+		}
+	}
+	return roles
+}
+
+// endregion Public
+
+// region Private
+
+// dial connects to the directory, upgrading "ldap://" connections when StartTLS is enabled.
+func (c *Client) dial() (*ldap.Conn, error) {
+	timeout := c.cfg.GetTimeout()
+
+	conn, err := ldap.DialURL(c.cfg.URL, ldap.DialWithDialer(&net.Dialer{Timeout: timeout}))
+	if err != nil {
+		return nil, fmt.Errorf("%w - %s", ErrDirectoryFailed, err.Error())
+	}
+	conn.SetTimeout(timeout)
+
+	if c.cfg.StartTLS {
+		host := ""
+		if u, err := url.Parse(c.cfg.URL); err == nil {
+			host = u.Hostname()
+		}
+		if err := conn.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("%w - StartTLS: %s", ErrDirectoryFailed, err.Error())
+		}
+	}
+
+	return conn, nil
+}
+
+// mapRoles returns the roles granted by a list of group DNs. DNs are compared
+// case-insensitively, ignoring the spacing between their components.
+func (c *Client) mapRoles(p_groups []string) []string {
+	roles := make([]string, 0)
+	for _, g := range p_groups {
+		group, err := ldap.ParseDN(g)
+		if err != nil {
+			continue
+		}
+		for _, gr := range c.cfg.GroupRoles {
+			mapped, err := ldap.ParseDN(gr.GroupDN)
+			if err == nil && group.EqualFold(mapped) && !slices.Contains(roles, gr.Role) {
+				roles = append(roles, gr.Role)
+			}
+		}
+	}
+	return roles
+}
+
+// endregion Private
@@ -0,0 +1,270 @@
+package directory
+
+import (
+	ldap_config "cerberus/pkg/config/ldap"
+	"errors"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+const (
+	serviceDN       string = "cn=svc,dc=example,dc=com"
+	servicePassword string = "svc-secret"
+	adaDN           string = "uid=ada,ou=people,dc=example,dc=com"
+	adminsDN        string = "cn=admins,ou=groups,dc=example,dc=com"
+)
+
+// fakeEntry is an entry of the fake directory.
+type fakeEntry struct {
+	dn       string
+	password string
+	attrs    map[string][]string
+}
+
+// fakeLDAP is an in-process LDAP server answering simple binds and searches. Like most
+// directories, it accepts unauthenticated binds: a DN with an empty password.
+type fakeLDAP struct {
+	ln      net.Listener
+	entries []fakeEntry
+
+	mu      sync.Mutex
+	binds   []string
+	filters []string
+}
+
+func newFakeLDAP(t *testing.T) *fakeLDAP {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeLDAP{ln: ln, entries: []fakeEntry{
+		{dn: serviceDN, password: servicePassword, attrs: map[string][]string{"cn": {"svc"}}},
+		{dn: adaDN, password: "ada-secret", attrs: map[string][]string{
+			"mail": {"ada@example.com"}, "cn": {"Ada Lovelace"}, "memberOf": {"CN=Admins, OU=Groups, DC=example, DC=com"},
+		}},
+		{dn: "uid=bob,ou=people,dc=example,dc=com", password: "bob-secret", attrs: map[string][]string{
+			"mail": {"bob@example.com"}, "cn": {"Bob"},
+		}},
+	}}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+// client returns a directory client of the fake server, searching as the service account.
+func (f *fakeLDAP) client(t *testing.T) *Client {
+	t.Setenv("LDAP_BIND_PASSWORD", servicePassword)
+	return NewClient(&ldap_config.LDAPConfigData{
+		URL:        "ldap://" + f.ln.Addr().String(),
+		BindDN:     serviceDN,
+		BaseDN:     "dc=example,dc=com",
+		GroupRoles: []ldap_config.GroupRole{{GroupDN: adminsDN, Role: "admin"}},
+		Timeout:    "2s",
+	})
+}
+
+// boundAs tells whether a bind was attempted with the given DN.
+func (f *fakeLDAP) boundAs(p_dn string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Contains(f.binds, p_dn)
+}
+
+// lastFilter returns the filter of the last search.
+func (f *fakeLDAP) lastFilter() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.filters) == 0 {
+		return ""
+	}
+	return f.filters[len(f.filters)-1]
+}
+
+func (f *fakeLDAP) serve(p_conn net.Conn) {
+	defer p_conn.Close()
+
+	for {
+		msg, err := ber.ReadPacket(p_conn)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+		id, _ := msg.Children[0].Value.(int64)
+		op := msg.Children[1]
+
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			dn, password := op.Children[1].Data.String(), op.Children[2].Data.String()
+			f.mu.Lock()
+			f.binds = append(f.binds, dn)
+			f.mu.Unlock()
+
+			code := ldap.LDAPResultInvalidCredentials
+			if i := slices.IndexFunc(f.entries, func(e fakeEntry) bool { return e.dn == dn }); i >= 0 &&
+				(password == "" || password == f.entries[i].password) {
+				code = ldap.LDAPResultSuccess
+			}
+			p_conn.Write(fakeResponse(id, fakeResult(ldap.ApplicationBindResponse, code)).Bytes())
+
+		case ldap.ApplicationSearchRequest:
+			filter := op.Children[6]
+			str, _ := ldap.DecompileFilter(filter)
+			f.mu.Lock()
+			f.filters = append(f.filters, str)
+			f.mu.Unlock()
+
+			for _, e := range f.entries {
+				if fakeMatch(filter, e) {
+					p_conn.Write(fakeResponse(id, fakeSearchEntry(e)).Bytes())
+				}
+			}
+			p_conn.Write(fakeResponse(id, fakeResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess)).Bytes())
+
+		case ldap.ApplicationUnbindRequest:
+			return
+		}
+	}
+}
+
+// fakeMatch evaluates the filters used by the tests: and, or, not, equality and presence.
+func fakeMatch(p_filter *ber.Packet, p_entry fakeEntry) bool {
+	switch p_filter.Tag {
+	case ldap.FilterAnd:
+		for _, c := range p_filter.Children {
+			if !fakeMatch(c, p_entry) {
+				return false
+			}
+		}
+		return true
+
+	case ldap.FilterOr:
+		return slices.ContainsFunc(p_filter.Children, func(c *ber.Packet) bool { return fakeMatch(c, p_entry) })
+
+	case ldap.FilterNot:
+		return !fakeMatch(p_filter.Children[0], p_entry)
+
+	case ldap.FilterEqualityMatch:
+		values := p_entry.attrs[p_filter.Children[0].Data.String()]
+		want := p_filter.Children[1].Data.String()
+		return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, want) })
+
+	case ldap.FilterPresent:
+		return len(p_entry.attrs[p_filter.Data.String()]) > 0
+	}
+	return false
+}
+
+func fakeResponse(p_id int64, p_op *ber.Packet) *ber.Packet {
+	msg := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	msg.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, p_id, "Message ID"))
+	msg.AppendChild(p_op)
+	return msg
+}
+
+func fakeResult(p_tag ber.Tag, p_code int) *ber.Packet {
+	res := ber.Encode(ber.ClassApplication, ber.TypeConstructed, p_tag, nil, "Result")
+	res.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, p_code, "Result Code"))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return res
+}
+
+func fakeSearchEntry(p_entry fakeEntry) *ber.Packet {
+	res := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, p_entry.dn, "Object Name"))
+
+	attrs := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for name, values := range p_entry.attrs {
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, v := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "Value"))
+		}
+		attr.AppendChild(set)
+		attrs.AppendChild(attr)
+	}
+	res.AppendChild(attrs)
+	return res
+}
+
+func TestAuthenticate(t *testing.T) {
+	f := newFakeLDAP(t)
+
+	entry, err := f.client(t).Authenticate("Ada@example.com", "ada-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.DN != adaDN || entry.Email != "ada@example.com" || entry.Name != "Ada Lovelace" {
+		t.Errorf("entry = %+v", entry)
+	}
+	if !slices.Equal(entry.Roles, []string{"admin"}) {
+		t.Errorf("roles = %v, want [admin]", entry.Roles)
+	}
+	if !f.boundAs(serviceDN) || !f.boundAs(adaDN) {
+		t.Errorf("binds = %v, want the service account then the user", f.binds)
+	}
+}
+
+func TestAuthenticateRefusesWrongPassword(t *testing.T) {
+	f := newFakeLDAP(t)
+
+	if _, err := f.client(t).Authenticate("ada@example.com", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidCredentials)
+	}
+}
+
+func TestAuthenticateRefusesEmptyPassword(t *testing.T) {
+	f := newFakeLDAP(t)
+
+	if _, err := f.client(t).Authenticate("ada@example.com", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidCredentials)
+	}
+	// The directory would have accepted the unauthenticated bind.
+	if f.boundAs(adaDN) {
+		t.Error("an unauthenticated bind reached the directory")
+	}
+}
+
+func TestAuthenticateEscapesLogin(t *testing.T) {
+	tests := []struct {
+		login  string
+		filter string
+	}{
+		{"*", `(mail=\2a)`},
+		{"*)(mail=*", `(mail=\2a\29\28mail=\2a)`},
+		{"ada@example.com)(|(cn=*", `(mail=ada@example.com\29\28|\28cn=\2a)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.login, func(t *testing.T) {
+			f := newFakeLDAP(t)
+
+			if _, err := f.client(t).Authenticate(tt.login, "ada-secret"); !errors.Is(err, ErrUserNotFound) {
+				t.Errorf("err = %v, want %v", err, ErrUserNotFound)
+			}
+			if got := f.lastFilter(); got != tt.filter {
+				t.Errorf("filter = %s, want %s", got, tt.filter)
+			}
+			if f.boundAs(adaDN) {
+				t.Error("bound as a user the login does not match")
+			}
+		})
+	}
+}
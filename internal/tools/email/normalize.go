@@ -0,0 +1,53 @@
+package email
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// ErrInvalidEmail is returned when an address cannot be split into a local part and a domain.
+var ErrInvalidEmail = errors.New("invalid email address")
+
+// region Public
+
+// Normalize returns the canonical form of an email address.
+//
+// The canonical form is obtained by:
+//  1. Trimming surrounding whitespace.
+//  2. Converting the domain to its ASCII (punycode) form, so "bücher.de" and
+//     "xn--bcher-kva.de" map to the same value.
+//  3. Lowercasing the domain and removing a trailing dot.
+//  4. Lowercasing the local part, only when p_lowerLocal is true.
+//
+// Parameters:
+//   - p_addr: The raw email address as provided by the client.
+//   - p_lowerLocal: Whether the local part (before the "@") should also be lowercased.
+//
+// Returns:
+//   - string: The canonical email address.
+//   - error: ErrInvalidEmail if the address is malformed or the domain is not a valid IDN.
+func Normalize(p_addr string, p_lowerLocal bool) (string, error) {
+	addr := strings.TrimSpace(p_addr)
+
+	at := strings.LastIndex(addr, "@")
+	if at <= 0 || at == len(addr)-1 {
+		return "", ErrInvalidEmail
+	}
+
+	local, domain := addr[:at], strings.TrimSuffix(addr[at+1:], ".")
+
+	ascii, err := idna.Lookup.ToASCII(domain)
+	if err != nil || ascii == "" {
+		return "", ErrInvalidEmail
+	}
+
+	if p_lowerLocal {
+		local = strings.ToLower(local)
+	}
+
+	return local + "@" + strings.ToLower(ascii), nil
+}
+
+// endregion Public
@@ -3,25 +3,85 @@ package jwt
 import (
 	"cerberus/internal/tools/logger"
 	"cerberus/pkg/config"
+	"cerberus/pkg/jwks"
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // Claims represents the custom claims structure for JWT tokens.
+//
+// Fields:
+//   - UserID: The ID of the user the token was issued to.
+//   - Scope: The space separated scopes granted to the token, empty for session tokens.
+//   - AuthTime: When the user last authenticated in the session ("auth_time" claim).
+//   - AMR: The authentication methods used in the session ("amr" claim, RFC 8176 values).
+//   - ACR: The authentication context class reached in the session ("acr" claim).
+//   - Act: The party acting as the user, set on impersonation tokens ("act" claim, RFC 8693).
 type Claims struct {
-	UserID string `json:"user_id"`
+	UserID   string           `json:"user_id"`
+	Scope    string           `json:"scope,omitempty"`
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	AMR      []string         `json:"amr,omitempty"`
+	ACR      string           `json:"acr,omitempty"`
+	Act      *Actor           `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// Actor identifies the party acting on behalf of the subject of a token, as the "act" claim
+// of RFC 8693. Actors acting for other actors are nested.
+//
+// Fields:
+//   - Subject: The ID of the acting user.
+//   - Act: The actor the acting user itself acts for, nil if none.
+type Actor struct {
+	Subject string `json:"sub"`
+	Act     *Actor `json:"act,omitempty"`
+}
+
+// Authentication describes how the user of a session authenticated. It is carried by the
+// "auth_time", "amr" and "acr" claims of the access tokens of the session.
+//
+// Fields:
+//   - Time: When the user last authenticated.
+//   - Methods: The authentication methods used (e.g., "pwd", "otp").
+//   - Class: The authentication context class reached (e.g., "aal2").
+type Authentication struct {
+	Time    time.Time `json:"time"`
+	Methods []string  `json:"methods"`
+	Class   string    `json:"class"`
+}
+
 // JWTGenerator is responsible for generating and validating JWT tokens.
+//
+// Tokens are signed with HS256 and the JWT_SECRET key, unless a signing key file is
+// configured. In that case they are signed with the asymmetric key, whose public part is
+// published through JWKS so that other services can verify tokens without the secret.
+//
+// Fields:
+//   - Secret: The HMAC key signing the tokens when no signing key file is configured.
+//   - Duration: The lifetime of the tokens.
+//   - Issuer: The "iss" claim of the tokens, required on validation.
+//   - Audiences: The "aud" claim of the tokens; validated tokens must target at least one of them.
+//   - Leeway: The clock skew tolerated on the "exp", "nbf" and "iat" claims.
 type JWTGenerator struct {
-	Secret   []byte
-	Duration time.Duration
+	Secret    []byte
+	Duration  time.Duration
+	Issuer    string
+	Audiences []string
+	Leeway    time.Duration
+
+	method    jwt.SigningMethod // The only algorithm used to sign and accepted on validation.
+	signKey   interface{}       // The key signing the tokens.
+	verifyKey interface{}       // The key verifying the tokens.
+	keyID     string            // The "kid" header of the tokens, empty for HS256.
 }
 
 // NewJWTGenerator creates a new JWTGenerator instance with the provided configuration.
@@ -33,43 +93,184 @@ type JWTGenerator struct {
 //
 // Returns:
 //   - *JWTGenerator: A pointer to the newly created JWTGenerator instance.
-func NewJWTGenerator(p_cfg *config.ConfigData) *JWTGenerator {
+//   - error: An error if the configured signing key file cannot be loaded, nil otherwise.
+func NewJWTGenerator(p_cfg *config.ConfigData) (*JWTGenerator, error) {
 	d, err := time.ParseDuration(p_cfg.RedisData.JWTDuration)
 	if err != nil {
 		logger.Log("Failed to parse JWT configuration, fail to default", logger.ERROR)
 		d = 15
 	}
-	return &JWTGenerator{
-		Secret:   []byte(os.Getenv("JWT_SECRET")),
-		Duration: d,
+	gen := &JWTGenerator{
+		Secret:    []byte(os.Getenv("JWT_SECRET")),
+		Duration:  d,
+		Issuer:    p_cfg.GetJWTIssuer(),
+		Audiences: p_cfg.GetJWTAudiences(),
+		Leeway:    p_cfg.GetJWTLeeway(),
+	}
+
+	if p_cfg.JWTSigningKeyFile == "" {
+		gen.method = jwt.SigningMethodHS256
+		gen.signKey = gen.Secret
+		gen.verifyKey = gen.Secret
+		return gen, nil
+	}
+
+	key, method, err := loadSigningKey(p_cfg.JWTSigningKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWT signing key - %w", err)
+	}
+	if gen.keyID, err = jwks.KeyID(key.Public()); err != nil {
+		return nil, err
+	}
+	gen.method = method
+	gen.signKey = key
+	gen.verifyKey = key.Public()
+
+	logger.Log(fmt.Sprintf("🔑 Signing tokens with %s key %s", method.Alg(), gen.keyID), logger.INFO)
+	return gen, nil
+}
+
+// JWKS returns the public keys verifying the tokens, to be published to other services.
+// The set is empty when tokens are signed with the HS256 shared secret.
+//
+// Returns:
+//   - jwks.Set: The JSON Web Key Set.
+func (gen *JWTGenerator) JWKS() jwks.Set {
+	set := jwks.Set{Keys: []jwks.Key{}}
+	if gen.keyID == "" {
+		return set
+	}
+
+	key, err := jwks.FromPublicKey(gen.keyID, gen.method.Alg(), gen.verifyKey)
+	if err != nil {
+		logger.Log("Failed to encode the JWT verification key - "+err.Error(), logger.ERROR)
+		return set
 	}
+	set.Keys = append(set.Keys, key)
+	return set
 }
 
 // GenerateJWT generates a new JWT token for the given user ID.
+// Every token gets a unique ID ("jti" claim), used to publish its revocation, and carries the
+// configured issuer and audiences.
 //
 // Parameters:
 //   - p_usrId: The user ID to be included in the token claims.
+//   - p_auth: How the user authenticated, set in the "auth_time", "amr" and "acr" claims.
+//     The claims are omitted when nil.
 //
 // Returns:
 //   - string: The generated JWT token as a string.
 //   - error: An error if token generation fails, nil otherwise.
-func (gen *JWTGenerator) GenerateJWT(p_usrId string) (string, error) {
-	var expiration time.Time = time.Now().Add(gen.Duration)
+func (gen *JWTGenerator) GenerateJWT(p_usrId string, p_auth *Authentication) (string, error) {
+	now := time.Now()
+	var expiration time.Time = now.Add(gen.Duration)
 
 	claims := &Claims{
 		UserID: p_usrId,
 		RegisteredClaims: jwt.RegisteredClaims{
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        uuid.NewString(),
+			Issuer:    gen.Issuer,
+			Subject:   p_usrId,
+			Audience:  gen.Audiences,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(expiration),
 		},
 	}
+	if p_auth != nil {
+		claims.AuthTime = jwt.NewNumericDate(p_auth.Time)
+		claims.AMR = p_auth.Methods
+		claims.ACR = p_auth.Class
+	}
 
-	var tkn *jwt.Token = jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return tkn.SignedString(gen.Secret)
+	var tkn *jwt.Token = jwt.NewWithClaims(gen.method, claims)
+	if gen.keyID != "" {
+		tkn.Header["kid"] = gen.keyID
+	}
+	return tkn.SignedString(gen.signKey)
+}
+
+// GenerateExchangedJWT generates a token issued by token exchange (RFC 8693). Unlike session
+// tokens, it always carries a scope, and may target fewer audiences and act for another user.
+//
+// Parameters:
+//   - p_usrId: The user ID to be included in the token claims.
+//   - p_scope: The space separated scopes granted to the token.
+//   - p_audiences: The "aud" claim of the token, a subset of the configured audiences.
+//   - p_act: The party acting as the user, set as "act"; omitted when nil.
+//   - p_expiresAt: When the token expires.
+//
+// Returns:
+//   - string: The generated JWT token as a string.
+//   - *Claims: The claims of the token.
+//   - error: An error if token generation fails, nil otherwise.
+func (gen *JWTGenerator) GenerateExchangedJWT(p_usrId string, p_scope string, p_audiences []string, p_act *Actor,
+	p_expiresAt time.Time) (string, *Claims, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID: p_usrId,
+		Scope:  p_scope,
+		Act:    p_act,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    gen.Issuer,
+			Subject:   p_usrId,
+			Audience:  p_audiences,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(p_expiresAt),
+		},
+	}
+
+	var tkn *jwt.Token = jwt.NewWithClaims(gen.method, claims)
+	if gen.keyID != "" {
+		tkn.Header["kid"] = gen.keyID
+	}
+	signed, err := tkn.SignedString(gen.signKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
+}
+
+// OpaqueClaims describes an opaque token, such as a personal access token, with the claims an
+// access token of the same user would carry, so that both are handled alike once validated.
+//
+// Parameters:
+//   - p_id: The unique ID of the token, set as "jti".
+//   - p_usrId: The ID of the user the token acts as.
+//   - p_scope: The space separated scopes granted to the token.
+//   - p_issuedAt: When the token was created.
+//   - p_expiresAt: When the token expires, nil if it never does.
+//
+// Returns:
+//   - *Claims: The claims of the token.
+func (gen *JWTGenerator) OpaqueClaims(p_id string, p_usrId string, p_scope string, p_issuedAt time.Time,
+	p_expiresAt *time.Time) *Claims {
+	claims := &Claims{
+		UserID: p_usrId,
+		Scope:  p_scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       p_id,
+			Issuer:   gen.Issuer,
+			Subject:  p_usrId,
+			IssuedAt: jwt.NewNumericDate(p_issuedAt),
+		},
+	}
+	if p_expiresAt != nil {
+		claims.ExpiresAt = jwt.NewNumericDate(*p_expiresAt)
+	}
+	return claims
 }
 
 // ValidateJWT validates the provided JWT token and returns the claims if valid.
 //
+// Only signatures of the configured algorithm are accepted, and the token must carry the configured issuer, at
+// least one of the configured audiences, a "jti" and an expiration. Time based claims are
+// checked with the configured leeway. Tokens minted by other systems sharing the secret are
+// therefore rejected.
+//
 // Parameters:
 //   - p_token: The JWT token string to validate.
 //
@@ -77,12 +278,8 @@ func (gen *JWTGenerator) GenerateJWT(p_usrId string) (string, error) {
 //   - *Claims: A pointer to the Claims structure if the token is valid.
 //   - error: An error if the token is invalid or validation fails, nil otherwise.
 func (gen *JWTGenerator) ValidateJWT(p_token string) (*Claims, error) {
-	claims := &Claims{}
-
-	token, err := jwt.ParseWithClaims(p_token, claims, func(t *jwt.Token) (interface{}, error) {
-		return gen.Secret, nil
-	})
-	if err != nil || !token.Valid {
+	claims, err := gen.parse(p_token)
+	if err != nil {
 		return nil, errors.New("invalid token")
 	}
 
@@ -119,17 +316,49 @@ func (gen *JWTGenerator) ValidateRefreshToken(p_savedToken string, p_targetToken
 
 // GetUserIDFromToken extracts the user ID from the provided JWT token.
 // It parses the token using the JWT secret stored in the JWTGenerator instance
-// and validates the token's signature. If the token is valid, it returns the user ID
+// and validates it like ValidateJWT. If the token is valid, it returns the user ID
 // embedded in the token. If the token is invalid or parsing fails, it returns an error.
 func (gen *JWTGenerator) GetUserIDFromToken(p_token string) (string, error) {
-	claims := &Claims{}
-
-	_, err := jwt.ParseWithClaims(p_token, claims, func(t *jwt.Token) (interface{}, error) {
-		return gen.Secret, nil
-	})
+	claims, err := gen.parse(p_token)
 	if err != nil {
 		return "", errors.New("invalid token")
 	}
 
 	return claims.UserID, nil
 }
+
+// region Private
+
+// parse verifies the signature and the registered claims of a token.
+func (gen *JWTGenerator) parse(p_token string) (*Claims, error) {
+	claims := &Claims{}
+
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
+		return gen.verifyKey, nil
+	}
+
+	token, err := jwt.ParseWithClaims(p_token, claims, keyFunc,
+		jwt.WithValidMethods([]string{gen.method.Alg()}),
+		jwt.WithIssuer(gen.Issuer),
+		jwt.WithLeeway(gen.Leeway),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+
+	if claims.ID == "" {
+		return nil, fmt.Errorf("%w - missing jti", jwt.ErrTokenInvalidClaims)
+	}
+	if !slices.ContainsFunc(claims.Audience, func(a string) bool { return slices.Contains(gen.Audiences, a) }) {
+		return nil, jwt.ErrTokenInvalidAudience
+	}
+
+	return claims, nil
+}
+
+// endregion Private
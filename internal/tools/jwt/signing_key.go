@@ -0,0 +1,71 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// region Private
+
+// loadSigningKey reads a PEM encoded private key (PKCS#8, PKCS#1 or SEC 1) and returns it
+// with the signing method matching its type: RS256 for RSA, ES256/ES384/ES512 for the
+// P-256/P-384/P-521 curves, and EdDSA for Ed25519.
+func loadSigningKey(p_path string) (crypto.Signer, jwt.SigningMethod, error) {
+	data, err := os.ReadFile(p_path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, nil, errors.New("no PEM block found")
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < 2048 {
+			return nil, nil, errors.New("RSA keys must be at least 2048 bits long")
+		}
+		return k, jwt.SigningMethodRS256, nil
+
+	case *ecdsa.PrivateKey:
+		switch k.Curve.Params().Name {
+		case "P-256":
+			return k, jwt.SigningMethodES256, nil
+		case "P-384":
+			return k, jwt.SigningMethodES384, nil
+		case "P-521":
+			return k, jwt.SigningMethodES512, nil
+		}
+		return nil, nil, fmt.Errorf("unsupported curve %s", k.Curve.Params().Name)
+
+	case ed25519.PrivateKey:
+		return k, jwt.SigningMethodEdDSA, nil
+	}
+
+	return nil, nil, fmt.Errorf("unsupported key type %T", key)
+}
+
+// endregion Private
@@ -0,0 +1,81 @@
+package mailer
+
+import (
+	"cerberus/internal/tools/logger"
+	"cerberus/pkg/config"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+// Mailer is implemented by every outbound email transport.
+type Mailer interface {
+	// Send delivers a plain text email to a single recipient.
+	Send(p_to string, p_subject string, p_body string) error
+}
+
+// SMTPMailer sends emails through an SMTP server using PLAIN authentication.
+type SMTPMailer struct {
+	Address  string
+	From     string
+	Username string
+	Password string
+}
+
+// LogMailer writes emails to the application log instead of sending them.
+// It is used when no SMTP server is configured, typically during development.
+type LogMailer struct{}
+
+// region Public
+
+// NewMailer creates the Mailer matching the provided configuration.
+//
+// Parameters:
+//   - p_cfg: A pointer to the ConfigData structure containing the mail configuration.
+//
+// Returns:
+//   - Mailer: An SMTPMailer if an SMTP host is configured, a LogMailer otherwise.
+func NewMailer(p_cfg *config.ConfigData) Mailer {
+	if p_cfg.MailData.Host == "" {
+		logger.Log("📭 No SMTP host configured, emails will be logged", logger.WARN)
+		return &LogMailer{}
+	}
+
+	usr, pwd := p_cfg.MailData.GetCredentials()
+	return &SMTPMailer{
+		Address:  p_cfg.MailData.GetAddress(),
+		From:     p_cfg.MailData.GetFrom(),
+		Username: usr,
+		Password: pwd,
+	}
+}
+
+// Send delivers a plain text email through the configured SMTP server.
+//
+// Parameters:
+//   - p_to: The recipient email address.
+//   - p_subject: The subject of the email.
+//   - p_body: The plain text body of the email.
+//
+// Returns:
+//   - error: An error if the email could not be sent, nil otherwise.
+func (m *SMTPMailer) Send(p_to string, p_subject string, p_body string) error {
+	var auth smtp.Auth
+	if m.Username != "" {
+		host, _, _ := strings.Cut(m.Address, ":")
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=\"UTF-8\"\r\n\r\n%s\r\n", m.From, p_to, p_subject, p_body)
+
+	return smtp.SendMail(m.Address, auth, m.From, []string{p_to}, []byte(msg))
+}
+
+// Send writes the email to the log at INFO level and never fails.
+func (m *LogMailer) Send(p_to string, p_subject string, p_body string) error {
+	logger.Log(fmt.Sprintf("📧 Email to %s - %s\n%s", p_to, p_subject, p_body), logger.INFO)
+	return nil
+}
+
+// endregion Public
@@ -0,0 +1,102 @@
+package oauth
+
+import (
+	oauth_config "cerberus/pkg/config/oauth"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	gitHubURL    string = "https://github.com"     // gitHubURL is the default issuer of GitHub providers.
+	gitHubAPIURL string = "https://api.github.com" // gitHubAPIURL is the REST API of github.com.
+)
+
+// gitHubUser holds the fields of the "/user" endpoint used by Cerberus.
+type gitHubUser struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// gitHubEmail is an entry of the "/user/emails" endpoint.
+type gitHubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+// gitHubProvider is GitHub, or a GitHub Enterprise Server when an issuer is configured.
+// GitHub does not implement OpenID Connect, so the user is read from the REST API.
+type gitHubProvider struct {
+	client *http.Client
+	conf   *oauth2.Config
+	apiURL string
+}
+
+// region Public
+
+// AuthCodeURL returns the authorization URL of GitHub with the PKCE challenge. The nonce is
+// not used by GitHub.
+func (p *gitHubProvider) AuthCodeURL(_ context.Context, p_state string, _ string, p_verifier string) (string, error) {
+	return p.conf.AuthCodeURL(p_state, oauth2.S256ChallengeOption(p_verifier)), nil
+}
+
+// Identify exchanges the authorization code and reads the user from the REST API. The email
+// address is the primary address of the account, verified only if GitHub verified it.
+func (p *gitHubProvider) Identify(p_ctx context.Context, p_code string, _ string, p_verifier string) (*Identity, error) {
+	tkn, err := exchange(p_ctx, p.client, p.conf, p_code, p_verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var usr gitHubUser
+	if err := getJSON(p_ctx, p.client, p.apiURL+"/user", tkn.AccessToken, &usr); err != nil {
+		return nil, err
+	}
+
+	idt := &Identity{Subject: strconv.FormatInt(usr.ID, 10), Email: usr.Email, Name: usr.Name}
+	if idt.Name == "" {
+		idt.Name = usr.Login
+	}
+
+	var emails []gitHubEmail
+	if err := getJSON(p_ctx, p.client, p.apiURL+"/user/emails", tkn.AccessToken, &emails); err == nil {
+		for _, e := range emails {
+			if e.Primary {
+				idt.Email = e.Email
+				idt.EmailVerified = e.Verified
+			}
+		}
+	}
+
+	return idt, nil
+}
+
+// endregion Public
+
+// region Private
+
+func newGitHubProvider(p_cfg *oauth_config.ProviderConfigData, p_client *http.Client) *gitHubProvider {
+	base, apiURL := gitHubURL, gitHubAPIURL
+	if p_cfg.Issuer != "" && p_cfg.Issuer != gitHubURL {
+		base = p_cfg.Issuer
+		apiURL = strings.TrimSuffix(p_cfg.Issuer, "/") + "/api/v3"
+	}
+
+	return &gitHubProvider{
+		client: p_client,
+		apiURL: apiURL,
+		conf: oauth2Config(p_cfg, oauth2.Endpoint{
+			AuthURL:   base + "/login/oauth/authorize",
+			TokenURL:  base + "/login/oauth/access_token",
+			AuthStyle: oauth2.AuthStyleInParams,
+		}),
+	}
+}
+
+// endregion Private
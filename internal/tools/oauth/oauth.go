@@ -0,0 +1,164 @@
+package oauth
+
+import (
+	"cerberus/internal/tools/logger"
+	oauth_config "cerberus/pkg/config/oauth"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	httpTimeout      time.Duration = 10 * time.Second // httpTimeout bounds every call made to an identity provider.
+	maxResponseBytes int64         = 1 << 20          // maxResponseBytes bounds the documents read from an identity provider.
+)
+
+var (
+	ErrExchangeFailed = errors.New("authorization code exchange failed") // ErrExchangeFailed is returned when the provider refuses the authorization code.
+	ErrInvalidIDToken = errors.New("invalid id token")                   // ErrInvalidIDToken is returned when the ID token of an OpenID Connect provider cannot be trusted.
+	ErrProviderFailed = errors.New("identity provider request failed")   // ErrProviderFailed is returned when the provider cannot be reached or answers an error.
+)
+
+// Identity describes a user authenticated by an external provider.
+//
+// Fields:
+//   - Subject: The stable ID of the user at the provider.
+//   - Email: The email address of the user, empty if the provider did not share it.
+//   - EmailVerified: Whether the provider verified the email address.
+//   - Name: The display name of the user.
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Provider is an external identity provider supporting the OAuth2 authorization code flow
+// with PKCE.
+type Provider interface {
+	// AuthCodeURL returns the URL of the provider the browser is redirected to.
+	//
+	// Parameters:
+	//   - p_ctx: The context of the request.
+	//   - p_state: The opaque value sent back to the callback.
+	//   - p_nonce: The value bound to the ID token, ignored by providers without OpenID Connect.
+	//   - p_verifier: The PKCE code verifier; its S256 challenge is sent.
+	AuthCodeURL(p_ctx context.Context, p_state string, p_nonce string, p_verifier string) (string, error)
+
+	// Identify exchanges an authorization code and returns the authenticated user.
+	//
+	// Parameters:
+	//   - p_ctx: The context of the request.
+	//   - p_code: The authorization code received on the callback.
+	//   - p_nonce: The nonce sent with the authorization request.
+	//   - p_verifier: The PKCE code verifier sent with the authorization request.
+	Identify(p_ctx context.Context, p_code string, p_nonce string, p_verifier string) (*Identity, error)
+}
+
+// region Public
+
+// NewProviders creates the configured identity providers. Providers missing a client ID or a
+// redirect URL, and providers of an unknown kind, are skipped with a warning.
+//
+// Parameters:
+//   - p_cfg: A pointer to the OAuthConfigData structure holding the providers.
+//
+// Returns:
+//   - map[string]Provider: The providers, by name.
+func NewProviders(p_cfg *oauth_config.OAuthConfigData) map[string]Provider {
+	providers := make(map[string]Provider)
+	client := &http.Client{Timeout: httpTimeout}
+
+	for name, pCfg := range p_cfg.Providers {
+		if pCfg.ClientID == "" || pCfg.RedirectURL == "" {
+			logger.Log(fmt.Sprintf("OAuth provider %q skipped, client ID or redirect URL missing", name), logger.WARN)
+			continue
+		}
+
+		switch pCfg.GetKind() {
+		case oauth_config.KindOIDC:
+			if pCfg.Issuer == "" {
+				logger.Log(fmt.Sprintf("OAuth provider %q skipped, issuer missing", name), logger.WARN)
+				continue
+			}
+			providers[name] = newOIDCProvider(pCfg, client)
+
+		case oauth_config.KindGitHub:
+			providers[name] = newGitHubProvider(pCfg, client)
+
+		default:
+			logger.Log(fmt.Sprintf("OAuth provider %q skipped, unknown kind %q", name, pCfg.Kind), logger.WARN)
+			continue
+		}
+
+		logger.Log(fmt.Sprintf("OAuth provider added: %s", name), logger.INFO)
+	}
+
+	return providers
+}
+
+// GenerateVerifier returns a new PKCE code verifier.
+func GenerateVerifier() string {
+	return oauth2.GenerateVerifier()
+}
+
+// endregion Public
+
+// region Private
+
+// oauth2Config builds the client configuration of a provider.
+func oauth2Config(p_cfg *oauth_config.ProviderConfigData, p_endpoint oauth2.Endpoint) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     p_cfg.ClientID,
+		ClientSecret: p_cfg.GetClientSecret(),
+		Endpoint:     p_endpoint,
+		RedirectURL:  p_cfg.RedirectURL,
+		Scopes:       p_cfg.GetScopes(),
+	}
+}
+
+// exchange trades an authorization code for the tokens of the user.
+func exchange(p_ctx context.Context, p_client *http.Client, p_conf *oauth2.Config, p_code string,
+	p_verifier string) (*oauth2.Token, error) {
+	ctx := context.WithValue(p_ctx, oauth2.HTTPClient, p_client)
+
+	tkn, err := p_conf.Exchange(ctx, p_code, oauth2.VerifierOption(p_verifier))
+	if err != nil {
+		return nil, fmt.Errorf("%w - %s", ErrExchangeFailed, err.Error())
+	}
+	return tkn, nil
+}
+
+// getJSON sends an authenticated GET request to a provider and decodes its JSON answer.
+func getJSON(p_ctx context.Context, p_client *http.Client, p_url string, p_accessToken string, p_out interface{}) error {
+	req, err := http.NewRequestWithContext(p_ctx, http.MethodGet, p_url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if p_accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+p_accessToken)
+	}
+
+	res, err := p_client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w - %s", ErrProviderFailed, err.Error())
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w - %s answered %d", ErrProviderFailed, p_url, res.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxResponseBytes)).Decode(p_out); err != nil {
+		return fmt.Errorf("%w - %s", ErrProviderFailed, err.Error())
+	}
+	return nil
+}
+
+// endregion Private
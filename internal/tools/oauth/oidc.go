@@ -0,0 +1,269 @@
+package oauth
+
+import (
+	oauth_config "cerberus/pkg/config/oauth"
+	"cerberus/pkg/jwks"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+const (
+	discoveryPath   string        = "/.well-known/openid-configuration"
+	keysTTL         time.Duration = time.Hour        // keysTTL is how long the signing keys of a provider are cached.
+	minKeysRefresh  time.Duration = 10 * time.Second // minKeysRefresh bounds how often an unknown key ID triggers a refresh.
+	idTokenLeeway   time.Duration = time.Minute      // idTokenLeeway is the clock skew tolerated on ID tokens.
+	discoveryMaxAge time.Duration = 24 * time.Hour   // discoveryMaxAge is how long the discovery document is cached.
+)
+
+// idTokenAlgorithms lists the signature algorithms accepted on ID tokens.
+var idTokenAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// discovery holds the fields of the OpenID Connect discovery document used by Cerberus.
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// idClaims holds the claims of an ID token, and of the userinfo endpoint.
+type idClaims struct {
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified flexBool `json:"email_verified"`
+	Name          string   `json:"name"`
+	jwt.RegisteredClaims
+}
+
+// flexBool decodes booleans sent either as JSON booleans or as strings, as some providers
+// do for "email_verified".
+type flexBool bool
+
+// oidcProvider is an OpenID Connect provider. Its endpoints and signing keys are discovered
+// from the issuer on first use, and cached.
+type oidcProvider struct {
+	cfg    *oauth_config.ProviderConfigData
+	client *http.Client
+
+	mu           sync.Mutex
+	meta         *discovery
+	discoveredAt time.Time
+	keys         *jwks.Set
+	keysAt       time.Time
+}
+
+// region Public
+
+// UnmarshalJSON accepts true, false, "true" and "false".
+func (b *flexBool) UnmarshalJSON(p_data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(p_data, &v); err != nil {
+		return err
+	}
+
+	switch t := v.(type) {
+	case bool:
+		*b = flexBool(t)
+	case string:
+		*b = t == "true"
+	default:
+		*b = false
+	}
+	return nil
+}
+
+// AuthCodeURL returns the authorization URL of the provider, with the nonce and the PKCE challenge.
+func (p *oidcProvider) AuthCodeURL(p_ctx context.Context, p_state string, p_nonce string, p_verifier string) (string, error) {
+	meta, err := p.discover(p_ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return p.config(meta).AuthCodeURL(p_state, oauth2.S256ChallengeOption(p_verifier),
+		oauth2.SetAuthURLParam("nonce", p_nonce)), nil
+}
+
+// Identify exchanges the authorization code and verifies the returned ID token: its signature,
+// issuer, audience, expiry and nonce. When the ID token holds no email address, it is read from
+// the userinfo endpoint.
+func (p *oidcProvider) Identify(p_ctx context.Context, p_code string, p_nonce string, p_verifier string) (*Identity, error) {
+	meta, err := p.discover(p_ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := p.config(meta)
+	tkn, err := exchange(p_ctx, p.client, conf, p_code, p_verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	rawID, _ := tkn.Extra("id_token").(string)
+	if rawID == "" {
+		return nil, fmt.Errorf("%w - missing from the token response", ErrInvalidIDToken)
+	}
+
+	claims, err := p.verifyIDToken(p_ctx, meta, rawID)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Nonce != p_nonce {
+		return nil, fmt.Errorf("%w - nonce mismatch", ErrInvalidIDToken)
+	}
+
+	idt := &Identity{
+		Subject:       claims.RegisteredClaims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}
+
+	if idt.Email == "" && meta.UserinfoEndpoint != "" {
+		var info idClaims
+		if err := getJSON(p_ctx, p.client, meta.UserinfoEndpoint, tkn.AccessToken, &info); err != nil {
+			return nil, err
+		}
+		if info.RegisteredClaims.Subject == idt.Subject {
+			idt.Email = info.Email
+			idt.EmailVerified = bool(info.EmailVerified)
+			if idt.Name == "" {
+				idt.Name = info.Name
+			}
+		}
+	}
+
+	return idt, nil
+}
+
+// endregion Public
+
+// region Private
+
+func newOIDCProvider(p_cfg *oauth_config.ProviderConfigData, p_client *http.Client) *oidcProvider {
+	return &oidcProvider{cfg: p_cfg, client: p_client}
+}
+
+// config builds the OAuth2 client configuration from the discovered endpoints.
+func (p *oidcProvider) config(p_meta *discovery) *oauth2.Config {
+	return oauth2Config(p.cfg, oauth2.Endpoint{
+		AuthURL:  p_meta.AuthorizationEndpoint,
+		TokenURL: p_meta.TokenEndpoint,
+	})
+}
+
+// discover returns the discovery document of the issuer, fetching it on first use and once a day.
+// The issuer of the document must match the configured one.
+func (p *oidcProvider) discover(p_ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.meta != nil && time.Since(p.discoveredAt) < discoveryMaxAge {
+		return p.meta, nil
+	}
+
+	var meta discovery
+	if err := getJSON(p_ctx, p.client, p.cfg.Issuer+discoveryPath, "", &meta); err != nil {
+		if p.meta != nil {
+			return p.meta, nil
+		}
+		return nil, err
+	}
+	if strings.TrimSuffix(meta.Issuer, "/") != p.cfg.Issuer {
+		return nil, fmt.Errorf("%w - discovery issuer %q does not match %q", ErrProviderFailed, meta.Issuer, p.cfg.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("%w - incomplete discovery document", ErrProviderFailed)
+	}
+
+	p.meta = &meta
+	p.discoveredAt = time.Now()
+	return p.meta, nil
+}
+
+// verifyIDToken checks the signature and the registered claims of an ID token.
+func (p *oidcProvider) verifyIDToken(p_ctx context.Context, p_meta *discovery, p_raw string) (*idClaims, error) {
+	claims := &idClaims{}
+
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(p_ctx, p_meta, kid)
+	}
+
+	_, err := jwt.ParseWithClaims(p_raw, claims, keyFunc,
+		jwt.WithValidMethods(idTokenAlgorithms),
+		jwt.WithIssuer(p_meta.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithLeeway(idTokenLeeway),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w - %s", ErrInvalidIDToken, err.Error())
+	}
+	if claims.RegisteredClaims.Subject == "" {
+		return nil, fmt.Errorf("%w - missing sub", ErrInvalidIDToken)
+	}
+
+	return claims, nil
+}
+
+// key returns the signing key with the given ID. The keys are refreshed when they are older
+// than keysTTL, or when the ID is unknown, which happens after a key rotation. Tokens without
+// a key ID are accepted when the provider publishes a single key.
+func (p *oidcProvider) key(p_ctx context.Context, p_meta *discovery, p_kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.keys == nil || time.Since(p.keysAt) > keysTTL {
+		if err := p.refreshKeys(p_ctx, p_meta); err != nil && p.keys == nil {
+			return nil, err
+		}
+	}
+
+	k := p.findKey(p_kid)
+	if k == nil && time.Since(p.keysAt) > minKeysRefresh {
+		if err := p.refreshKeys(p_ctx, p_meta); err != nil {
+			return nil, err
+		}
+		k = p.findKey(p_kid)
+	}
+	if k == nil {
+		return nil, fmt.Errorf("unknown key %q", p_kid)
+	}
+
+	return k.PublicKey()
+}
+
+// findKey looks up a key of the cached set.
+func (p *oidcProvider) findKey(p_kid string) *jwks.Key {
+	if p.keys == nil {
+		return nil
+	}
+	if p_kid == "" && len(p.keys.Keys) == 1 {
+		return &p.keys.Keys[0]
+	}
+	return p.keys.Find(p_kid)
+}
+
+// refreshKeys downloads the key set of the provider. On failure the previous keys are kept.
+func (p *oidcProvider) refreshKeys(p_ctx context.Context, p_meta *discovery) error {
+	p.keysAt = time.Now()
+
+	var set jwks.Set
+	if err := getJSON(p_ctx, p.client, p_meta.JWKSURI, "", &set); err != nil {
+		return err
+	}
+
+	p.keys = &set
+	return nil
+}
+
+// endregion Private
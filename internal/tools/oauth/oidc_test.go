@@ -0,0 +1,260 @@
+package oauth
+
+import (
+	oauth_config "cerberus/pkg/config/oauth"
+	"cerberus/pkg/jwks"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	fakeClientID    string = "cerberus"
+	fakeRedirectURL string = "https://auth.example.com/auth/oauth/fake/callback"
+)
+
+// fakeGrant is an authorization granted by the fake provider, waiting to be exchanged.
+type fakeGrant struct {
+	challenge string
+	nonce     string
+}
+
+// fakeOIDC is a local OpenID Connect provider. It grants every authorization request, checks
+// the PKCE verifier on the token endpoint, and signs ID tokens holding the claims of user.
+type fakeOIDC struct {
+	*httptest.Server
+	key  *ecdsa.PrivateKey
+	user map[string]interface{}
+
+	mu     sync.Mutex
+	grants map[string]fakeGrant
+}
+
+func newFakeOIDC(t *testing.T, p_user map[string]interface{}) *fakeOIDC {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeOIDC{key: key, user: p_user, grants: make(map[string]fakeGrant)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(discovery{
+			Issuer:                f.URL,
+			AuthorizationEndpoint: f.URL + "/authorize",
+			TokenEndpoint:         f.URL + "/token",
+			JWKSURI:               f.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		k, _ := jwks.FromPublicKey("k1", "ES256", &f.key.PublicKey)
+		json.NewEncoder(w).Encode(jwks.Set{Keys: []jwks.Key{k}})
+	})
+	mux.HandleFunc("GET /authorize", f.authorize)
+	mux.HandleFunc("POST /token", f.token)
+
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+// authorize grants the request and redirects to the callback with a code and the state.
+func (f *fakeOIDC) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != fakeClientID || q.Get("redirect_uri") != fakeRedirectURL ||
+		q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	code := "code-" + q.Get("state")
+	f.mu.Lock()
+	f.grants[code] = fakeGrant{challenge: q.Get("code_challenge"), nonce: q.Get("nonce")}
+	f.mu.Unlock()
+
+	callback, _ := url.Parse(fakeRedirectURL)
+	callback.RawQuery = url.Values{"code": {code}, "state": {q.Get("state")}}.Encode()
+	http.Redirect(w, r, callback.String(), http.StatusFound)
+}
+
+// token exchanges a code once, if the verifier matches the challenge of the authorization.
+func (f *fakeOIDC) token(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	grant, ok := f.grants[r.PostFormValue("code")]
+	delete(f.grants, r.PostFormValue("code"))
+	f.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if !ok || r.PostFormValue("grant_type") != "authorization_code" ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != grant.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   f.URL,
+		"aud":   fakeClientID,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": grant.nonce,
+	}
+	for k, v := range f.user {
+		claims[k] = v
+	}
+	tkn := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	tkn.Header["kid"] = "k1"
+	idToken, err := tkn.SignedString(f.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "at-" + grant.nonce,
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     idToken,
+	})
+}
+
+// login follows the authorization URL of a provider, as the browser would, and returns the
+// query of the callback.
+func (f *fakeOIDC) login(t *testing.T, p_provider Provider, p_state string, p_nonce string, p_verifier string) url.Values {
+	t.Helper()
+
+	authURL, err := p_provider.AuthCodeURL(context.Background(), p_state, p_nonce, p_verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	browser := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	res, err := browser.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusFound {
+		t.Fatalf("authorization request answered %d", res.StatusCode)
+	}
+
+	loc, err := url.Parse(res.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc.Query()
+}
+
+func newFakeProvider(f *fakeOIDC) Provider {
+	return newOIDCProvider(&oauth_config.ProviderConfigData{
+		Name:        "fake",
+		Kind:        oauth_config.KindOIDC,
+		ClientID:    fakeClientID,
+		Issuer:      f.URL,
+		RedirectURL: fakeRedirectURL,
+	}, f.Client())
+}
+
+func TestOIDCCallbackWithStateAndPKCE(t *testing.T) {
+	f := newFakeOIDC(t, map[string]interface{}{
+		"sub": "user-1", "email": "ada@example.com", "email_verified": true, "name": "Ada",
+	})
+	provider := newFakeProvider(f)
+
+	verifier := GenerateVerifier()
+	callback := f.login(t, provider, "state-1", "nonce-1", verifier)
+	if callback.Get("state") != "state-1" {
+		t.Fatalf("callback state = %q, want state-1", callback.Get("state"))
+	}
+
+	idt, err := provider.Identify(context.Background(), callback.Get("code"), "nonce-1", verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Identity{Subject: "user-1", Email: "ada@example.com", EmailVerified: true, Name: "Ada"}
+	if *idt != want {
+		t.Errorf("identity = %+v, want %+v", *idt, want)
+	}
+
+	// The code was consumed: the callback cannot be replayed.
+	if _, err := provider.Identify(context.Background(), callback.Get("code"), "nonce-1", verifier); !errors.Is(err, ErrExchangeFailed) {
+		t.Errorf("replayed code: err = %v, want %v", err, ErrExchangeFailed)
+	}
+}
+
+func TestOIDCCallbackRejectsWrongVerifier(t *testing.T) {
+	f := newFakeOIDC(t, map[string]interface{}{"sub": "user-1"})
+	provider := newFakeProvider(f)
+
+	callback := f.login(t, provider, "state-1", "nonce-1", GenerateVerifier())
+
+	_, err := provider.Identify(context.Background(), callback.Get("code"), "nonce-1", GenerateVerifier())
+	if !errors.Is(err, ErrExchangeFailed) {
+		t.Errorf("err = %v, want %v", err, ErrExchangeFailed)
+	}
+}
+
+func TestOIDCCallbackRejectsNonceOfAnotherLogin(t *testing.T) {
+	f := newFakeOIDC(t, map[string]interface{}{"sub": "user-1"})
+	provider := newFakeProvider(f)
+
+	verifier := GenerateVerifier()
+	callback := f.login(t, provider, "state-1", "nonce-1", verifier)
+
+	_, err := provider.Identify(context.Background(), callback.Get("code"), "nonce-2", verifier)
+	if !errors.Is(err, ErrInvalidIDToken) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidIDToken)
+	}
+}
+
+func TestOIDCEmailVerified(t *testing.T) {
+	tests := []struct {
+		name     string
+		verified interface{}
+		want     bool
+	}{
+		{"boolean true", true, true},
+		{"string true", "true", true},
+		{"boolean false", false, false},
+		{"string false", "false", false},
+		{"missing", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := map[string]interface{}{"sub": "user-1", "email": "ada@example.com"}
+			if tt.verified != nil {
+				user["email_verified"] = tt.verified
+			}
+			f := newFakeOIDC(t, user)
+			provider := newFakeProvider(f)
+
+			verifier := GenerateVerifier()
+			callback := f.login(t, provider, "state-1", "nonce-1", verifier)
+			idt, err := provider.Identify(context.Background(), callback.Get("code"), "nonce-1", verifier)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if idt.EmailVerified != tt.want {
+				t.Errorf("EmailVerified = %v, want %v", idt.EmailVerified, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package otp
+
+import (
+	"cerberus/internal/tools/logger"
+	"cerberus/internal/tools/mailer"
+	"cerberus/pkg/config"
+	"fmt"
+	"time"
+)
+
+const (
+	ChannelEmail string = "email" // ChannelEmail delivers codes to the email address of the account.
+	ChannelSMS   string = "sms"   // ChannelSMS delivers codes to the verified phone number of the account.
+
+	smsProviderFake string = "fake" // smsProviderFake selects the FakeSMSProvider.
+)
+
+// Sender is implemented by every channel delivering one-time codes.
+type Sender interface {
+	// Send delivers a code to a single recipient of the channel.
+	Send(p_to string, p_code string, p_ttl time.Duration) error
+}
+
+// SMSProvider is implemented by every SMS gateway.
+type SMSProvider interface {
+	// SendSMS delivers a text message to a phone number in E.164 format.
+	SendSMS(p_to string, p_body string) error
+}
+
+// EmailSender delivers codes by email through a Mailer.
+type EmailSender struct {
+	Mailer mailer.Mailer
+}
+
+// SMSSender delivers codes by text message through an SMSProvider.
+type SMSSender struct {
+	Provider SMSProvider
+}
+
+// FakeSMSProvider writes text messages to the application log instead of sending them.
+// It is meant for development and tests, never for production.
+type FakeSMSProvider struct{}
+
+// region Public
+
+// NewSenders creates the Senders of the configured channels. Email is always available; SMS
+// is only available when an SMS provider is configured.
+//
+// Parameters:
+//   - p_cfg: A pointer to the ConfigData structure containing the SMS configuration.
+//   - p_mailer: The Mailer delivering emails.
+//
+// Returns:
+//   - map[string]Sender: The senders, keyed by channel (ChannelEmail, ChannelSMS).
+func NewSenders(p_cfg *config.ConfigData, p_mailer mailer.Mailer) map[string]Sender {
+	senders := map[string]Sender{ChannelEmail: &EmailSender{Mailer: p_mailer}}
+	if provider := NewSMSProvider(p_cfg); provider != nil {
+		senders[ChannelSMS] = &SMSSender{Provider: provider}
+	}
+	return senders
+}
+
+// NewSMSProvider creates the SMSProvider selected by SMS_PROVIDER.
+//
+// Parameters:
+//   - p_cfg: A pointer to the ConfigData structure containing the SMS configuration.
+//
+// Returns:
+//   - SMSProvider: The provider, or nil if SMS is disabled or the provider is unknown.
+func NewSMSProvider(p_cfg *config.ConfigData) SMSProvider {
+	switch p_cfg.SMSProvider {
+	case "":
+		return nil
+	case smsProviderFake:
+		logger.Log("📵 Fake SMS provider configured, text messages will be logged", logger.WARN)
+		return &FakeSMSProvider{}
+	default:
+		logger.Log("Unknown SMS provider "+p_cfg.SMSProvider+", SMS disabled", logger.ERROR)
+		return nil
+	}
+}
+
+// Send emails the code to the address.
+//
+// Parameters:
+//   - p_to: The recipient email address.
+//   - p_code: The one-time code.
+//   - p_ttl: How long the code stays valid, mentioned in the message.
+//
+// Returns:
+//   - error: An error if the email could not be sent, nil otherwise.
+func (s *EmailSender) Send(p_to string, p_code string, p_ttl time.Duration) error {
+	body := fmt.Sprintf("Your verification code is %s\n\nIt expires in %s. If you did not request it, "+
+		"ignore this email and never share the code.", p_code, p_ttl)
+	return s.Mailer.Send(p_to, "Your verification code", body)
+}
+
+// Send texts the code to the phone number.
+//
+// Parameters:
+//   - p_to: The recipient phone number, in E.164 format.
+//   - p_code: The one-time code.
+//   - p_ttl: How long the code stays valid, mentioned in the message.
+//
+// Returns:
+//   - error: An error if the message could not be sent, nil otherwise.
+func (s *SMSSender) Send(p_to string, p_code string, p_ttl time.Duration) error {
+	return s.Provider.SendSMS(p_to, fmt.Sprintf("Your verification code is %s. It expires in %s.", p_code, p_ttl))
+}
+
+// SendSMS writes the message to the log at INFO level and never fails.
+func (p *FakeSMSProvider) SendSMS(p_to string, p_body string) error {
+	logger.Log(fmt.Sprintf("📱 SMS to %s - %s", p_to, p_body), logger.INFO)
+	return nil
+}
+
+// endregion Public
@@ -0,0 +1,40 @@
+package pagination
+
+import (
+	"net/http"
+	"strconv"
+)
+
+const (
+	DefaultPageSize int = 20  // DefaultPageSize is used when the request does not specify a page size.
+	MaxPageSize     int = 100 // MaxPageSize is the largest page size a client can request.
+)
+
+// region Public
+
+// FromRequest reads the "page" and "page_size" query parameters of a request.
+//
+// Missing or invalid values fall back to the first page and DefaultPageSize, and the page
+// size is capped at MaxPageSize.
+//
+// Parameters:
+//   - r: The incoming *http.Request.
+//
+// Returns:
+//   - int: The 1-based page number.
+//   - int: The page size.
+func FromRequest(r *http.Request) (int, int) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	size, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || size < 1 {
+		size = DefaultPageSize
+	}
+
+	return page, min(size, MaxPageSize)
+}
+
+// endregion Public
@@ -0,0 +1,88 @@
+package random
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"strings"
+)
+
+// region Public
+
+// Token generates a cryptographically secure, URL-safe random token.
+//
+// Parameters:
+//   - p_size: The number of random bytes used to build the token.
+//
+// Returns:
+//   - string: The token encoded with unpadded base64url.
+//   - error: An error if the random source fails, nil otherwise.
+func Token(p_size int) (string, error) {
+	bytes := make([]byte, p_size)
+
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// Hex generates a cryptographically secure random string of lowercase hexadecimal digits.
+//
+// Parameters:
+//   - p_size: The number of random bytes, encoded with two digits each.
+//
+// Returns:
+//   - string: The hex encoded bytes.
+//   - error: An error if the random source fails, nil otherwise.
+func Hex(p_size int) (string, error) {
+	bytes := make([]byte, p_size)
+
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(bytes), nil
+}
+
+// Digits generates a cryptographically secure numeric code, such as a one-time password.
+// Every digit is drawn uniformly, so codes may start with zeros.
+//
+// Parameters:
+//   - p_count: The number of digits of the code.
+//
+// Returns:
+//   - string: The code.
+//   - error: An error if the random source fails, nil otherwise.
+func Digits(p_count int) (string, error) {
+	return Chars(p_count, "0123456789")
+}
+
+// Chars generates a cryptographically secure string of characters drawn uniformly from an
+// alphabet, such as a code typed by users.
+//
+// Parameters:
+//   - p_count: The number of characters of the string.
+//   - p_alphabet: The ASCII characters to draw from.
+//
+// Returns:
+//   - string: The random string.
+//   - error: An error if the random source fails, nil otherwise.
+func Chars(p_count int, p_alphabet string) (string, error) {
+	var sb strings.Builder
+	sb.Grow(p_count)
+
+	size := big.NewInt(int64(len(p_alphabet)))
+	for i := 0; i < p_count; i++ {
+		n, err := rand.Int(rand.Reader, size)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(p_alphabet[n.Int64()])
+	}
+
+	return sb.String(), nil
+}
+
+// endregion Public
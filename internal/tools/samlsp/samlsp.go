@@ -0,0 +1,427 @@
+package samlsp
+
+import (
+	"cerberus/internal/tools/logger"
+	saml_config "cerberus/pkg/config/saml"
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crewjam/saml"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+const (
+	httpTimeout      time.Duration = 10 * time.Second // httpTimeout bounds every call made to an identity provider.
+	metadataMaxAge   time.Duration = 24 * time.Hour   // metadataMaxAge is how long the metadata of an identity provider is cached.
+	maxMetadataBytes int64         = 1 << 20          // maxMetadataBytes bounds the metadata read from an identity provider.
+)
+
+var (
+	ErrInvalidResponse = errors.New("invalid SAML response")              // ErrInvalidResponse is returned when a response or its assertion cannot be trusted.
+	ErrProviderFailed  = errors.New("SAML identity provider unavailable") // ErrProviderFailed is returned when the metadata of the provider cannot be loaded.
+)
+
+// emailAttributes and nameAttributes list the attributes commonly holding the email address
+// and the display name, tried when no attribute is configured.
+var (
+	emailAttributes = []string{"email", "mail", "emailAddress", "urn:oid:0.9.2342.19200300.100.1.3",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress"}
+	nameAttributes = []string{"displayName", "name", "cn", "urn:oid:2.16.840.1.113730.3.1.241",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/name"}
+)
+
+// Identity describes a user authenticated by a SAML identity provider.
+//
+// Fields:
+//   - Subject: The NameID of the user, stable across logins.
+//   - Email: The email address of the user, empty if the provider did not assert it.
+//   - Name: The display name of the user.
+//   - Attributes: The profile attributes mapped from the assertion attributes.
+type Identity struct {
+	Subject    string
+	Email      string
+	Name       string
+	Attributes map[string]string
+}
+
+// Provider is a SAML 2.0 identity provider Cerberus is a service provider of. The metadata of
+// the identity provider is loaded on first use, and cached.
+type Provider struct {
+	cfg    *saml_config.ProviderConfigData
+	base   saml.ServiceProvider
+	client *http.Client
+
+	mu        sync.Mutex
+	sp        *saml.ServiceProvider
+	fetchedAt time.Time
+}
+
+// region Public
+
+// NewProviders creates the configured SAML identity providers. Providers without metadata or
+// without email domains are skipped with a warning.
+//
+// Cerberus is published under "<SAML_BASE_URL>/auth/saml/<name>/metadata" and receives the
+// assertions on "<SAML_BASE_URL>/auth/saml/<name>/acs". When a key is configured, authentication
+// requests are signed and encrypted assertions are accepted.
+//
+// Parameters:
+//   - p_cfg: A pointer to the SAMLConfigData structure holding the providers.
+//
+// Returns:
+//   - map[string]*Provider: The providers, by name.
+//   - error: An error if the configured key or certificate cannot be loaded, nil otherwise.
+func NewProviders(p_cfg *saml_config.SAMLConfigData) (map[string]*Provider, error) {
+	providers := make(map[string]*Provider)
+	if len(p_cfg.Providers) == 0 {
+		return providers, nil
+	}
+
+	key, cert, err := loadKeyPair(p_cfg.KeyFile, p_cfg.CertFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SAML key - %w", err)
+	}
+	client := &http.Client{Timeout: httpTimeout}
+
+	for name, pCfg := range p_cfg.Providers {
+		if pCfg.MetadataURL == "" && pCfg.MetadataFile == "" {
+			logger.Log(fmt.Sprintf("SAML provider %q skipped, metadata missing", name), logger.WARN)
+			continue
+		}
+		if len(pCfg.EmailDomains) == 0 {
+			logger.Log(fmt.Sprintf("SAML provider %q skipped, email domains missing", name), logger.WARN)
+			continue
+		}
+
+		base := fmt.Sprintf("%s/auth/saml/%s", p_cfg.GetBaseURL(), name)
+		metadataURL, err := url.Parse(base + "/metadata")
+		if err != nil {
+			logger.Log(fmt.Sprintf("SAML provider %q skipped, invalid base URL - %s", name, err.Error()), logger.WARN)
+			continue
+		}
+		acsURL, _ := url.Parse(base + "/acs")
+
+		p := &Provider{cfg: pCfg, client: client}
+		p.base = saml.ServiceProvider{
+			EntityID:          pCfg.EntityID,
+			Key:               key,
+			Certificate:       cert,
+			HTTPClient:        client,
+			MetadataURL:       *metadataURL,
+			AcsURL:            *acsURL,
+			AuthnNameIDFormat: nameIDFormat(pCfg.NameIDFormat),
+		}
+		if key != nil {
+			p.base.SignatureMethod = dsig.RSASHA256SignatureMethod
+		}
+
+		providers[name] = p
+		logger.Log(fmt.Sprintf("SAML provider added: %s", name), logger.INFO)
+	}
+
+	return providers, nil
+}
+
+// Metadata returns the metadata of Cerberus as a service provider of this identity provider,
+// to be registered with it.
+//
+// Returns:
+//   - []byte: The XML metadata.
+//   - error: An error if the metadata cannot be encoded, nil otherwise.
+func (p *Provider) Metadata() ([]byte, error) {
+	data, err := xml.MarshalIndent(p.base.Metadata(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// AuthnRequestURL creates an authentication request and returns the URL of the identity
+// provider it is sent to, with the HTTP-Redirect binding.
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_relayState: The opaque value sent back to the ACS with the response.
+//
+// Returns:
+//   - string: The URL the browser must be redirected to.
+//   - string: The ID of the request, which the response must be in response to.
+//   - error: An error wrapping ErrProviderFailed, or an error if the request cannot be built.
+func (p *Provider) AuthnRequestURL(p_ctx context.Context, p_relayState string) (string, string, error) {
+	sp, err := p.serviceProvider(p_ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	location := sp.GetSSOBindingLocation(saml.HTTPRedirectBinding)
+	if location == "" {
+		return "", "", fmt.Errorf("%w - no HTTP-Redirect single sign-on service", ErrProviderFailed)
+	}
+
+	req, err := sp.MakeAuthenticationRequest(location, saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+	if err != nil {
+		return "", "", err
+	}
+	redirect, err := req.Redirect(p_relayState, sp)
+	if err != nil {
+		return "", "", err
+	}
+
+	return redirect.String(), req.ID, nil
+}
+
+// ParseResponse validates the response posted to the ACS, and returns the authenticated user.
+//
+// The response, or its assertions, must be signed by the identity provider. The issuer,
+// destination, recipient, audience, validity period and "InResponseTo" are checked. Transient
+// NameIDs are refused, as they cannot identify the user across logins.
+//
+// Parameters:
+//   - r: The request posted to the ACS, with its form parsed.
+//   - p_requestID: The ID of the pending authentication request.
+//
+// Returns:
+//   - *Identity: The authenticated user.
+//   - error: An error wrapping ErrInvalidResponse or ErrProviderFailed.
+func (p *Provider) ParseResponse(r *http.Request, p_requestID string) (*Identity, error) {
+	sp, err := p.serviceProvider(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	assertion, err := sp.ParseResponse(r, []string{p_requestID})
+	if err != nil {
+		var invalid *saml.InvalidResponseError
+		if errors.As(err, &invalid) && invalid.PrivateErr != nil {
+			err = invalid.PrivateErr
+		}
+		return nil, fmt.Errorf("%w - %s", ErrInvalidResponse, err.Error())
+	}
+
+	if assertion.Subject == nil || assertion.Subject.NameID == nil || assertion.Subject.NameID.Value == "" {
+		return nil, fmt.Errorf("%w - missing NameID", ErrInvalidResponse)
+	}
+	nameID := assertion.Subject.NameID
+	if nameID.Format == string(saml.TransientNameIDFormat) {
+		return nil, fmt.Errorf("%w - transient NameID", ErrInvalidResponse)
+	}
+
+	attrs := attributes(assertion)
+	idt := &Identity{
+		Subject:    nameID.Value,
+		Email:      pick(attrs, p.cfg.AttrEmail, emailAttributes),
+		Name:       pick(attrs, p.cfg.AttrName, nameAttributes),
+		Attributes: make(map[string]string),
+	}
+	if idt.Email == "" && nameID.Format == string(saml.EmailAddressNameIDFormat) {
+		idt.Email = nameID.Value
+	}
+	for key, attr := range p.cfg.Attributes {
+		if v := attrs[attr]; v != "" {
+			idt.Attributes[key] = v
+		}
+	}
+
+	return idt, nil
+}
+
+// endregion Public
+
+// region Private
+
+// serviceProvider returns the service provider with the metadata of the identity provider,
+// loading it on first use and once a day. On failure the previous metadata is kept.
+func (p *Provider) serviceProvider(p_ctx context.Context) (*saml.ServiceProvider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.sp != nil && time.Since(p.fetchedAt) < metadataMaxAge {
+		return p.sp, nil
+	}
+
+	meta, err := p.loadMetadata(p_ctx)
+	if err != nil {
+		if p.sp != nil {
+			return p.sp, nil
+		}
+		return nil, fmt.Errorf("%w - %s", ErrProviderFailed, err.Error())
+	}
+
+	sp := p.base
+	sp.IDPMetadata = meta
+	p.sp = &sp
+	p.fetchedAt = time.Now()
+	return p.sp, nil
+}
+
+// loadMetadata reads the metadata of the identity provider from its file or URL.
+func (p *Provider) loadMetadata(p_ctx context.Context) (*saml.EntityDescriptor, error) {
+	var data []byte
+	var err error
+
+	if p.cfg.MetadataFile != "" {
+		data, err = os.ReadFile(p.cfg.MetadataFile)
+	} else {
+		data, err = p.fetch(p_ctx, p.cfg.MetadataURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMetadata(data)
+}
+
+// fetch downloads a document of the identity provider.
+func (p *Provider) fetch(p_ctx context.Context, p_url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(p_ctx, http.MethodGet, p_url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s answered %d", p_url, res.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(res.Body, maxMetadataBytes))
+}
+
+// parseMetadata decodes the metadata of an identity provider, published either as a single
+// entity or as the first identity provider of a group of entities.
+func parseMetadata(p_data []byte) (*saml.EntityDescriptor, error) {
+	var entity saml.EntityDescriptor
+	if err := xml.Unmarshal(p_data, &entity); err == nil {
+		if len(entity.IDPSSODescriptors) == 0 {
+			return nil, errors.New("metadata without identity provider descriptor")
+		}
+		return &entity, nil
+	}
+
+	var entities saml.EntitiesDescriptor
+	if err := xml.Unmarshal(p_data, &entities); err != nil {
+		return nil, fmt.Errorf("invalid metadata - %s", err.Error())
+	}
+	for i := range entities.EntityDescriptors {
+		if len(entities.EntityDescriptors[i].IDPSSODescriptors) > 0 {
+			return &entities.EntityDescriptors[i], nil
+		}
+	}
+	return nil, errors.New("metadata without identity provider descriptor")
+}
+
+// loadKeyPair reads the PEM RSA key and certificate of the service provider. Both are nil when
+// no key is configured.
+func loadKeyPair(p_keyFile string, p_certFile string) (*rsa.PrivateKey, *x509.Certificate, error) {
+	if p_keyFile == "" && p_certFile == "" {
+		return nil, nil, nil
+	}
+	if p_keyFile == "" || p_certFile == "" {
+		return nil, nil, errors.New("SAML_KEY_FILE and SAML_CERT_FILE must be set together")
+	}
+
+	block, err := readPEM(p_keyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	var parsed interface{}
+	if block.Type == "RSA PRIVATE KEY" {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, nil, errors.New("the SAML key must be an RSA key")
+	}
+
+	block, err = readPEM(p_certFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, cert, nil
+}
+
+// readPEM reads the first PEM block of a file.
+func readPEM(p_path string) (*pem.Block, error) {
+	data, err := os.ReadFile(p_path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in %s", p_path)
+	}
+	return block, nil
+}
+
+// nameIDFormat returns the NameID format requested for a configured value.
+func nameIDFormat(p_value string) saml.NameIDFormat {
+	switch p_value {
+	case "email":
+		return saml.EmailAddressNameIDFormat
+	case "unspecified":
+		return saml.UnspecifiedNameIDFormat
+	default:
+		return saml.PersistentNameIDFormat
+	}
+}
+
+// attributes returns the first value of the assertion attributes, by name and by friendly name.
+func attributes(p_assertion *saml.Assertion) map[string]string {
+	attrs := make(map[string]string)
+	for _, stmt := range p_assertion.AttributeStatements {
+		for _, attr := range stmt.Attributes {
+			if len(attr.Values) == 0 {
+				continue
+			}
+			value := strings.TrimSpace(attr.Values[0].Value)
+			if _, ok := attrs[attr.Name]; !ok {
+				attrs[attr.Name] = value
+			}
+			if _, ok := attrs[attr.FriendlyName]; attr.FriendlyName != "" && !ok {
+				attrs[attr.FriendlyName] = value
+			}
+		}
+	}
+	return attrs
+}
+
+// pick returns the configured attribute, or the first of the common attributes found.
+func pick(p_attrs map[string]string, p_configured string, p_common []string) string {
+	if p_configured != "" {
+		return p_attrs[p_configured]
+	}
+	for _, name := range p_common {
+		if v := p_attrs[name]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// endregion Private
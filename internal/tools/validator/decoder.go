@@ -0,0 +1,105 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrorResponse represents the JSON body returned when a request fails decoding or validation.
+//
+// Fields:
+//   - Message: A summary of the failure.
+//   - Errors: The per-field validation failures, omitted when the failure is not field related.
+type ErrorResponse struct {
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+// ErrBodyTooLarge is returned by DecodeJSONBody when the body exceeds the allowed size.
+var ErrBodyTooLarge = errors.New("request body too large")
+
+// region Public
+
+// DecodeJSONBody decodes the request body into p_dst and validates the result.
+//
+// The body is limited to p_maxBytes, unknown JSON fields are rejected and trailing data
+// after the JSON object is treated as an error. Once decoded, the struct is checked with Validate.
+//
+// Parameters:
+//   - w: The http.ResponseWriter, required by http.MaxBytesReader.
+//   - r: The incoming *http.Request whose body is decoded.
+//   - p_dst: A pointer to the DTO struct that receives the decoded body.
+//   - p_maxBytes: The maximum accepted body size in bytes.
+//
+// Returns:
+//   - error: ErrBodyTooLarge, a decode error, a ValidationErrors value, or nil if successful.
+func DecodeJSONBody(w http.ResponseWriter, r *http.Request, p_dst interface{}, p_maxBytes int64) error {
+	r.Body = http.MaxBytesReader(w, r.Body, p_maxBytes)
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(p_dst); err != nil {
+		var maxErr *http.MaxBytesError
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+
+		switch {
+		case errors.As(err, &maxErr):
+			return ErrBodyTooLarge
+
+		case errors.Is(err, io.EOF):
+			return errors.New("request body must not be empty")
+
+		case errors.As(err, &syntaxErr):
+			return fmt.Errorf("malformed JSON at position %d", syntaxErr.Offset)
+
+		case errors.As(err, &typeErr):
+			return ValidationErrors{{Field: typeErr.Field, Rule: "type",
+				Message: fmt.Sprintf("%s must be of type %s", typeErr.Field, typeErr.Type)}}
+
+		case strings.HasPrefix(err.Error(), "json: unknown field "):
+			field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+			return ValidationErrors{{Field: field, Rule: "unknown", Message: "unknown field " + field}}
+
+		default:
+			return err
+		}
+	}
+
+	if dec.More() {
+		return errors.New("request body must contain a single JSON object")
+	}
+
+	return Validate(p_dst)
+}
+
+// WriteError writes the JSON error response matching an error returned by DecodeJSONBody.
+//
+// Parameters:
+//   - w: The http.ResponseWriter used to send the response.
+//   - p_err: The error returned by DecodeJSONBody.
+//
+// The status code is 413 for oversized bodies and 400 for any other failure.
+func WriteError(w http.ResponseWriter, p_err error) {
+	status := http.StatusBadRequest
+	res := ErrorResponse{Message: p_err.Error()}
+
+	var vErrs ValidationErrors
+	if errors.Is(p_err, ErrBodyTooLarge) {
+		status = http.StatusRequestEntityTooLarge
+	} else if errors.As(p_err, &vErrs) {
+		res.Message = "Validation failed"
+		res.Errors = vErrs
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
+
+// endregion Public
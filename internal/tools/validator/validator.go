@@ -0,0 +1,209 @@
+package validator
+
+import (
+	"cerberus/internal/tools/email"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError describes a single validation failure for a DTO field.
+//
+// Fields:
+//   - Field: The JSON name of the field that failed validation.
+//   - Rule: The validation rule that was violated (e.g., "required", "email", "max").
+//   - Message: A human readable description of the failure.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// e164Pattern matches phone numbers in the E.164 international format.
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// ValidationErrors is a collection of FieldError values returned when a struct fails validation.
+// It implements the error interface so it can be returned and inspected with errors.As.
+type ValidationErrors []FieldError
+
+// Error returns a single line summary of all the field errors.
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(v))
+	for _, fe := range v {
+		msgs = append(msgs, fe.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// region Public
+
+// Validate checks the fields of the provided struct against the rules declared in their
+// `validate` struct tags.
+//
+// Supported rules (comma separated):
+//   - required: The field must not be empty (strings are trimmed before the check).
+//   - email: The field must be a bare RFC 5322 address (no display name) once normalized by
+//     email.Normalize. Surrounding whitespace is ignored by every rule of the field.
+//   - url: The field must be an absolute http or https URL.
+//   - numeric: The string must only contain ASCII digits.
+//   - e164: The string must be a phone number in E.164 format (e.g., +14155550100).
+//   - min=N: The string must contain at least N characters.
+//   - max=N: The string must contain at most N characters.
+//   - maxbytes=N: The string must be at most N bytes long once UTF-8 encoded, such as
+//     passwords, which bcrypt truncates after 72 bytes.
+//   - oneof=a b c: The string must be one of the space separated values.
+//
+// Empty optional fields (without "required") skip every other rule.
+//
+// Parameters:
+//   - p_dto: A pointer to the struct to validate.
+//
+// Returns:
+//   - error: A ValidationErrors value if any rule fails, nil otherwise.
+func Validate(p_dto interface{}) error {
+	v := reflect.ValueOf(p_dto)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ValidationErrors{{Field: "body", Rule: "required", Message: "body is required"}}
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs ValidationErrors
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("validate")
+		if tag == "" || tag == "-" || !sf.IsExported() {
+			continue
+		}
+
+		if fe := validateField(jsonName(sf), v.Field(i), tag); fe != nil {
+			errs = append(errs, *fe)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// endregion Public
+// region Private
+
+// validateField applies the rules of a single tag to a field value and returns the first failure.
+func validateField(p_name string, p_value reflect.Value, p_tag string) *FieldError {
+	for p_value.Kind() == reflect.Pointer {
+		if p_value.IsNil() {
+			if strings.Contains(p_tag, "required") {
+				return &FieldError{Field: p_name, Rule: "required", Message: p_name + " is required"}
+			}
+			return nil
+		}
+		p_value = p_value.Elem()
+	}
+
+	if p_value.Kind() != reflect.String {
+		return nil
+	}
+	str := p_value.String()
+
+	rules := strings.Split(p_tag, ",")
+	if slices.Contains(rules, "email") {
+		// Addresses are trimmed by email.Normalize before use, so padding is accepted and does
+		// not count towards the length rules.
+		str = strings.TrimSpace(str)
+	}
+	if strings.TrimSpace(str) == "" {
+		for _, r := range rules {
+			if r == "required" {
+				return &FieldError{Field: p_name, Rule: "required", Message: p_name + " is required"}
+			}
+		}
+		return nil
+	}
+
+	for _, rule := range rules {
+		name, arg, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "email":
+			canonical, err := email.Normalize(str, false)
+			if err != nil {
+				return &FieldError{Field: p_name, Rule: name, Message: p_name + " must be a valid email address"}
+			}
+			addr, err := mail.ParseAddress(canonical)
+			if err != nil || addr.Address != canonical {
+				return &FieldError{Field: p_name, Rule: name, Message: p_name + " must be a valid email address"}
+			}
+
+		case "url":
+			u, err := url.Parse(strings.TrimSpace(str))
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return &FieldError{Field: p_name, Rule: name, Message: p_name + " must be an absolute http(s) URL"}
+			}
+
+		case "numeric":
+			if strings.Trim(str, "0123456789") != "" {
+				return &FieldError{Field: p_name, Rule: name, Message: p_name + " must only contain digits"}
+			}
+
+		case "e164":
+			if !e164Pattern.MatchString(str) {
+				return &FieldError{Field: p_name, Rule: name,
+					Message: p_name + " must be an international phone number, such as +14155550100"}
+			}
+
+		case "min":
+			n, _ := strconv.Atoi(arg)
+			if utf8.RuneCountInString(str) < n {
+				return &FieldError{Field: p_name, Rule: name,
+					Message: fmt.Sprintf("%s must be at least %d characters", p_name, n)}
+			}
+
+		case "max":
+			n, _ := strconv.Atoi(arg)
+			if utf8.RuneCountInString(str) > n {
+				return &FieldError{Field: p_name, Rule: name,
+					Message: fmt.Sprintf("%s must be at most %d characters", p_name, n)}
+			}
+
+		case "maxbytes":
+			n, _ := strconv.Atoi(arg)
+			if len(str) > n {
+				return &FieldError{Field: p_name, Rule: name,
+					Message: fmt.Sprintf("%s must be at most %d bytes", p_name, n)}
+			}
+
+		case "oneof":
+			if !slices.Contains(strings.Fields(arg), str) {
+				return &FieldError{Field: p_name, Rule: name,
+					Message: fmt.Sprintf("%s must be one of [%s]", p_name, arg)}
+			}
+		}
+	}
+
+	return nil
+}
+
+// jsonName returns the JSON key of a struct field, falling back to the Go field name.
+func jsonName(p_field reflect.StructField) string {
+	name, _, _ := strings.Cut(p_field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return p_field.Name
+	}
+	return name
+}
+
+// endregion Private
@@ -0,0 +1,43 @@
+package validator
+
+import (
+	"cerberus/internal/tools/email"
+	"strings"
+	"testing"
+)
+
+type emailDTO struct {
+	Email string `validate:"required,email,max=254"`
+}
+
+func TestEmailRuleAcceptsWhatNormalizeAccepts(t *testing.T) {
+	for _, addr := range []string{
+		"jane@example.com",
+		"  jane@example.com\t",
+		"Jane@Example.COM.",
+		"jane@bücher.de",
+		" " + strings.Repeat("a", 64) + "@" + strings.Repeat("b", 180) + ".com ",
+	} {
+		if _, err := email.Normalize(addr, false); err != nil {
+			t.Fatalf("%q: normalize failed - %v", addr, err)
+		}
+		if err := Validate(&emailDTO{Email: addr}); err != nil {
+			t.Fatalf("%q: unexpected validation error %v", addr, err)
+		}
+	}
+}
+
+func TestEmailRuleRejectsInvalidAddresses(t *testing.T) {
+	for _, addr := range []string{
+		"   ",
+		"jane",
+		"jane@",
+		"@example.com",
+		"Jane <jane@example.com>",
+		"jane@exa mple.com",
+	} {
+		if err := Validate(&emailDTO{Email: addr}); err == nil {
+			t.Fatalf("%q: expected a validation error", addr)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	HeaderEvent     string = "X-Cerberus-Event"     // HeaderEvent carries the type of the delivered event.
+	HeaderDelivery  string = "X-Cerberus-Delivery"  // HeaderDelivery carries the unique ID of the delivery.
+	HeaderTimestamp string = "X-Cerberus-Timestamp" // HeaderTimestamp carries the Unix time the delivery was signed at.
+	HeaderSignature string = "X-Cerberus-Signature" // HeaderSignature carries the "sha256=" prefixed HMAC of the delivery.
+
+	signaturePrefix string = "sha256="
+)
+
+// ErrInvalidSignature is returned by Verify when a delivery was not signed with the expected secret.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Request describes a single delivery attempt.
+//
+// Fields:
+//   - URL: The endpoint receiving the delivery.
+//   - Secret: The key used to sign the body.
+//   - Event: The type of the delivered event.
+//   - DeliveryID: The unique ID of the delivery, identical across retries.
+//   - Body: The JSON payload.
+type Request struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID string
+	Body       []byte
+}
+
+// region Public
+
+// Sign computes the signature of a delivery body.
+//
+// The signed message is the timestamp and the body joined by a dot, so a captured
+// delivery cannot be replayed later with a different timestamp.
+//
+// Parameters:
+//   - p_secret: The key shared with the receiver.
+//   - p_timestamp: The Unix time sent in the HeaderTimestamp header.
+//   - p_body: The raw request body.
+//
+// Returns:
+//   - string: The value of the HeaderSignature header.
+func Sign(p_secret string, p_timestamp int64, p_body []byte) string {
+	mac := hmac.New(sha256.New, []byte(p_secret))
+	mac.Write([]byte(strconv.FormatInt(p_timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(p_body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a received delivery. Receivers written in Go can use it
+// directly; others must reproduce Sign.
+//
+// Parameters:
+//   - p_secret: The key shared with the sender.
+//   - p_header: The headers of the received request.
+//   - p_body: The raw request body.
+//   - p_tolerance: How old the signature may be, 0 to skip the check.
+//
+// Returns:
+//   - error: ErrInvalidSignature if the signature does not match or is too old, nil otherwise.
+func Verify(p_secret string, p_header http.Header, p_body []byte, p_tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(p_header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if p_tolerance > 0 && time.Since(time.Unix(ts, 0)).Abs() > p_tolerance {
+		return ErrInvalidSignature
+	}
+
+	expected := Sign(p_secret, ts, p_body)
+	if !hmac.Equal([]byte(expected), []byte(p_header.Get(HeaderSignature))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Send performs one signed delivery attempt. Any 2xx response is a success.
+//
+// Parameters:
+//   - p_ctx: The context bounding the attempt.
+//   - p_client: The HTTP client used to send the request.
+//   - p_req: The delivery to send.
+//
+// Returns:
+//   - int: The HTTP status of the response, 0 if none was received.
+//   - error: An error if the request failed or the receiver did not answer with a 2xx status.
+func Send(p_ctx context.Context, p_client *http.Client, p_req *Request) (int, error) {
+	req, err := http.NewRequestWithContext(p_ctx, http.MethodPost, p_req.URL, bytes.NewReader(p_req.Body))
+	if err != nil {
+		return 0, err
+	}
+
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Cerberus-Webhook/1.0")
+	req.Header.Set(HeaderEvent, p_req.Event)
+	req.Header.Set(HeaderDelivery, p_req.DeliveryID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(p_req.Secret, ts, p_req.Body))
+
+	res, err := p_client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("receiver answered with status %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
+
+// endregion Public
@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// The administration methods require the logged in user to hold the admin role; the server
+// answers ErrForbidden otherwise.
+
+// region Public
+
+// ListUsers returns a page of users (GET /admin/users).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_opts: The page and the search filter.
+//
+// Returns:
+//   - *ListUsersResponse: A page of users.
+//   - error: ErrSessionExpired if the session cannot be refreshed, or an *APIError.
+func (c *Client) ListUsers(p_ctx context.Context, p_opts ListUsersOptions) (*ListUsersResponse, error) {
+	query := p_opts.Pagination.values()
+	if p_opts.Query != "" {
+		query.Set("q", p_opts.Query)
+	}
+
+	var res ListUsersResponse
+	if err := c.do(p_ctx, http.MethodGet, "/admin/users", query, nil, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetUser returns a user (GET /admin/users/{id}).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_usrId: The ID of the user.
+//
+// Returns:
+//   - *UserSummary: The user.
+//   - error: An *APIError matching ErrNotFound if the user does not exist.
+func (c *Client) GetUser(p_ctx context.Context, p_usrId string) (*UserSummary, error) {
+	var res UserSummary
+	if err := c.do(p_ctx, http.MethodGet, userPath(p_usrId, ""), nil, nil, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteUser soft-deletes a user and revokes their sessions (DELETE /admin/users/{id}).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_usrId: The ID of the user.
+//
+// Returns:
+//   - *AdminDeleteUserResponse: When the account data will be permanently removed.
+//   - error: An *APIError matching ErrNotFound if the user does not exist.
+func (c *Client) DeleteUser(p_ctx context.Context, p_usrId string) (*AdminDeleteUserResponse, error) {
+	var res AdminDeleteUserResponse
+	if err := c.do(p_ctx, http.MethodDelete, userPath(p_usrId, ""), nil, nil, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ChangeUserStatus changes the status of a user (POST /admin/users/{id}/status).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_usrId: The ID of the user.
+//   - p_req: The new status and the reason of the change.
+//
+// Returns:
+//   - *UserSummary: The updated user.
+//   - error: An *APIError matching ErrNotFound, or ErrConflict if the transition is not allowed.
+func (c *Client) ChangeUserStatus(p_ctx context.Context, p_usrId string, p_req ChangeStatusRequest) (*UserSummary, error) {
+	var res UserSummary
+	if err := c.do(p_ctx, http.MethodPost, userPath(p_usrId, "/status"), nil, p_req, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DisableUser disables a user (POST /admin/users/{id}/disable).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_usrId: The ID of the user.
+//   - p_reason: Why the user is disabled, may be empty.
+//
+// Returns:
+//   - *UserSummary: The updated user.
+//   - error: An *APIError matching ErrNotFound, or ErrConflict if the transition is not allowed.
+func (c *Client) DisableUser(p_ctx context.Context, p_usrId string, p_reason string) (*UserSummary, error) {
+	var res UserSummary
+	err := c.do(p_ctx, http.MethodPost, userPath(p_usrId, "/disable"), nil,
+		ChangeStatusRequest{Reason: p_reason}, &res, authSession)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// EnableUser re-activates a user (POST /admin/users/{id}/enable).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_usrId: The ID of the user.
+//   - p_reason: Why the user is enabled, may be empty.
+//
+// Returns:
+//   - *UserSummary: The updated user.
+//   - error: An *APIError matching ErrNotFound, or ErrConflict if the transition is not allowed.
+func (c *Client) EnableUser(p_ctx context.Context, p_usrId string, p_reason string) (*UserSummary, error) {
+	var res UserSummary
+	err := c.do(p_ctx, http.MethodPost, userPath(p_usrId, "/enable"), nil,
+		ChangeStatusRequest{Reason: p_reason}, &res, authSession)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ForceLogout revokes every session of a user (POST /admin/users/{id}/logout).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_usrId: The ID of the user.
+//
+// Returns:
+//   - *AdminMessageResponse: The confirmation message.
+//   - error: An *APIError matching ErrNotFound if the user does not exist.
+func (c *Client) ForceLogout(p_ctx context.Context, p_usrId string) (*AdminMessageResponse, error) {
+	var res AdminMessageResponse
+	if err := c.do(p_ctx, http.MethodPost, userPath(p_usrId, "/logout"), nil, nil, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ForcePasswordReset requires a user to change their password before logging in again
+// (POST /admin/users/{id}/reset-password).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_usrId: The ID of the user.
+//
+// Returns:
+//   - *AdminMessageResponse: The confirmation message.
+//   - error: An *APIError matching ErrNotFound if the user does not exist.
+func (c *Client) ForcePasswordReset(p_ctx context.Context, p_usrId string) (*AdminMessageResponse, error) {
+	var res AdminMessageResponse
+	if err := c.do(p_ctx, http.MethodPost, userPath(p_usrId, "/reset-password"), nil, nil, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListAuditEvents returns a page of the security audit log (GET /admin/audit).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_filter: The filter and the page of the events.
+//
+// Returns:
+//   - *ListAuditEventsResponse: A page of events, newest first.
+//   - error: An *APIError matching ErrBadRequest for malformed filters.
+func (c *Client) ListAuditEvents(p_ctx context.Context, p_filter AuditFilter) (*ListAuditEventsResponse, error) {
+	var res ListAuditEventsResponse
+	if err := c.do(p_ctx, http.MethodGet, "/admin/audit", p_filter.values(), nil, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// endregion Public
+
+// region Private
+
+// userPath builds the path of an administration route targeting a user.
+func userPath(p_usrId string, p_suffix string) string {
+	return "/admin/users/" + url.PathEscape(p_usrId) + p_suffix
+}
+
+// endregion Private
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// region Public
+
+// Register creates a new account (POST /auth/register).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_req: The email, name and password of the new user.
+//
+// Returns:
+//   - *RegisterResponse: The ID of the created user.
+//   - error: An *APIError matching ErrBadRequest or ErrConflict, or the transport error.
+func (c *Client) Register(p_ctx context.Context, p_req RegisterRequest) (*RegisterResponse, error) {
+	var res RegisterResponse
+	if err := c.do(p_ctx, http.MethodPost, "/auth/register", nil, p_req, &res, authNone); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ChangePassword changes the password of a user (POST /auth/change-password).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_req: The email, the current password and the new password of the user.
+//
+// Returns:
+//   - *ChangePasswordResponse: The confirmation message.
+//   - error: An *APIError matching ErrBadRequest or ErrUnauthorized, or the transport error.
+func (c *Client) ChangePassword(p_ctx context.Context, p_req ChangePasswordRequest) (*ChangePasswordResponse, error) {
+	var res ChangePasswordResponse
+	if err := c.do(p_ctx, http.MethodPost, "/auth/change-password", nil, p_req, &res, authNone); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// endregion Public
@@ -0,0 +1,305 @@
+// Package client is a Go client for the Cerberus HTTP API.
+//
+// Every route of the server is wrapped by a typed method. Logging in stores the session tokens
+// in a TokenStore, and the authenticated methods refresh the access token when it is about to
+// expire or when the server rejects it:
+//
+//	c, err := client.New(client.Config{BaseURL: "https://auth.example.com"})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	if _, err := c.Login(ctx, client.LoginRequest{Email: email, Password: password}); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	profile, err := c.GetProfile(ctx)
+//	if errors.Is(err, client.ErrSessionExpired) {
+//		// Log in again
+//	}
+//
+// Errors returned by the server are *APIError values, matching ErrUnauthorized, ErrForbidden,
+// ErrNotFound and ErrConflict through errors.Is.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultTimeout       time.Duration = 10 * time.Second
+	defaultRefreshBefore time.Duration = 30 * time.Second
+
+	// maxResponseBytes bounds the responses read from the server.
+	maxResponseBytes int64 = 10 << 20
+)
+
+// Config holds the settings of a Client.
+//
+// Fields:
+//   - BaseURL: The root URL of the Cerberus server (e.g., "https://auth.example.com").
+//   - HTTPClient: The client used to reach Cerberus, a client with a 10s timeout by default.
+//   - TokenStore: Where the session tokens are kept, a MemoryTokenStore by default.
+//   - RefreshBefore: How long before its expiration the access token is refreshed, 30 seconds by default.
+//   - RevocationFeedKey: The REVOCATION_FEED_KEY of the server, sent by ListRevocations.
+type Config struct {
+	BaseURL           string
+	HTTPClient        *http.Client
+	TokenStore        TokenStore
+	RefreshBefore     time.Duration
+	RevocationFeedKey string
+}
+
+// Client calls the Cerberus HTTP API. It is safe for concurrent use.
+type Client struct {
+	cfg     Config
+	baseURL *url.URL
+
+	refreshMu sync.Mutex // Serializes the refreshes, the server rotates the refresh token on each one.
+}
+
+// authMode selects the credentials sent with a request.
+type authMode int
+
+const (
+	authNone     authMode = iota // No credentials.
+	authSession                  // The access token of the stored session.
+	authPassword                 // The access token of the stored session, on routes answering 401 to a wrong password.
+	authFeedKey                  // The revocation feed key.
+)
+
+// region Public
+
+// New creates a Client from a configuration, applying the defaults of the unset fields.
+//
+// Parameters:
+//   - p_cfg: The client settings.
+//
+// Returns:
+//   - *Client: The client.
+//   - error: ErrInvalidConfig if the base URL is missing or malformed.
+func New(p_cfg Config) (*Client, error) {
+	if p_cfg.BaseURL == "" {
+		return nil, errors.Join(ErrInvalidConfig, errors.New("a base URL is required"))
+	}
+	base, err := url.Parse(strings.TrimSuffix(p_cfg.BaseURL, "/"))
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, errors.Join(ErrInvalidConfig, fmt.Errorf("malformed base URL %q", p_cfg.BaseURL))
+	}
+
+	if p_cfg.HTTPClient == nil {
+		p_cfg.HTTPClient = &http.Client{Timeout: defaultTimeout}
+	}
+	if p_cfg.TokenStore == nil {
+		p_cfg.TokenStore = &MemoryTokenStore{}
+	}
+	if p_cfg.RefreshBefore <= 0 {
+		p_cfg.RefreshBefore = defaultRefreshBefore
+	}
+
+	return &Client{cfg: p_cfg, baseURL: base}, nil
+}
+
+// Tokens returns the tokens of the current session.
+//
+// Parameters:
+//   - p_ctx: The context passed to the token store.
+//
+// Returns:
+//   - *Tokens: The session tokens.
+//   - error: ErrNotAuthenticated if no session is stored, or the error of the token store.
+func (c *Client) Tokens(p_ctx context.Context) (*Tokens, error) {
+	tkns, err := c.cfg.TokenStore.Load(p_ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tkns == nil || tkns.AccessToken == "" {
+		return nil, ErrNotAuthenticated
+	}
+	return tkns, nil
+}
+
+// SetTokens replaces the tokens of the current session, for instance to resume a session
+// created by another process.
+//
+// Parameters:
+//   - p_ctx: The context passed to the token store.
+//   - p_tkns: The session tokens.
+//
+// Returns:
+//   - error: The error of the token store, nil otherwise.
+func (c *Client) SetTokens(p_ctx context.Context, p_tkns *Tokens) error {
+	return c.cfg.TokenStore.Save(p_ctx, p_tkns)
+}
+
+// endregion Public
+
+// region Private
+
+// do sends a request and decodes the JSON response into p_out.
+//
+// With authSession, the access token is refreshed beforehand when it is about to expire, and
+// the request is sent once more after a refresh if the server rejects the token. With
+// authPassword, a rejection is returned as is since it usually means the password was wrong.
+func (c *Client) do(p_ctx context.Context, p_method string, p_path string, p_query url.Values,
+	p_in interface{}, p_out interface{}, p_auth authMode) error {
+	var body []byte
+	if p_in != nil {
+		var err error
+		if body, err = json.Marshal(p_in); err != nil {
+			return err
+		}
+	}
+
+	if p_auth != authSession && p_auth != authPassword {
+		return c.send(p_ctx, p_method, p_path, p_query, body, p_out, c.credentials(p_auth, ""))
+	}
+
+	tkns, err := c.Tokens(p_ctx)
+	if err != nil {
+		return err
+	}
+	if c.expiresSoon(tkns.AccessToken) {
+		if tkns, err = c.refresh(p_ctx, tkns); err != nil {
+			return err
+		}
+	}
+
+	err = c.send(p_ctx, p_method, p_path, p_query, body, p_out, c.credentials(p_auth, tkns.AccessToken))
+	if p_auth == authPassword || !errors.Is(err, ErrUnauthorized) || tkns.RefreshToken == "" {
+		return err
+	}
+
+	if tkns, err = c.refresh(p_ctx, tkns); err != nil {
+		return err
+	}
+	return c.send(p_ctx, p_method, p_path, p_query, body, p_out, c.credentials(p_auth, tkns.AccessToken))
+}
+
+// send performs a single HTTP request. Path parameters in p_path must already be escaped.
+func (c *Client) send(p_ctx context.Context, p_method string, p_path string, p_query url.Values,
+	p_body []byte, p_out interface{}, p_authorization string) error {
+	target := c.baseURL.String() + p_path
+	if len(p_query) > 0 {
+		target += "?" + p_query.Encode()
+	}
+
+	var reader io.Reader
+	if p_body != nil {
+		reader = bytes.NewReader(p_body)
+	}
+	req, err := http.NewRequestWithContext(p_ctx, p_method, target, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if p_body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if p_authorization != "" {
+		req.Header.Set("Authorization", p_authorization)
+	}
+
+	res, err := c.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxResponseBytes))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return newAPIError(res.StatusCode, data)
+	}
+
+	if p_out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, p_out); err != nil {
+		return fmt.Errorf("failed to decode the response of %s %s - %w", p_method, p_path, err)
+	}
+	return nil
+}
+
+// credentials returns the Authorization header of a request.
+func (c *Client) credentials(p_auth authMode, p_accessToken string) string {
+	switch p_auth {
+	case authSession, authPassword:
+		return "Bearer " + p_accessToken
+	case authFeedKey:
+		if c.cfg.RevocationFeedKey != "" {
+			return "Bearer " + c.cfg.RevocationFeedKey
+		}
+	}
+	return ""
+}
+
+// expiresSoon checks whether an access token expires within the RefreshBefore window.
+// The token is only decoded, its signature is checked by the server.
+func (c *Client) expiresSoon(p_token string) bool {
+	claims := jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(p_token, &claims); err != nil || claims.ExpiresAt == nil {
+		return false
+	}
+	return time.Until(claims.ExpiresAt.Time) < c.cfg.RefreshBefore
+}
+
+// refresh exchanges the refresh token for a new pair of tokens and stores them.
+//
+// p_stale holds the tokens the caller used; if another goroutine already replaced them, the
+// stored tokens are returned without refreshing again. When the server refuses the refresh,
+// the session is cleared and ErrSessionExpired is returned.
+func (c *Client) refresh(p_ctx context.Context, p_stale *Tokens) (*Tokens, error) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	current, err := c.Tokens(p_ctx)
+	if err != nil {
+		return nil, err
+	}
+	if current.AccessToken != p_stale.AccessToken {
+		return current, nil
+	}
+	if current.RefreshToken == "" {
+		return nil, ErrSessionExpired
+	}
+
+	body, err := json.Marshal(RefreshRequest{RefreshToken: current.RefreshToken})
+	if err != nil {
+		return nil, err
+	}
+
+	var res RefreshResponse
+	err = c.send(p_ctx, http.MethodPost, "/session/refresh", nil, body, &res,
+		c.credentials(authSession, current.AccessToken))
+	if errors.Is(err, ErrUnauthorized) {
+		if clearErr := c.cfg.TokenStore.Clear(p_ctx); clearErr != nil {
+			return nil, clearErr
+		}
+		return nil, errors.Join(ErrSessionExpired, err)
+	} else if err != nil {
+		return nil, err
+	}
+
+	tkns := &Tokens{AccessToken: res.Token, RefreshToken: res.RefreshToken}
+	if err := c.cfg.TokenStore.Save(p_ctx, tkns); err != nil {
+		return nil, err
+	}
+	return tkns, nil
+}
+
+// endregion Private
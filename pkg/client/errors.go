@@ -0,0 +1,88 @@
+package client
+
+import (
+	"cerberus/internal/tools/validator"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrInvalidConfig    = errors.New("invalid client configuration")
+	ErrNotAuthenticated = errors.New("not authenticated")
+	ErrSessionExpired   = errors.New("session expired")
+
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrServer       = errors.New("server error")
+)
+
+// FieldError describes a single validation failure reported by the server.
+type FieldError = validator.FieldError
+
+// APIError is returned when the server answers with an error status.
+//
+// Fields:
+//   - StatusCode: The HTTP status code of the response.
+//   - Message: The error message sent by the server.
+//   - Errors: The per-field validation failures, set on validation errors only.
+type APIError struct {
+	StatusCode int
+	Message    string
+	Errors     []FieldError
+}
+
+// region Public
+
+// Error returns the status code and the message of the error.
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("cerberus: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("cerberus: %d %s", e.StatusCode, e.Message)
+}
+
+// Is matches the error against the sentinel of its status code, so that callers can use
+// errors.Is(err, client.ErrNotFound).
+func (e *APIError) Is(p_target error) bool {
+	switch e.StatusCode {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge:
+		return p_target == ErrBadRequest
+	case http.StatusUnauthorized:
+		return p_target == ErrUnauthorized
+	case http.StatusForbidden:
+		return p_target == ErrForbidden
+	case http.StatusNotFound:
+		return p_target == ErrNotFound
+	case http.StatusConflict:
+		return p_target == ErrConflict
+	}
+	return e.StatusCode >= 500 && p_target == ErrServer
+}
+
+// endregion Public
+
+// region Private
+
+// newAPIError builds an APIError from an error response. The server answers with a JSON
+// validator.ErrorResponse on malformed bodies and with a plain text message otherwise.
+func newAPIError(p_status int, p_body []byte) *APIError {
+	apiErr := &APIError{StatusCode: p_status}
+
+	var res validator.ErrorResponse
+	if err := json.Unmarshal(p_body, &res); err == nil && res.Message != "" {
+		apiErr.Message = res.Message
+		apiErr.Errors = res.Errors
+		return apiErr
+	}
+
+	apiErr.Message = strings.TrimSpace(string(p_body))
+	return apiErr
+}
+
+// endregion Private
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// region Public
+
+// GetProfile returns the profile of the logged in user (GET /me).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//
+// Returns:
+//   - *ProfileResponse: The profile.
+//   - error: ErrSessionExpired if the session cannot be refreshed, or an *APIError.
+func (c *Client) GetProfile(p_ctx context.Context) (*ProfileResponse, error) {
+	var res ProfileResponse
+	if err := c.do(p_ctx, http.MethodGet, "/me", nil, nil, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateProfile changes the name or the attributes of the logged in user (PATCH /me).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_req: The fields to change.
+//
+// Returns:
+//   - *ProfileResponse: The updated profile.
+//   - error: ErrSessionExpired if the session cannot be refreshed, or an *APIError matching ErrBadRequest.
+func (c *Client) UpdateProfile(p_ctx context.Context, p_req UpdateProfileRequest) (*ProfileResponse, error) {
+	var res ProfileResponse
+	if err := c.do(p_ctx, http.MethodPatch, "/me", nil, p_req, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteAccount deletes the account of the logged in user (DELETE /me) and clears the stored
+// tokens, which the server revoked.
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_req: The current password of the user, left empty for accounts without one.
+//
+// Returns:
+//   - *DeleteAccountResponse: When the account data will be permanently removed.
+//   - error: An *APIError matching ErrUnauthorized if the password is wrong, or the error of the token store.
+func (c *Client) DeleteAccount(p_ctx context.Context, p_req DeleteAccountRequest) (*DeleteAccountResponse, error) {
+	var res DeleteAccountResponse
+	if err := c.do(p_ctx, http.MethodDelete, "/me", nil, p_req, &res, authPassword); err != nil {
+		return nil, err
+	}
+
+	if err := c.cfg.TokenStore.Clear(p_ctx); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ExportData returns every piece of data stored about the logged in user (GET /me/export).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//
+// Returns:
+//   - *ExportResponse: The exported data.
+//   - error: ErrSessionExpired if the session cannot be refreshed, or an *APIError.
+func (c *Client) ExportData(p_ctx context.Context) (*ExportResponse, error) {
+	var res ExportResponse
+	if err := c.do(p_ctx, http.MethodGet, "/me/export", nil, nil, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// SetPassword replaces the password of the user of the stored session (POST /me/password).
+// The session must have authenticated recently, or be reauthenticated first.
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_req: The new password.
+//
+// Returns:
+//   - *ProfileMessageResponse: The confirmation message.
+//   - error: An *APIError matching ErrUnauthorized if a recent authentication is required,
+//     ErrBadRequest if the password is unchanged or ErrConflict if the account has no local
+//     password.
+func (c *Client) SetPassword(p_ctx context.Context, p_req SetPasswordRequest) (*ProfileMessageResponse, error) {
+	var res ProfileMessageResponse
+	if err := c.do(p_ctx, http.MethodPost, "/me/password", nil, p_req, &res, authPassword); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// RequestEmailChange sends a confirmation token to a new email address (POST /me/email).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_req: The new email address and the current password of the user.
+//
+// Returns:
+//   - *ProfileMessageResponse: The confirmation message.
+//   - error: An *APIError matching ErrUnauthorized if the password is wrong or ErrConflict if the
+//     address is taken.
+func (c *Client) RequestEmailChange(p_ctx context.Context, p_req ChangeEmailRequest) (*ProfileMessageResponse, error) {
+	var res ProfileMessageResponse
+	if err := c.do(p_ctx, http.MethodPost, "/me/email", nil, p_req, &res, authPassword); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ConfirmEmailChange applies a pending email change (POST /me/email/confirm).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_req: The token sent to the new address.
+//
+// Returns:
+//   - *ProfileResponse: The updated profile.
+//   - error: An *APIError matching ErrBadRequest if the token is invalid or ErrConflict if the
+//     address was taken in the meantime.
+func (c *Client) ConfirmEmailChange(p_ctx context.Context, p_req ConfirmEmailRequest) (*ProfileResponse, error) {
+	var res ProfileResponse
+	if err := c.do(p_ctx, http.MethodPost, "/me/email/confirm", nil, p_req, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListSecurityEvents returns the audit events affecting the logged in user (GET /me/security-events).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_filter: The filter and the page of the events; ActorID and TargetID are ignored.
+//
+// Returns:
+//   - *ListAuditEventsResponse: A page of events, newest first.
+//   - error: ErrSessionExpired if the session cannot be refreshed, or an *APIError matching ErrBadRequest.
+func (c *Client) ListSecurityEvents(p_ctx context.Context, p_filter AuditFilter) (*ListAuditEventsResponse, error) {
+	var res ListAuditEventsResponse
+	if err := c.do(p_ctx, http.MethodGet, "/me/security-events", p_filter.values(), nil, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// endregion Public
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"cerberus/pkg/jwks"
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// region Public
+
+// Login authenticates a user (POST /session/login) and stores the session tokens, which are
+// then sent by the authenticated methods.
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_req: The email and the password of the user.
+//
+// Returns:
+//   - *LoginResponse: The session tokens.
+//   - error: An *APIError matching ErrUnauthorized for invalid credentials or ErrForbidden for
+//     inactive accounts, the error of the token store, or the transport error.
+func (c *Client) Login(p_ctx context.Context, p_req LoginRequest) (*LoginResponse, error) {
+	var res LoginResponse
+	if err := c.do(p_ctx, http.MethodPost, "/session/login", nil, p_req, &res, authNone); err != nil {
+		return nil, err
+	}
+
+	tkns := &Tokens{AccessToken: res.Token, RefreshToken: res.RefreshToken}
+	if err := c.cfg.TokenStore.Save(p_ctx, tkns); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Logout ends the current session (POST /session/logout) and clears the stored tokens.
+// The tokens are cleared even if the server already considered the session over.
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//
+// Returns:
+//   - error: ErrNotAuthenticated if no session is stored, the error of the token store, or the
+//     transport error.
+func (c *Client) Logout(p_ctx context.Context) error {
+	err := c.do(p_ctx, http.MethodPost, "/session/logout", nil, nil, nil, authSession)
+	if err != nil && !errors.Is(err, ErrUnauthorized) && !errors.Is(err, ErrSessionExpired) {
+		return err
+	}
+
+	return c.cfg.TokenStore.Clear(p_ctx)
+}
+
+// Refresh exchanges the stored refresh token for a new pair of tokens (POST /session/refresh).
+// The authenticated methods call it automatically; calling it directly is only needed to extend
+// an idle session.
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//
+// Returns:
+//   - *Tokens: The new session tokens.
+//   - error: ErrSessionExpired if the server refused the refresh, ErrNotAuthenticated if no
+//     session is stored, or the transport error.
+func (c *Client) Refresh(p_ctx context.Context) (*Tokens, error) {
+	tkns, err := c.Tokens(p_ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.refresh(p_ctx, tkns)
+}
+
+// Validate asks the server whether the stored access token is active (POST /session/validate).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//
+// Returns:
+//   - *ValidateResponse: The claims of the token.
+//   - error: ErrSessionExpired if the session cannot be refreshed, or an *APIError.
+func (c *Client) Validate(p_ctx context.Context) (*ValidateResponse, error) {
+	var res ValidateResponse
+	if err := c.do(p_ctx, http.MethodPost, "/session/validate", nil, nil, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ValidateToken asks the server whether any access token is active (POST /session/validate).
+// Unlike Validate, the stored session is neither used nor refreshed.
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_token: The access token to check.
+//
+// Returns:
+//   - *ValidateResponse: The claims of the token.
+//   - error: An *APIError matching ErrUnauthorized if the token is not active, or the transport error.
+func (c *Client) ValidateToken(p_ctx context.Context, p_token string) (*ValidateResponse, error) {
+	var res ValidateResponse
+	err := c.send(p_ctx, http.MethodPost, "/session/validate", nil, nil, &res, "Bearer "+p_token)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListRevocations returns the access tokens revoked after a point in time
+// (GET /session/revocations), sending the configured RevocationFeedKey.
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_since: Only revocations after this time; the zero value returns every retained revocation.
+//
+// Returns:
+//   - *RevocationsResponse: The revocations and the cursor of the next call.
+//   - error: An *APIError matching ErrUnauthorized if the feed key is wrong, or the transport error.
+func (c *Client) ListRevocations(p_ctx context.Context, p_since time.Time) (*RevocationsResponse, error) {
+	query := url.Values{}
+	if !p_since.IsZero() {
+		query.Set("since", p_since.Format(time.RFC3339Nano))
+	}
+
+	var res RevocationsResponse
+	if err := c.do(p_ctx, http.MethodGet, "/session/revocations", query, nil, &res, authFeedKey); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// JWKS returns the public keys verifying the access tokens (GET /.well-known/jwks.json).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//
+// Returns:
+//   - *jwks.Set: The key set, empty when the server signs with a shared secret.
+//   - error: An *APIError or the transport error.
+func (c *Client) JWKS(p_ctx context.Context) (*jwks.Set, error) {
+	var res jwks.Set
+	if err := c.do(p_ctx, http.MethodGet, "/.well-known/jwks.json", nil, nil, &res, authNone); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// endregion Public
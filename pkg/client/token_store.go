@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// Tokens holds the tokens of a session.
+//
+// Fields:
+//   - AccessToken: The JWT sent with the authenticated requests.
+//   - RefreshToken: The token exchanged for a new pair of tokens when the access token expires.
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// TokenStore keeps the tokens of the session of a Client, for instance in a file or a keyring so
+// that a CLI stays logged in between runs. Implementations must be safe for concurrent use.
+type TokenStore interface {
+	// Load returns the stored tokens, or nil if no session is stored.
+	Load(p_ctx context.Context) (*Tokens, error)
+	// Save replaces the stored tokens.
+	Save(p_ctx context.Context, p_tkns *Tokens) error
+	// Clear removes the stored tokens.
+	Clear(p_ctx context.Context) error
+}
+
+// MemoryTokenStore is a TokenStore keeping the tokens in memory. The zero value is ready to use.
+type MemoryTokenStore struct {
+	mu   sync.Mutex
+	tkns *Tokens
+}
+
+// region Public
+
+// Load returns a copy of the stored tokens, or nil if no session is stored.
+func (s *MemoryTokenStore) Load(_ context.Context) (*Tokens, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tkns == nil {
+		return nil, nil
+	}
+	tkns := *s.tkns
+	return &tkns, nil
+}
+
+// Save replaces the stored tokens with a copy of p_tkns.
+func (s *MemoryTokenStore) Save(_ context.Context, p_tkns *Tokens) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p_tkns == nil {
+		s.tkns = nil
+		return nil
+	}
+	tkns := *p_tkns
+	s.tkns = &tkns
+	return nil
+}
+
+// Clear removes the stored tokens.
+func (s *MemoryTokenStore) Clear(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tkns = nil
+	return nil
+}
+
+// endregion Public
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"cerberus/internal/dto/admin_dto"
+	"cerberus/internal/dto/audit_dto"
+	"cerberus/internal/dto/auth_dto"
+	"cerberus/internal/dto/profile_dto"
+	"cerberus/internal/dto/session_dto"
+	"cerberus/internal/dto/webhook_dto"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// The request and response types of the API are the DTOs used by the server, re-exported so that
+// they can be named outside of the Cerberus module.
+type (
+	RegisterRequest        = auth_dto.RegisterRequest
+	RegisterResponse       = auth_dto.RegisterResponse
+	ChangePasswordRequest  = auth_dto.ChangePasswordRequest
+	ChangePasswordResponse = auth_dto.ChangePasswordResponse
+
+	LoginRequest        = session_dto.LoginRequest
+	LoginResponse       = session_dto.LoginResponse
+	RefreshRequest      = session_dto.RefreshRequest
+	RefreshResponse     = session_dto.RefreshResponse
+	ValidateResponse    = session_dto.ValidateResponse
+	Revocation          = session_dto.Revocation
+	RevocationsResponse = session_dto.RevocationsResponse
+
+	ProfileResponse        = profile_dto.ProfileResponse
+	UpdateProfileRequest   = profile_dto.UpdateProfileRequest
+	ChangeEmailRequest     = profile_dto.ChangeEmailRequest
+	SetPasswordRequest     = profile_dto.SetPasswordRequest
+	ConfirmEmailRequest    = profile_dto.ConfirmEmailRequest
+	ProfileMessageResponse = profile_dto.ProfileMessageResponse
+	DeleteAccountRequest   = profile_dto.DeleteAccountRequest
+	DeleteAccountResponse  = profile_dto.DeleteAccountResponse
+	SessionExport          = profile_dto.SessionExport
+	ExportResponse         = profile_dto.ExportResponse
+
+	UserSummary             = admin_dto.UserSummary
+	ListUsersResponse       = admin_dto.ListUsersResponse
+	ChangeStatusRequest     = admin_dto.ChangeStatusRequest
+	AdminMessageResponse    = admin_dto.AdminMessageResponse
+	AdminDeleteUserResponse = admin_dto.AdminDeleteUserResponse
+
+	CreateWebhookRequest   = webhook_dto.CreateWebhookRequest
+	UpdateWebhookRequest   = webhook_dto.UpdateWebhookRequest
+	WebhookResponse        = webhook_dto.WebhookResponse
+	ListWebhooksResponse   = webhook_dto.ListWebhooksResponse
+	DeliveryResponse       = webhook_dto.DeliveryResponse
+	ListDeliveriesResponse = webhook_dto.ListDeliveriesResponse
+
+	AuditEventResponse      = audit_dto.AuditEventResponse
+	ListAuditEventsResponse = audit_dto.ListAuditEventsResponse
+)
+
+// Pagination selects a page of a paginated listing. Zero values use the defaults of the server.
+//
+// Fields:
+//   - Page: The 1-based page number.
+//   - PageSize: The number of items per page.
+type Pagination struct {
+	Page     int
+	PageSize int
+}
+
+// ListUsersOptions filters the users listed by ListUsers.
+//
+// Fields:
+//   - Pagination: The page to return.
+//   - Query: A case-insensitive filter matched against the email and the name.
+type ListUsersOptions struct {
+	Pagination
+	Query string
+}
+
+// AuditFilter filters the events listed by ListAuditEvents and ListSecurityEvents.
+// Empty fields are ignored.
+//
+// Fields:
+//   - Pagination: The page to return.
+//   - Type: The event type (e.g., "login_failure").
+//   - ActorID: The user who performed the action.
+//   - TargetID: The user affected by the action.
+//   - Outcome: "success" or "failure".
+//   - Since: Only events that happened at or after this time.
+//   - Until: Only events that happened before this time.
+type AuditFilter struct {
+	Pagination
+	Type     string
+	ActorID  string
+	TargetID string
+	Outcome  string
+	Since    time.Time
+	Until    time.Time
+}
+
+// ListDeliveriesOptions filters the deliveries listed by ListWebhookDeliveries.
+//
+// Fields:
+//   - Pagination: The page to return.
+//   - Status: The delivery status ("pending", "succeeded" or "failed").
+type ListDeliveriesOptions struct {
+	Pagination
+	Status string
+}
+
+// region Private
+
+// values encodes the pagination query parameters.
+func (p Pagination) values() url.Values {
+	query := url.Values{}
+	if p.Page > 0 {
+		query.Set("page", strconv.Itoa(p.Page))
+	}
+	if p.PageSize > 0 {
+		query.Set("page_size", strconv.Itoa(p.PageSize))
+	}
+	return query
+}
+
+// values encodes the filter as query parameters.
+func (f AuditFilter) values() url.Values {
+	query := f.Pagination.values()
+	for name, value := range map[string]string{
+		"type": f.Type, "actor_id": f.ActorID, "target_id": f.TargetID, "outcome": f.Outcome,
+	} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	if !f.Since.IsZero() {
+		query.Set("since", f.Since.Format(time.RFC3339))
+	}
+	if !f.Until.IsZero() {
+		query.Set("until", f.Until.Format(time.RFC3339))
+	}
+	return query
+}
+
+// endregion Private
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// region Public
+
+// ListWebhooks returns every webhook subscription, without their secrets (GET /admin/webhooks).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//
+// Returns:
+//   - *ListWebhooksResponse: The subscriptions.
+//   - error: ErrSessionExpired if the session cannot be refreshed, or an *APIError.
+func (c *Client) ListWebhooks(p_ctx context.Context) (*ListWebhooksResponse, error) {
+	var res ListWebhooksResponse
+	if err := c.do(p_ctx, http.MethodGet, "/admin/webhooks", nil, nil, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateWebhook subscribes an endpoint to identity events (POST /admin/webhooks).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_req: The endpoint, the event types and optionally the signing secret.
+//
+// Returns:
+//   - *WebhookResponse: The subscription, including its signing secret.
+//   - error: An *APIError matching ErrBadRequest for invalid payloads.
+func (c *Client) CreateWebhook(p_ctx context.Context, p_req CreateWebhookRequest) (*WebhookResponse, error) {
+	var res WebhookResponse
+	if err := c.do(p_ctx, http.MethodPost, "/admin/webhooks", nil, p_req, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetWebhook returns a webhook subscription, without its secret (GET /admin/webhooks/{id}).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_id: The ID of the subscription.
+//
+// Returns:
+//   - *WebhookResponse: The subscription.
+//   - error: An *APIError matching ErrNotFound if the subscription does not exist.
+func (c *Client) GetWebhook(p_ctx context.Context, p_id string) (*WebhookResponse, error) {
+	var res WebhookResponse
+	if err := c.do(p_ctx, http.MethodGet, webhookPath(p_id, ""), nil, nil, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateWebhook partially updates a webhook subscription (PATCH /admin/webhooks/{id}).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_id: The ID of the subscription.
+//   - p_req: The fields to change.
+//
+// Returns:
+//   - *WebhookResponse: The updated subscription, including its secret if it was rotated.
+//   - error: An *APIError matching ErrBadRequest or ErrNotFound.
+func (c *Client) UpdateWebhook(p_ctx context.Context, p_id string, p_req UpdateWebhookRequest) (*WebhookResponse, error) {
+	var res WebhookResponse
+	if err := c.do(p_ctx, http.MethodPatch, webhookPath(p_id, ""), nil, p_req, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteWebhook removes a webhook subscription and its delivery log (DELETE /admin/webhooks/{id}).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_id: The ID of the subscription.
+//
+// Returns:
+//   - *AdminMessageResponse: The confirmation message.
+//   - error: An *APIError matching ErrNotFound if the subscription does not exist.
+func (c *Client) DeleteWebhook(p_ctx context.Context, p_id string) (*AdminMessageResponse, error) {
+	var res AdminMessageResponse
+	if err := c.do(p_ctx, http.MethodDelete, webhookPath(p_id, ""), nil, nil, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListWebhookDeliveries returns a page of the delivery log of a subscription
+// (GET /admin/webhooks/{id}/deliveries).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_id: The ID of the subscription.
+//   - p_opts: The page and the status filter.
+//
+// Returns:
+//   - *ListDeliveriesResponse: A page of deliveries.
+//   - error: An *APIError matching ErrNotFound if the subscription does not exist.
+func (c *Client) ListWebhookDeliveries(p_ctx context.Context, p_id string, p_opts ListDeliveriesOptions) (*ListDeliveriesResponse, error) {
+	query := p_opts.Pagination.values()
+	if p_opts.Status != "" {
+		query.Set("status", p_opts.Status)
+	}
+
+	var res ListDeliveriesResponse
+	if err := c.do(p_ctx, http.MethodGet, webhookPath(p_id, "/deliveries"), query, nil, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// RetryWebhookDelivery schedules a delivery for an immediate new attempt
+// (POST /admin/webhooks/{id}/deliveries/{delivery_id}/retry).
+//
+// Parameters:
+//   - p_ctx: The context of the request.
+//   - p_id: The ID of the subscription.
+//   - p_deliveryId: The ID of the delivery.
+//
+// Returns:
+//   - *AdminMessageResponse: The confirmation message.
+//   - error: An *APIError matching ErrNotFound if the delivery does not exist.
+func (c *Client) RetryWebhookDelivery(p_ctx context.Context, p_id string, p_deliveryId string) (*AdminMessageResponse, error) {
+	var res AdminMessageResponse
+	path := webhookPath(p_id, "/deliveries/"+url.PathEscape(p_deliveryId)+"/retry")
+	if err := c.do(p_ctx, http.MethodPost, path, nil, nil, &res, authSession); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// endregion Public
+
+// region Private
+
+// webhookPath builds the path of an administration route targeting a webhook subscription.
+func webhookPath(p_id string, p_suffix string) string {
+	return "/admin/webhooks/" + url.PathEscape(p_id) + p_suffix
+}
+
+// endregion Private
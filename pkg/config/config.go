@@ -4,26 +4,82 @@ import (
 	"bufio"
 	"cerberus/internal/tools/logger"
 	db_config "cerberus/pkg/config/db"
+	ldap_config "cerberus/pkg/config/ldap"
+	mail_config "cerberus/pkg/config/mail"
+	oauth_config "cerberus/pkg/config/oauth"
+	saml_config "cerberus/pkg/config/saml"
 
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // ConfigData holds the configuration settings for the application.
 // It includes the server port and the debug flag.
 type ConfigData struct {
-	ServerAddress string
-	ServerPort    int
-	Debug         bool
+	ServerAddress   string
+	ServerPort      int
+	GRPCPort        int
+	GRPCTLSCertFile string
+	GRPCTLSKeyFile  string
+	Debug           bool
 
 	EnableCORS     bool
 	AllowedOrigins []string
 
+	MaxBodyBytes      int64
+	TrustProxyHeaders bool
+
+	SessionCookieName     string
+	SessionCookieDomain   string
+	SessionCookieSameSite string
+	SessionCookieInsecure bool
+
+	JWTIssuer         string
+	JWTAudiences      []string
+	JWTLeeway         string
+	JWTSigningKeyFile string
+
+	LowercaseEmailLocal bool
+
+	AuthBackends []string
+
+	OTPDigits      int
+	OTPMaxAttempts int
+	OTPMaxFailures int
+	OTPLockout     string
+	SMSProvider    string
+
+	ReauthMaxAge string
+
+	MagicLinkEmailLimit int
+	MagicLinkIPLimit    int
+	MagicLinkRateWindow string
+
+	PersonalTokenScopes []string
+
+	DeviceClients       []string
+	DeviceTokenDuration string
+
+	AccountDeletionGrace string
+
+	WebhookMaxAttempts int
+	WebhookTimeout     string
+
 	PostgresData db_config.PostgresConfigData
 	RedisData    db_config.RedisConfigData
+
+	MailData mail_config.MailConfigData
+
+	OAuthData oauth_config.OAuthConfigData
+
+	LDAPData ldap_config.LDAPConfigData
+
+	SAMLData saml_config.SAMLConfigData
 }
 
 // DefaultCfg is the default configuration that is loaded at initialization.
@@ -32,12 +88,36 @@ var DefaultCfg ConfigData
 func init() {
 	DefaultCfg.ServerAddress = "localhost"
 	DefaultCfg.ServerPort = 8181
+	DefaultCfg.GRPCPort = 9191
 	DefaultCfg.Debug = true
 
 	DefaultCfg.EnableCORS = true
 	DefaultCfg.AllowedOrigins = make([]string, 0)
+	DefaultCfg.MaxBodyBytes = 1 << 20
+	DefaultCfg.SessionCookieName = "cerberus_session"
+	DefaultCfg.SessionCookieSameSite = "strict"
+	DefaultCfg.JWTIssuer = "cerberus"
+	DefaultCfg.JWTAudiences = []string{"cerberus"}
+	DefaultCfg.JWTLeeway = "30s"
+	DefaultCfg.AuthBackends = []string{"local"}
+	DefaultCfg.OTPDigits = 6
+	DefaultCfg.OTPMaxAttempts = 5
+	DefaultCfg.OTPMaxFailures = 10
+	DefaultCfg.OTPLockout = "1h"
+	DefaultCfg.ReauthMaxAge = "10m"
+	DefaultCfg.MagicLinkEmailLimit = 5
+	DefaultCfg.MagicLinkIPLimit = 20
+	DefaultCfg.MagicLinkRateWindow = "1h"
+	DefaultCfg.DeviceTokenDuration = "2160h"
+	DefaultCfg.AccountDeletionGrace = "720h"
+	DefaultCfg.WebhookMaxAttempts = 8
+	DefaultCfg.WebhookTimeout = "10s"
 	DefaultCfg.PostgresData = db_config.DefaultPostgresCfg
 	DefaultCfg.RedisData = db_config.DefaultRedisConfig
+	DefaultCfg.MailData = mail_config.DefaultMailConfig
+	DefaultCfg.OAuthData = oauth_config.DefaultOAuthConfig
+	DefaultCfg.LDAPData = ldap_config.DefaultLDAPConfig
+	DefaultCfg.SAMLData = saml_config.DefaultSAMLConfig
 }
 
 // region Public
@@ -46,6 +126,269 @@ func (m_config *ConfigData) GetAddressStr() string {
 	return fmt.Sprintf("%s:%d", m_config.ServerAddress, m_config.ServerPort)
 }
 
+// GetGRPCAddressStr returns the address of the gRPC server, on the same interface as the HTTP
+// server. If no port is configured, the default port is used. It returns an empty string when
+// the gRPC server is disabled with a negative port.
+func (m_config *ConfigData) GetGRPCAddressStr() string {
+	port := m_config.GRPCPort
+	if port < 0 {
+		return ""
+	} else if port == 0 {
+		port = DefaultCfg.GRPCPort
+	}
+	return fmt.Sprintf("%s:%d", m_config.ServerAddress, port)
+}
+
+// GetMaxBodyBytes returns the maximum accepted request body size in bytes.
+// If the configured value is not positive, the default limit is returned.
+func (m_config *ConfigData) GetMaxBodyBytes() int64 {
+	if m_config.MaxBodyBytes <= 0 {
+		return DefaultCfg.MaxBodyBytes
+	}
+	return m_config.MaxBodyBytes
+}
+
+// GetSessionCookieName returns the name of the cookie holding the access token of browser sessions.
+// If no name is configured, the default name is returned.
+func (m_config *ConfigData) GetSessionCookieName() string {
+	if m_config.SessionCookieName == "" {
+		return DefaultCfg.SessionCookieName
+	}
+	return m_config.SessionCookieName
+}
+
+// GetRefreshCookieName returns the name of the cookie holding the refresh token of browser
+// sessions, derived from the session cookie name.
+func (m_config *ConfigData) GetRefreshCookieName() string {
+	return m_config.GetSessionCookieName() + "_refresh"
+}
+
+// GetCSRFCookieName returns the name of the cookie exposing the CSRF token of browser sessions
+// to scripts, derived from the session cookie name.
+func (m_config *ConfigData) GetCSRFCookieName() string {
+	return m_config.GetSessionCookieName() + "_csrf"
+}
+
+// GetOAuthStateCookieName returns the name of the cookie binding a federated login to the
+// browser that started it, derived from the session cookie name.
+func (m_config *ConfigData) GetOAuthStateCookieName() string {
+	return m_config.GetSessionCookieName() + "_oauth"
+}
+
+// GetSAMLRequestCookieName returns the name of the cookie binding a SAML login to the browser
+// that started it, derived from the session cookie name.
+func (m_config *ConfigData) GetSAMLRequestCookieName() string {
+	return m_config.GetSessionCookieName() + "_saml"
+}
+
+// GetMagicLinkCookieName returns the name of the cookie binding a magic login link to the
+// browser that requested it, derived from the session cookie name.
+func (m_config *ConfigData) GetMagicLinkCookieName() string {
+	return m_config.GetSessionCookieName() + "_magic"
+}
+
+// GetSessionCookieSameSite returns the SameSite attribute of the session cookies.
+// Unknown values fall back to the default, strict.
+func (m_config *ConfigData) GetSessionCookieSameSite() http.SameSite {
+	switch strings.ToLower(m_config.SessionCookieSameSite) {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteStrictMode
+	}
+}
+
+// GetCSRFSecret retrieves the key used to compute the CSRF tokens of browser sessions from the
+// "CSRF_SECRET" environment variable, falling back to "JWT_SECRET" when it is not set.
+//
+// Returns:
+//   - []byte: The key.
+func (m_config *ConfigData) GetCSRFSecret() []byte {
+	if secret := os.Getenv("CSRF_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+	return []byte(os.Getenv("JWT_SECRET"))
+}
+
+// GetAccountDeletionGrace returns how long a soft-deleted account is kept before being
+// permanently removed. If the configured value cannot be parsed, the default is returned.
+func (m_config *ConfigData) GetAccountDeletionGrace() time.Duration {
+	d, err := time.ParseDuration(m_config.AccountDeletionGrace)
+	if err != nil {
+		d, _ = time.ParseDuration(DefaultCfg.AccountDeletionGrace)
+	}
+	return d
+}
+
+// GetAuthBackends returns the names of the backends checking passwords, in the order they are
+// tried. If no backend is configured, only the local accounts are used.
+func (m_config *ConfigData) GetAuthBackends() []string {
+	if len(m_config.AuthBackends) == 0 {
+		return DefaultCfg.AuthBackends
+	}
+	return m_config.AuthBackends
+}
+
+// GetOTPDigits returns the number of digits of one-time codes. Values outside of 6 to 10
+// digits fall back to the default.
+func (m_config *ConfigData) GetOTPDigits() int {
+	if m_config.OTPDigits < 6 || m_config.OTPDigits > 10 {
+		return DefaultCfg.OTPDigits
+	}
+	return m_config.OTPDigits
+}
+
+// GetOTPMaxAttempts returns how many wrong guesses a one-time code tolerates before being
+// discarded. If the configured value is not positive, the default is returned.
+func (m_config *ConfigData) GetOTPMaxAttempts() int {
+	if m_config.OTPMaxAttempts <= 0 {
+		return DefaultCfg.OTPMaxAttempts
+	}
+	return m_config.OTPMaxAttempts
+}
+
+// GetOTPMaxFailures returns how many wrong codes a user may send within the lockout window,
+// across every code issued to them. If the configured value is not positive, the default is
+// returned.
+func (m_config *ConfigData) GetOTPMaxFailures() int {
+	if m_config.OTPMaxFailures <= 0 {
+		return DefaultCfg.OTPMaxFailures
+	}
+	return m_config.OTPMaxFailures
+}
+
+// GetOTPLockout returns how long wrong codes are counted, from the first one. Once
+// GetOTPMaxFailures is reached, no code is issued nor accepted until the window ends. If the
+// configured value cannot be parsed, the default is returned.
+func (m_config *ConfigData) GetOTPLockout() time.Duration {
+	d, err := time.ParseDuration(m_config.OTPLockout)
+	if err != nil || d <= 0 {
+		d, _ = time.ParseDuration(DefaultCfg.OTPLockout)
+	}
+	return d
+}
+
+// GetMagicLinkEmailLimit returns how many login links may be requested for an email address
+// within the window of GetMagicLinkRateWindow. If the configured value is not positive, the
+// default is returned.
+func (m_config *ConfigData) GetMagicLinkEmailLimit() int {
+	if m_config.MagicLinkEmailLimit <= 0 {
+		return DefaultCfg.MagicLinkEmailLimit
+	}
+	return m_config.MagicLinkEmailLimit
+}
+
+// GetMagicLinkIPLimit returns how many login links a client IP address may request within the
+// window of GetMagicLinkRateWindow. If the configured value is not positive, the default is
+// returned.
+func (m_config *ConfigData) GetMagicLinkIPLimit() int {
+	if m_config.MagicLinkIPLimit <= 0 {
+		return DefaultCfg.MagicLinkIPLimit
+	}
+	return m_config.MagicLinkIPLimit
+}
+
+// GetMagicLinkRateWindow returns the window the login link requests are counted in, from the
+// first one. If the configured value cannot be parsed, the default is returned.
+func (m_config *ConfigData) GetMagicLinkRateWindow() time.Duration {
+	d, err := time.ParseDuration(m_config.MagicLinkRateWindow)
+	if err != nil || d <= 0 {
+		d, _ = time.ParseDuration(DefaultCfg.MagicLinkRateWindow)
+	}
+	return d
+}
+
+// GetReauthMaxAge returns how long after authenticating a user may perform sensitive
+// operations, such as changing their email address. If the configured value cannot be parsed,
+// the default is returned.
+func (m_config *ConfigData) GetReauthMaxAge() time.Duration {
+	d, err := time.ParseDuration(m_config.ReauthMaxAge)
+	if err != nil || d <= 0 {
+		d, _ = time.ParseDuration(DefaultCfg.ReauthMaxAge)
+	}
+	return d
+}
+
+// GetPersonalTokenScopes returns the scopes of other services that personal access tokens may
+// be granted, on top of the scopes of Cerberus itself.
+func (m_config *ConfigData) GetPersonalTokenScopes() []string {
+	return m_config.PersonalTokenScopes
+}
+
+// GetDeviceClients returns the IDs of the clients allowed to use the device authorization
+// grant. The grant is disabled when none is configured.
+func (m_config *ConfigData) GetDeviceClients() []string {
+	return m_config.DeviceClients
+}
+
+// GetDeviceTokenDuration returns how long the tokens issued through the device authorization
+// grant stay valid. If the configured value cannot be parsed, the default is returned.
+func (m_config *ConfigData) GetDeviceTokenDuration() time.Duration {
+	d, err := time.ParseDuration(m_config.DeviceTokenDuration)
+	if err != nil || d <= 0 {
+		d, _ = time.ParseDuration(DefaultCfg.DeviceTokenDuration)
+	}
+	return d
+}
+
+// GetJWTIssuer returns the "iss" claim of the issued tokens.
+// If no issuer is configured, the default issuer is returned.
+func (m_config *ConfigData) GetJWTIssuer() string {
+	if m_config.JWTIssuer == "" {
+		return DefaultCfg.JWTIssuer
+	}
+	return m_config.JWTIssuer
+}
+
+// GetJWTAudiences returns the "aud" claim of the issued tokens. A token is only accepted if
+// it is meant for at least one of them. If no audience is configured, the default is returned.
+func (m_config *ConfigData) GetJWTAudiences() []string {
+	if len(m_config.JWTAudiences) == 0 {
+		return DefaultCfg.JWTAudiences
+	}
+	return m_config.JWTAudiences
+}
+
+// GetJWTLeeway returns the clock skew tolerated when checking the time based claims of a
+// token. If the configured value cannot be parsed, the default is returned.
+func (m_config *ConfigData) GetJWTLeeway() time.Duration {
+	d, err := time.ParseDuration(m_config.JWTLeeway)
+	if err != nil || d < 0 {
+		d, _ = time.ParseDuration(DefaultCfg.JWTLeeway)
+	}
+	return d
+}
+
+// GetRevocationFeedKey retrieves the key protecting the token revocation feed from the
+// "REVOCATION_FEED_KEY" environment variable. An empty key disables the feed.
+//
+// Returns:
+//   - string: The key expected as a Bearer token, or an empty string if not set.
+func (m_config *ConfigData) GetRevocationFeedKey() string {
+	return os.Getenv("REVOCATION_FEED_KEY")
+}
+
+// GetWebhookMaxAttempts returns how many times a webhook delivery is attempted before
+// being marked as failed. If the configured value is not positive, the default is returned.
+func (m_config *ConfigData) GetWebhookMaxAttempts() int {
+	if m_config.WebhookMaxAttempts <= 0 {
+		return DefaultCfg.WebhookMaxAttempts
+	}
+	return m_config.WebhookMaxAttempts
+}
+
+// GetWebhookTimeout returns how long a single webhook delivery attempt may take.
+// If the configured value cannot be parsed, the default is returned.
+func (m_config *ConfigData) GetWebhookTimeout() time.Duration {
+	d, err := time.ParseDuration(m_config.WebhookTimeout)
+	if err != nil || d <= 0 {
+		d, _ = time.ParseDuration(DefaultCfg.WebhookTimeout)
+	}
+	return d
+}
+
 // LoadEnvFile loads environment variables from the specified `.env` file.
 // It parses the file, checks for valid key-value pairs, and returns a ConfigData struct
 // with the values of the configuration settings.
@@ -99,6 +442,19 @@ func LoadEnvFile(p_path string) (*ConfigData, error) {
 				}
 				cfg.ServerPort = v
 
+			case "GRPC_PORT":
+				v, err := strconv.Atoi(value)
+				if err != nil {
+					v = DefaultCfg.GRPCPort
+				}
+				cfg.GRPCPort = v
+
+			case "GRPC_TLS_CERT_FILE":
+				cfg.GRPCTLSCertFile = value
+
+			case "GRPC_TLS_KEY_FILE":
+				cfg.GRPCTLSKeyFile = value
+
 			case "DEBUG":
 				cfg.Debug = value == "true"
 
@@ -109,9 +465,142 @@ func LoadEnvFile(p_path string) (*ConfigData, error) {
 					cfg.AllowedOrigins[i] = strings.Trim(v, `"`)
 				}
 
+			case "JWT_ISSUER":
+				cfg.JWTIssuer = value
+
+			case "JWT_AUDIENCES":
+				cfg.JWTAudiences = nil
+				for _, v := range strings.Split(value, ",") {
+					if v = strings.TrimSpace(v); v != "" {
+						cfg.JWTAudiences = append(cfg.JWTAudiences, v)
+					}
+				}
+
+			case "JWT_LEEWAY":
+				cfg.JWTLeeway = value
+
+			case "JWT_SIGNING_KEY_FILE":
+				cfg.JWTSigningKeyFile = value
+
+			case "EMAIL_LOWERCASE_LOCAL":
+				cfg.LowercaseEmailLocal = value == "true"
+
+			case "AUTH_BACKENDS":
+				cfg.AuthBackends = nil
+				for _, v := range strings.Split(value, ",") {
+					if v = strings.TrimSpace(strings.ToLower(v)); v != "" {
+						cfg.AuthBackends = append(cfg.AuthBackends, v)
+					}
+				}
+
+			case "OTP_DIGITS":
+				v, err := strconv.Atoi(value)
+				if err != nil {
+					v = DefaultCfg.OTPDigits
+				}
+				cfg.OTPDigits = v
+
+			case "OTP_MAX_ATTEMPTS":
+				v, err := strconv.Atoi(value)
+				if err != nil {
+					v = DefaultCfg.OTPMaxAttempts
+				}
+				cfg.OTPMaxAttempts = v
+
+			case "OTP_MAX_FAILURES":
+				v, err := strconv.Atoi(value)
+				if err != nil {
+					v = DefaultCfg.OTPMaxFailures
+				}
+				cfg.OTPMaxFailures = v
+
+			case "OTP_LOCKOUT":
+				cfg.OTPLockout = value
+
+			case "SMS_PROVIDER":
+				cfg.SMSProvider = strings.ToLower(value)
+
+			case "REAUTH_MAX_AGE":
+				cfg.ReauthMaxAge = value
+
+			case "MAGIC_LINK_EMAIL_LIMIT":
+				v, err := strconv.Atoi(value)
+				if err != nil {
+					v = DefaultCfg.MagicLinkEmailLimit
+				}
+				cfg.MagicLinkEmailLimit = v
+
+			case "MAGIC_LINK_IP_LIMIT":
+				v, err := strconv.Atoi(value)
+				if err != nil {
+					v = DefaultCfg.MagicLinkIPLimit
+				}
+				cfg.MagicLinkIPLimit = v
+
+			case "MAGIC_LINK_RATE_WINDOW":
+				cfg.MagicLinkRateWindow = value
+
+			case "DEVICE_CLIENTS":
+				cfg.DeviceClients = nil
+				for _, v := range strings.Split(value, ",") {
+					if v = strings.TrimSpace(v); v != "" {
+						cfg.DeviceClients = append(cfg.DeviceClients, v)
+					}
+				}
+
+			case "DEVICE_TOKEN_DURATION":
+				cfg.DeviceTokenDuration = value
+
+			case "PERSONAL_TOKEN_SCOPES":
+				cfg.PersonalTokenScopes = nil
+				for _, v := range strings.Split(value, ",") {
+					if v = strings.TrimSpace(v); v != "" {
+						cfg.PersonalTokenScopes = append(cfg.PersonalTokenScopes, v)
+					}
+				}
+
+			case "ACCOUNT_DELETION_GRACE":
+				cfg.AccountDeletionGrace = value
+
+			case "WEBHOOK_MAX_ATTEMPTS":
+				v, err := strconv.Atoi(value)
+				if err != nil {
+					v = DefaultCfg.WebhookMaxAttempts
+				}
+				cfg.WebhookMaxAttempts = v
+
+			case "WEBHOOK_TIMEOUT":
+				cfg.WebhookTimeout = value
+
+			case "SESSION_COOKIE_NAME":
+				cfg.SessionCookieName = value
+
+			case "SESSION_COOKIE_DOMAIN":
+				cfg.SessionCookieDomain = value
+
+			case "SESSION_COOKIE_SAMESITE":
+				cfg.SessionCookieSameSite = value
+
+			case "SESSION_COOKIE_INSECURE":
+				cfg.SessionCookieInsecure = value == "true"
+
+			case "TRUST_PROXY_HEADERS":
+				cfg.TrustProxyHeaders = value == "true"
+
+			case "MAX_BODY_BYTES":
+				v, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					v = DefaultCfg.MaxBodyBytes
+				}
+				cfg.MaxBodyBytes = v
+
 			default:
 				cfg.PostgresData.ParseLineData(key, value)
 				cfg.RedisData.ParseLineData(key, value)
+				cfg.MailData.ParseLineData(key, value)
+				cfg.OAuthData.ParseLineData(key, value)
+				cfg.LDAPData.ParseLineData(key, value)
+				cfg.SAMLData.ParseLineData(key, value)
 			}
 		}
 
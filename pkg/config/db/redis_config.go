@@ -3,14 +3,24 @@ package db_config
 import (
 	"cerberus/internal/tools/logger"
 	"os"
+	"strconv"
 	"time"
 )
 
 // RedisConfigData represents the configuration data for a Redis connection.
 type RedisConfigData struct {
-	Address            string
-	JWTDuration        string
-	RefreshJWTDuration string
+	Address             string
+	JWTDuration         string
+	RefreshJWTDuration  string
+	EmailChangeDuration string
+	MagicLinkDuration   string
+	OTPDuration         string
+	DeviceCodeDuration  string
+
+	EventsStream       string
+	EventsStreamMaxLen string
+
+	RevocationChannel string
 }
 
 // DefaultRedisConfig is a global variable holding the default Redis configuration.
@@ -20,6 +30,13 @@ func init() {
 	DefaultRedisConfig.Address = "localhost:6379"
 	DefaultRedisConfig.JWTDuration = "15m"
 	DefaultRedisConfig.RefreshJWTDuration = "1h"
+	DefaultRedisConfig.EmailChangeDuration = "1h"
+	DefaultRedisConfig.MagicLinkDuration = "15m"
+	DefaultRedisConfig.OTPDuration = "5m"
+	DefaultRedisConfig.DeviceCodeDuration = "10m"
+	DefaultRedisConfig.EventsStream = "cerberus:events"
+	DefaultRedisConfig.EventsStreamMaxLen = "100000"
+	DefaultRedisConfig.RevocationChannel = "cerberus:revocations"
 }
 
 // ParseLineData parses a key-value pair and updates the corresponding field in the RedisConfigData struct.
@@ -33,6 +50,15 @@ func (cfg *RedisConfigData) ParseLineData(p_key string, p_value string) {
 		"REDIS_ADDRESS":        &cfg.Address,
 		"JWT_DURATION":         &cfg.JWTDuration,
 		"JWT_REFRESH_DURATION": &cfg.RefreshJWTDuration,
+
+		"EMAIL_CHANGE_DURATION": &cfg.EmailChangeDuration,
+		"MAGIC_LINK_DURATION":   &cfg.MagicLinkDuration,
+		"OTP_DURATION":          &cfg.OTPDuration,
+		"DEVICE_CODE_DURATION":  &cfg.DeviceCodeDuration,
+
+		"EVENTS_STREAM":        &cfg.EventsStream,
+		"EVENTS_STREAM_MAXLEN": &cfg.EventsStreamMaxLen,
+		"REVOCATION_CHANNEL":   &cfg.RevocationChannel,
 	}
 
 	if f, ok := fMap[p_key]; ok {
@@ -88,3 +114,109 @@ func (cfg *RedisConfigData) GetRefreshJWTDuration() time.Duration {
 
 	return i
 }
+
+// GetEmailChangeDuration returns how long a pending email change stays valid as a time.Duration.
+// If the EmailChangeDuration field cannot be parsed, it logs an error and returns the default duration.
+//
+// Returns:
+//   - time.Duration: The parsed email change verification duration.
+func (cfg *RedisConfigData) GetEmailChangeDuration() time.Duration {
+	i, err := time.ParseDuration(cfg.EmailChangeDuration)
+	if err != nil {
+		logger.Log("Failed to get email change duration, return default", logger.ERROR)
+		i, _ := time.ParseDuration(DefaultRedisConfig.EmailChangeDuration)
+		return i
+	}
+
+	return i
+}
+
+// GetMagicLinkDuration returns how long a magic login link stays valid as a time.Duration.
+// If the MagicLinkDuration field cannot be parsed, it logs an error and returns the default duration.
+//
+// Returns:
+//   - time.Duration: The parsed magic link duration.
+func (cfg *RedisConfigData) GetMagicLinkDuration() time.Duration {
+	i, err := time.ParseDuration(cfg.MagicLinkDuration)
+	if err != nil {
+		logger.Log("Failed to get magic link duration, return default", logger.ERROR)
+		i, _ := time.ParseDuration(DefaultRedisConfig.MagicLinkDuration)
+		return i
+	}
+
+	return i
+}
+
+// GetOTPDuration returns how long a one-time code stays valid as a time.Duration.
+// If the OTPDuration field cannot be parsed, it logs an error and returns the default duration.
+//
+// Returns:
+//   - time.Duration: The parsed one-time code duration.
+func (cfg *RedisConfigData) GetOTPDuration() time.Duration {
+	i, err := time.ParseDuration(cfg.OTPDuration)
+	if err != nil {
+		logger.Log("Failed to get one-time code duration, return default", logger.ERROR)
+		i, _ := time.ParseDuration(DefaultRedisConfig.OTPDuration)
+		return i
+	}
+
+	return i
+}
+
+// GetDeviceCodeDuration returns how long a device authorization request stays valid as a
+// time.Duration. If the DeviceCodeDuration field cannot be parsed, it logs an error and returns
+// the default duration.
+//
+// Returns:
+//   - time.Duration: The parsed device code duration.
+func (cfg *RedisConfigData) GetDeviceCodeDuration() time.Duration {
+	i, err := time.ParseDuration(cfg.DeviceCodeDuration)
+	if err != nil {
+		logger.Log("Failed to get device code duration, return default", logger.ERROR)
+		i, _ := time.ParseDuration(DefaultRedisConfig.DeviceCodeDuration)
+		return i
+	}
+
+	return i
+}
+
+// GetEventsStream returns the key of the Redis Stream identity events are appended to.
+// If no stream is configured, the default key is returned.
+//
+// Returns:
+//   - string: The key of the events stream.
+func (cfg *RedisConfigData) GetEventsStream() string {
+	if cfg.EventsStream == "" {
+		return DefaultRedisConfig.EventsStream
+	}
+	return cfg.EventsStream
+}
+
+// GetEventsStreamMaxLen returns the approximate number of entries kept in the events stream.
+// Older entries are trimmed as new ones are appended. If the configured value is not a positive
+// integer, it logs an error and returns the default length.
+//
+// Returns:
+//   - int64: The maximum length of the events stream.
+func (cfg *RedisConfigData) GetEventsStreamMaxLen() int64 {
+	n, err := strconv.ParseInt(cfg.EventsStreamMaxLen, 10, 64)
+	if err != nil || n <= 0 {
+		if cfg.EventsStreamMaxLen != "" {
+			logger.Log("Failed to get events stream length, return default", logger.ERROR)
+		}
+		n, _ = strconv.ParseInt(DefaultRedisConfig.EventsStreamMaxLen, 10, 64)
+	}
+	return n
+}
+
+// GetRevocationChannel returns the Redis pub/sub channel token revocations are published on.
+// If no channel is configured, the default channel is returned.
+//
+// Returns:
+//   - string: The name of the revocation channel.
+func (cfg *RedisConfigData) GetRevocationChannel() string {
+	if cfg.RevocationChannel == "" {
+		return DefaultRedisConfig.RevocationChannel
+	}
+	return cfg.RevocationChannel
+}
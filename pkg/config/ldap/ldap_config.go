@@ -0,0 +1,135 @@
+package ldap_config
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// GroupRole maps the members of a directory group to a Cerberus role.
+type GroupRole struct {
+	GroupDN string // The distinguished name of the group
+	Role    string // The role granted to its members
+}
+
+// LDAPConfigData represents the configuration of the LDAP authentication backend.
+//
+// Users are looked up with a search under BaseDN, bound as BindDN, then authenticated by
+// binding as the user found. The password of BindDN is read from the "LDAP_BIND_PASSWORD"
+// environment variable.
+type LDAPConfigData struct {
+	URL        string      // The URL of the directory (e.g., "ldaps://ldap.example.com")
+	StartTLS   bool        // Upgrades "ldap://" connections with StartTLS
+	BindDN     string      // The service account used to search users, anonymous if empty
+	BaseDN     string      // The base of the user search
+	UserFilter string      // The search filter, where "{login}" is replaced by the escaped email address
+	AttrEmail  string      // The attribute holding the email address
+	AttrName   string      // The attribute holding the display name
+	AttrGroups string      // The attribute listing the groups of the user
+	GroupRoles []GroupRole // The roles granted to the members of groups
+	Timeout    string      // The timeout of the directory requests
+}
+
+// DefaultLDAPConfig is a global variable holding the default LDAP configuration.
+var DefaultLDAPConfig LDAPConfigData
+
+func init() {
+	DefaultLDAPConfig.UserFilter = "(mail={login})"
+	DefaultLDAPConfig.AttrEmail = "mail"
+	DefaultLDAPConfig.AttrName = "cn"
+	DefaultLDAPConfig.AttrGroups = "memberOf"
+	DefaultLDAPConfig.Timeout = "5s"
+}
+
+// region Public
+
+// ParseLineData parses a key-value pair and updates the corresponding field in the LDAPConfigData struct.
+//
+// "LDAP_GROUP_ROLES" holds "<group DN>=><role>" pairs separated by ";", for example
+// "cn=admins,ou=groups,dc=example,dc=com=>admin".
+//
+// Parameters:
+//   - p_key: A string representing the configuration key.
+//   - p_value: A string representing the value to be set for the given key.
+func (cfg *LDAPConfigData) ParseLineData(p_key string, p_value string) {
+	fMap := map[string]*string{
+		"LDAP_URL":         &cfg.URL,
+		"LDAP_BIND_DN":     &cfg.BindDN,
+		"LDAP_BASE_DN":     &cfg.BaseDN,
+		"LDAP_USER_FILTER": &cfg.UserFilter,
+		"LDAP_ATTR_EMAIL":  &cfg.AttrEmail,
+		"LDAP_ATTR_NAME":   &cfg.AttrName,
+		"LDAP_ATTR_GROUPS": &cfg.AttrGroups,
+		"LDAP_TIMEOUT":     &cfg.Timeout,
+	}
+
+	if f, ok := fMap[p_key]; ok {
+		*f = p_value
+		return
+	}
+
+	switch p_key {
+	case "LDAP_START_TLS":
+		cfg.StartTLS = p_value == "true"
+
+	case "LDAP_GROUP_ROLES":
+		cfg.GroupRoles = nil
+		for _, pair := range strings.Split(p_value, ";") {
+			dn, role, ok := strings.Cut(pair, "=>")
+			dn, role = strings.TrimSpace(dn), strings.TrimSpace(role)
+			if ok && dn != "" && role != "" {
+				cfg.GroupRoles = append(cfg.GroupRoles, GroupRole{GroupDN: dn, Role: role})
+			}
+		}
+	}
+}
+
+// GetBindPassword retrieves the password of the service account from the "LDAP_BIND_PASSWORD"
+// environment variable.
+func (cfg *LDAPConfigData) GetBindPassword() string {
+	return os.Getenv("LDAP_BIND_PASSWORD")
+}
+
+// GetUserFilter returns the configured search filter, or the default one if not set.
+func (cfg *LDAPConfigData) GetUserFilter() string {
+	if cfg.UserFilter == "" {
+		return DefaultLDAPConfig.UserFilter
+	}
+	return cfg.UserFilter
+}
+
+// GetAttrEmail returns the attribute holding the email address, or the default one if not set.
+func (cfg *LDAPConfigData) GetAttrEmail() string {
+	if cfg.AttrEmail == "" {
+		return DefaultLDAPConfig.AttrEmail
+	}
+	return cfg.AttrEmail
+}
+
+// GetAttrName returns the attribute holding the display name, or the default one if not set.
+func (cfg *LDAPConfigData) GetAttrName() string {
+	if cfg.AttrName == "" {
+		return DefaultLDAPConfig.AttrName
+	}
+	return cfg.AttrName
+}
+
+// GetAttrGroups returns the attribute listing the groups of the user, or the default one if not set.
+func (cfg *LDAPConfigData) GetAttrGroups() string {
+	if cfg.AttrGroups == "" {
+		return DefaultLDAPConfig.AttrGroups
+	}
+	return cfg.AttrGroups
+}
+
+// GetTimeout returns the timeout of the directory requests.
+// If the configured value cannot be parsed, the default is returned.
+func (cfg *LDAPConfigData) GetTimeout() time.Duration {
+	d, err := time.ParseDuration(cfg.Timeout)
+	if err != nil || d <= 0 {
+		d, _ = time.ParseDuration(DefaultLDAPConfig.Timeout)
+	}
+	return d
+}
+
+// endregion Public
@@ -0,0 +1,84 @@
+package mail_config
+
+import (
+	"fmt"
+	"os"
+)
+
+// MailConfigData represents the configuration used to send outbound emails.
+//
+// When Host is empty no SMTP server is used and emails are written to the log instead,
+// which is convenient for local development.
+type MailConfigData struct {
+	Host        string // The hostname of the SMTP server
+	Port        string // The port of the SMTP server
+	From        string // The sender address used in the "From" header
+	LinkBaseURL string // The public base URL of the frontend used to build links sent by email
+}
+
+// DefaultMailConfig is a global variable holding the default mail configuration.
+var DefaultMailConfig MailConfigData
+
+func init() {
+	DefaultMailConfig.Host = ""
+	DefaultMailConfig.Port = "587"
+	DefaultMailConfig.From = "no-reply@cerberus.local"
+	DefaultMailConfig.LinkBaseURL = "http://localhost:3000"
+}
+
+// region Public
+
+// ParseLineData parses a key-value pair and updates the corresponding field in the MailConfigData struct.
+//
+// Parameters:
+//   - p_key: A string representing the configuration key.
+//   - p_value: A string representing the value to be set for the given key.
+func (cfg *MailConfigData) ParseLineData(p_key string, p_value string) {
+	fMap := map[string]*string{
+		"SMTP_HOST":          &cfg.Host,
+		"SMTP_PORT":          &cfg.Port,
+		"MAIL_FROM":          &cfg.From,
+		"MAIL_LINK_BASE_URL": &cfg.LinkBaseURL,
+	}
+
+	if f, ok := fMap[p_key]; ok {
+		*f = p_value
+	}
+}
+
+// GetAddress returns the SMTP server address in the "host:port" form.
+// If no port is configured, the default port is used.
+func (cfg *MailConfigData) GetAddress() string {
+	port := cfg.Port
+	if port == "" {
+		port = DefaultMailConfig.Port
+	}
+	return fmt.Sprintf("%s:%s", cfg.Host, port)
+}
+
+// GetFrom returns the configured sender address, or the default one if not set.
+func (cfg *MailConfigData) GetFrom() string {
+	if cfg.From == "" {
+		return DefaultMailConfig.From
+	}
+	return cfg.From
+}
+
+// GetLinkBaseURL returns the configured public base URL, or the default one if not set.
+func (cfg *MailConfigData) GetLinkBaseURL() string {
+	if cfg.LinkBaseURL == "" {
+		return DefaultMailConfig.LinkBaseURL
+	}
+	return cfg.LinkBaseURL
+}
+
+// GetCredentials retrieves the SMTP credentials from the environment.
+//
+// Returns:
+//   - string: The value of the SMTP_USERNAME environment variable.
+//   - string: The value of the SMTP_PASSWORD environment variable.
+func (cfg *MailConfigData) GetCredentials() (string, string) {
+	return os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD")
+}
+
+// endregion Public
@@ -0,0 +1,150 @@
+package oauth_config
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	KindOIDC   string = "oidc"   // KindOIDC is an OpenID Connect provider, configured through its discovery document.
+	KindGitHub string = "github" // KindGitHub is GitHub, which implements OAuth2 without OpenID Connect.
+
+	keyPrefix string = "OAUTH_"
+)
+
+// ProviderConfigData represents the configuration of an external identity provider.
+//
+// Providers are configured with "OAUTH_<NAME>_<FIELD>" keys, the name being used in the
+// routes in lowercase. The client secret is read from the "OAUTH_<NAME>_CLIENT_SECRET"
+// environment variable.
+type ProviderConfigData struct {
+	Name          string   // The name of the provider in the routes (e.g., "google")
+	Kind          string   // KindOIDC or KindGitHub
+	ClientID      string   // The OAuth2 client ID registered with the provider
+	Issuer        string   // The OpenID Connect issuer URL, used for discovery
+	Scopes        []string // The requested scopes
+	RedirectURL   string   // The public URL of the callback route, registered with the provider
+	DisableSignup bool     // Rejects unknown users instead of creating their account
+}
+
+// OAuthConfigData represents the configuration of the federated login.
+type OAuthConfigData struct {
+	Providers     map[string]*ProviderConfigData // The providers, by name
+	SuccessURL    string                         // Where browser sessions are redirected after logging in
+	StateDuration string                         // How long a login may take at the provider
+}
+
+// DefaultOAuthConfig is a global variable holding the default federated login configuration.
+var DefaultOAuthConfig OAuthConfigData
+
+// presets holds the default settings of well-known providers, by name.
+var presets = map[string]ProviderConfigData{
+	"google": {Kind: KindOIDC, Issuer: "https://accounts.google.com"},
+	"github": {Kind: KindGitHub, Scopes: []string{"read:user", "user:email"}},
+}
+
+func init() {
+	DefaultOAuthConfig.StateDuration = "10m"
+}
+
+// region Public
+
+// ParseLineData parses a key-value pair and updates the corresponding provider in the
+// OAuthConfigData struct. Keys that do not start with "OAUTH_" are ignored.
+//
+// Parameters:
+//   - p_key: A string representing the configuration key.
+//   - p_value: A string representing the value to be set for the given key.
+func (cfg *OAuthConfigData) ParseLineData(p_key string, p_value string) {
+	switch p_key {
+	case "OAUTH_SUCCESS_URL":
+		cfg.SuccessURL = p_value
+		return
+	case "OAUTH_STATE_DURATION":
+		cfg.StateDuration = p_value
+		return
+	}
+
+	rest, ok := strings.CutPrefix(p_key, keyPrefix)
+	if !ok {
+		return
+	}
+
+	fields := map[string]func(*ProviderConfigData){
+		"_KIND":           func(p *ProviderConfigData) { p.Kind = strings.ToLower(p_value) },
+		"_CLIENT_ID":      func(p *ProviderConfigData) { p.ClientID = p_value },
+		"_ISSUER":         func(p *ProviderConfigData) { p.Issuer = strings.TrimSuffix(p_value, "/") },
+		"_SCOPES":         func(p *ProviderConfigData) { p.Scopes = strings.Fields(strings.ReplaceAll(p_value, ",", " ")) },
+		"_REDIRECT_URL":   func(p *ProviderConfigData) { p.RedirectURL = p_value },
+		"_DISABLE_SIGNUP": func(p *ProviderConfigData) { p.DisableSignup = p_value == "true" },
+	}
+
+	for suffix, set := range fields {
+		name, ok := strings.CutSuffix(rest, suffix)
+		if !ok || name == "" {
+			continue
+		}
+		set(cfg.provider(strings.ToLower(name)))
+		return
+	}
+}
+
+// GetStateDuration returns how long a login may take at the provider before its state expires.
+// If the configured value cannot be parsed, the default is returned.
+func (cfg *OAuthConfigData) GetStateDuration() time.Duration {
+	d, err := time.ParseDuration(cfg.StateDuration)
+	if err != nil || d <= 0 {
+		d, _ = time.ParseDuration(DefaultOAuthConfig.StateDuration)
+	}
+	return d
+}
+
+// GetKind returns the kind of the provider, KindOIDC unless configured otherwise.
+func (p *ProviderConfigData) GetKind() string {
+	if p.Kind == "" {
+		return KindOIDC
+	}
+	return p.Kind
+}
+
+// GetScopes returns the requested scopes. OpenID Connect providers always get the "openid" scope.
+func (p *ProviderConfigData) GetScopes() []string {
+	scopes := p.Scopes
+	if len(scopes) == 0 && p.GetKind() == KindOIDC {
+		scopes = []string{"openid", "email", "profile"}
+	}
+	if p.GetKind() == KindOIDC && !slices.Contains(scopes, "openid") {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+	return scopes
+}
+
+// GetClientSecret retrieves the client secret of the provider from the
+// "OAUTH_<NAME>_CLIENT_SECRET" environment variable.
+func (p *ProviderConfigData) GetClientSecret() string {
+	return os.Getenv(keyPrefix + strings.ToUpper(p.Name) + "_CLIENT_SECRET")
+}
+
+// endregion Public
+
+// region Private
+
+// provider returns the provider with the given name, creating it from its preset if needed.
+func (cfg *OAuthConfigData) provider(p_name string) *ProviderConfigData {
+	if cfg.Providers == nil {
+		cfg.Providers = make(map[string]*ProviderConfigData)
+	}
+
+	p, ok := cfg.Providers[p_name]
+	if !ok {
+		preset := presets[p_name]
+		p = &preset
+		p.Name = p_name
+		cfg.Providers[p_name] = p
+	}
+	return p
+}
+
+// endregion Private
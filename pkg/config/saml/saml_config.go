@@ -0,0 +1,173 @@
+package saml_config
+
+import (
+	"strings"
+	"time"
+)
+
+const keyPrefix string = "SAML_"
+
+// ProviderConfigData represents the configuration of a SAML 2.0 identity provider.
+//
+// Providers are configured with "SAML_<NAME>_<FIELD>" keys, the name being used in the
+// routes in lowercase.
+type ProviderConfigData struct {
+	Name          string            // The name of the provider in the routes (e.g., "okta")
+	MetadataURL   string            // The URL of the metadata of the identity provider
+	MetadataFile  string            // The metadata of the identity provider, when it is not published
+	EntityID      string            // The entity ID of Cerberus, defaults to the URL of its metadata
+	NameIDFormat  string            // The requested NameID format: "persistent" (default), "email" or "unspecified"
+	AttrEmail     string            // The attribute holding the email address, defaults to the NameID
+	AttrName      string            // The attribute holding the display name
+	Attributes    map[string]string // The profile attributes set from assertion attributes, by profile attribute
+	EmailDomains  []string          // The email domains the provider may assert, required
+	DisableSignup bool              // Rejects unknown users instead of creating their account
+}
+
+// SAMLConfigData represents the configuration of Cerberus as a SAML 2.0 service provider.
+type SAMLConfigData struct {
+	Providers       map[string]*ProviderConfigData // The providers, by name
+	BaseURL         string                         // The public URL of Cerberus, used to build its metadata and ACS URLs
+	KeyFile         string                         // The PEM RSA key signing authentication requests and decrypting assertions
+	CertFile        string                         // The PEM certificate of KeyFile, published in the metadata
+	SuccessURL      string                         // Where browser sessions are redirected after logging in
+	RequestDuration string                         // How long a login may take at the provider
+}
+
+// DefaultSAMLConfig is a global variable holding the default SAML configuration.
+var DefaultSAMLConfig SAMLConfigData
+
+func init() {
+	DefaultSAMLConfig.BaseURL = "http://localhost:8181"
+	DefaultSAMLConfig.RequestDuration = "10m"
+}
+
+// region Public
+
+// ParseLineData parses a key-value pair and updates the corresponding field or provider in the
+// SAMLConfigData struct. Keys that do not start with "SAML_" are ignored.
+//
+// "SAML_<NAME>_ATTRIBUTES" holds "<profile attribute>=<assertion attribute>" pairs separated
+// by ",", and "SAML_<NAME>_EMAIL_DOMAINS" a list of domains separated by ",".
+//
+// Parameters:
+//   - p_key: A string representing the configuration key.
+//   - p_value: A string representing the value to be set for the given key.
+func (cfg *SAMLConfigData) ParseLineData(p_key string, p_value string) {
+	fMap := map[string]*string{
+		"SAML_BASE_URL":         &cfg.BaseURL,
+		"SAML_KEY_FILE":         &cfg.KeyFile,
+		"SAML_CERT_FILE":        &cfg.CertFile,
+		"SAML_SUCCESS_URL":      &cfg.SuccessURL,
+		"SAML_REQUEST_DURATION": &cfg.RequestDuration,
+	}
+
+	if f, ok := fMap[p_key]; ok {
+		*f = p_value
+		return
+	}
+
+	rest, ok := strings.CutPrefix(p_key, keyPrefix)
+	if !ok {
+		return
+	}
+
+	fields := map[string]func(*ProviderConfigData){
+		"_METADATA_URL":   func(p *ProviderConfigData) { p.MetadataURL = p_value },
+		"_METADATA_FILE":  func(p *ProviderConfigData) { p.MetadataFile = p_value },
+		"_ENTITY_ID":      func(p *ProviderConfigData) { p.EntityID = p_value },
+		"_NAMEID_FORMAT":  func(p *ProviderConfigData) { p.NameIDFormat = strings.ToLower(p_value) },
+		"_ATTR_EMAIL":     func(p *ProviderConfigData) { p.AttrEmail = p_value },
+		"_ATTR_NAME":      func(p *ProviderConfigData) { p.AttrName = p_value },
+		"_ATTRIBUTES":     func(p *ProviderConfigData) { p.Attributes = parseAttributes(p_value) },
+		"_EMAIL_DOMAINS":  func(p *ProviderConfigData) { p.EmailDomains = parseList(p_value) },
+		"_DISABLE_SIGNUP": func(p *ProviderConfigData) { p.DisableSignup = p_value == "true" },
+	}
+
+	for suffix, set := range fields {
+		name, ok := strings.CutSuffix(rest, suffix)
+		if !ok || name == "" {
+			continue
+		}
+		set(cfg.provider(strings.ToLower(name)))
+		return
+	}
+}
+
+// GetBaseURL returns the public URL of Cerberus without a trailing slash, or the default one
+// if not set.
+func (cfg *SAMLConfigData) GetBaseURL() string {
+	if cfg.BaseURL == "" {
+		return DefaultSAMLConfig.BaseURL
+	}
+	return strings.TrimSuffix(cfg.BaseURL, "/")
+}
+
+// GetRequestDuration returns how long a login may take at the provider before its request
+// expires. If the configured value cannot be parsed, the default is returned.
+func (cfg *SAMLConfigData) GetRequestDuration() time.Duration {
+	d, err := time.ParseDuration(cfg.RequestDuration)
+	if err != nil || d <= 0 {
+		d, _ = time.ParseDuration(DefaultSAMLConfig.RequestDuration)
+	}
+	return d
+}
+
+// AllowsEmail reports whether the provider may assert the given email address, according to
+// its email domains. A provider without email domains may not assert any address.
+func (p *ProviderConfigData) AllowsEmail(p_email string) bool {
+	_, domain, ok := strings.Cut(p_email, "@")
+	if !ok {
+		return false
+	}
+	for _, d := range p.EmailDomains {
+		if strings.EqualFold(d, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// endregion Public
+
+// region Private
+
+// provider returns the provider with the given name, creating it if needed.
+func (cfg *SAMLConfigData) provider(p_name string) *ProviderConfigData {
+	if cfg.Providers == nil {
+		cfg.Providers = make(map[string]*ProviderConfigData)
+	}
+
+	p, ok := cfg.Providers[p_name]
+	if !ok {
+		p = &ProviderConfigData{Name: p_name}
+		cfg.Providers[p_name] = p
+	}
+	return p
+}
+
+// parseList splits a list separated by commas, dropping empty items.
+func parseList(p_value string) []string {
+	items := make([]string, 0)
+	for _, v := range strings.Split(p_value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			items = append(items, v)
+		}
+	}
+	return items
+}
+
+// parseAttributes parses "<profile attribute>=<assertion attribute>" pairs separated by commas.
+func parseAttributes(p_value string) map[string]string {
+	attrs := make(map[string]string)
+	for _, pair := range parseList(p_value) {
+		key, attr, ok := strings.Cut(pair, "=")
+		key, attr = strings.TrimSpace(key), strings.TrimSpace(attr)
+		if ok && key != "" && attr != "" {
+			attrs[key] = attr
+		}
+	}
+	return attrs
+}
+
+// endregion Private
@@ -0,0 +1,28 @@
+package saml_config
+
+import "testing"
+
+func TestAllowsEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		domains []string
+		email   string
+		want    bool
+	}{
+		{"listed domain", []string{"acme.com"}, "ada@acme.com", true},
+		{"case-insensitive", []string{"acme.com"}, "ada@ACME.com", true},
+		{"other domain", []string{"acme.com"}, "ada@example.com", false},
+		{"subdomain", []string{"acme.com"}, "ada@evil.acme.com", false},
+		{"no domains", nil, "ada@acme.com", false},
+		{"no domain part", []string{"acme.com"}, "ada", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &ProviderConfigData{EmailDomains: tt.domains}
+			if got := p.AllowsEmail(tt.email); got != tt.want {
+				t.Errorf("AllowsEmail(%q) = %v, want %v", tt.email, got, tt.want)
+			}
+		})
+	}
+}
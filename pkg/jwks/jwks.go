@@ -0,0 +1,203 @@
+// Package jwks converts public keys to and from their JSON Web Key (RFC 7517) representation.
+//
+// It is shared by the Cerberus server, which publishes its signing keys on
+// "/.well-known/jwks.json", and by the verification SDK, which reads them.
+package jwks
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrUnsupportedKey is returned for key types or curves that cannot be represented.
+var ErrUnsupportedKey = errors.New("unsupported key type")
+
+// Key is a public JSON Web Key.
+//
+// Fields:
+//   - Kty: The key type ("RSA", "EC" or "OKP").
+//   - Kid: The key ID, matched against the "kid" header of the tokens.
+//   - Use: The intended use of the key, always "sig".
+//   - Alg: The algorithm the key signs with (e.g., "RS256").
+//   - N, E: The modulus and exponent of RSA keys.
+//   - Crv: The curve of EC ("P-256", "P-384", "P-521") and OKP ("Ed25519") keys.
+//   - X, Y: The coordinates of EC keys; X alone holds OKP keys.
+type Key struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// Set is a JSON Web Key Set.
+//
+// Fields:
+//   - Keys: The keys of the set.
+type Set struct {
+	Keys []Key `json:"keys"`
+}
+
+// region Public
+
+// Find returns the key of the set with the given ID.
+//
+// Parameters:
+//   - p_kid: The key ID to look for.
+//
+// Returns:
+//   - *Key: The matching key, or nil if the set does not hold it.
+func (s *Set) Find(p_kid string) *Key {
+	for i := range s.Keys {
+		if s.Keys[i].Kid == p_kid {
+			return &s.Keys[i]
+		}
+	}
+	return nil
+}
+
+// FromPublicKey builds the JSON Web Key of a public key.
+//
+// Parameters:
+//   - p_kid: The key ID.
+//   - p_alg: The algorithm the key signs with.
+//   - p_pub: An *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey.
+//
+// Returns:
+//   - Key: The JSON Web Key.
+//   - error: ErrUnsupportedKey for other key types.
+func FromPublicKey(p_kid string, p_alg string, p_pub crypto.PublicKey) (Key, error) {
+	key := Key{Kid: p_kid, Use: "sig", Alg: p_alg}
+
+	switch pub := p_pub.(type) {
+	case *rsa.PublicKey:
+		key.Kty = "RSA"
+		key.N = encode(pub.N.Bytes())
+		key.E = encode(big.NewInt(int64(pub.E)).Bytes())
+
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		key.Kty = "EC"
+		key.Crv = pub.Curve.Params().Name
+		key.X = encode(pub.X.FillBytes(make([]byte, size)))
+		key.Y = encode(pub.Y.FillBytes(make([]byte, size)))
+
+	case ed25519.PublicKey:
+		key.Kty = "OKP"
+		key.Crv = "Ed25519"
+		key.X = encode(pub)
+
+	default:
+		return Key{}, ErrUnsupportedKey
+	}
+
+	return key, nil
+}
+
+// PublicKey decodes the public key held by a JSON Web Key.
+//
+// Returns:
+//   - crypto.PublicKey: An *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey.
+//   - error: ErrUnsupportedKey for other key types, or a decoding error.
+func (k *Key) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("%w - curve %s", ErrUnsupportedKey, k.Crv)
+		}
+
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, errors.New("invalid EC point")
+		}
+		return pub, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("%w - curve %s", ErrUnsupportedKey, k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("%w - %s", ErrUnsupportedKey, k.Kty)
+}
+
+// KeyID derives a stable key ID from a public key: the base64url encoded SHA-256 digest of
+// its PKIX encoding.
+//
+// Parameters:
+//   - p_pub: The public key.
+//
+// Returns:
+//   - string: The key ID.
+//   - error: An error if the key cannot be encoded.
+func KeyID(p_pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(p_pub)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return encode(sum[:16]), nil
+}
+
+// endregion Public
+// region Private
+
+func encode(p_data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(p_data)
+}
+
+func decode(p_data string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(p_data)
+}
+
+// endregion Private
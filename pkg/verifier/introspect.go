@@ -0,0 +1,79 @@
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// introspectionResponse mirrors the body of the Cerberus validate endpoint.
+type introspectionResponse struct {
+	Active    bool      `json:"active"`
+	UserId    string    `json:"user_id"`
+	TokenId   string    `json:"jti"`
+	Issuer    string    `json:"iss"`
+	Audience  []string  `json:"aud"`
+	Scope     string    `json:"scope"`
+	Act       *Actor    `json:"act"`
+	IssuedAt  time.Time `json:"iat"`
+	ExpiresAt time.Time `json:"exp"`
+}
+
+// region Private
+
+// introspect asks Cerberus whether a token is active. Unlike local verification, it also
+// rejects revoked tokens and tokens of disabled accounts.
+func (v *Verifier) introspect(p_ctx context.Context, p_token string) (*Claims, error) {
+	req, err := http.NewRequestWithContext(p_ctx, http.MethodPost, v.cfg.IntrospectionURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+p_token)
+
+	res, err := v.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusUnauthorized:
+		return nil, errors.Join(ErrInvalidToken, errors.New("token rejected by the introspection endpoint"))
+	case res.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("introspection failed with status %d", res.StatusCode)
+	}
+
+	var body introspectionResponse
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&body); err != nil {
+		return nil, err
+	}
+	if !body.Active {
+		return nil, errors.Join(ErrInvalidToken, errors.New("inactive token"))
+	}
+	if body.Issuer != v.cfg.Issuer || !slices.Contains(body.Audience, v.cfg.Audience) {
+		return nil, errors.Join(ErrInvalidToken, errors.New("token issued for another issuer or audience"))
+	}
+
+	return &Claims{
+		UserID: body.UserId,
+		Scope:  body.Scope,
+		Act:    body.Act,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        body.TokenId,
+			Issuer:    body.Issuer,
+			Subject:   body.UserId,
+			Audience:  body.Audience,
+			IssuedAt:  jwt.NewNumericDate(body.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(body.ExpiresAt),
+		},
+	}, nil
+}
+
+// endregion Private
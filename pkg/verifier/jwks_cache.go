@@ -0,0 +1,108 @@
+package verifier
+
+import (
+	"cerberus/pkg/jwks"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// minRefreshInterval bounds how often an unknown key ID triggers a refresh of the keys, so
+// that forged tokens cannot be used to flood Cerberus.
+const minRefreshInterval time.Duration = 10 * time.Second
+
+// keyCache holds the public keys fetched from the JWKS endpoint.
+type keyCache struct {
+	url    string
+	client *http.Client
+	ttl    time.Duration
+
+	mu        sync.Mutex
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+// region Private
+
+func newKeyCache(p_url string, p_client *http.Client, p_ttl time.Duration) *keyCache {
+	return &keyCache{url: p_url, client: p_client, ttl: p_ttl}
+}
+
+// get returns the public key with the given ID. The keys are refreshed when they are older
+// than the TTL, or when the ID is unknown, which happens after a key rotation.
+func (c *keyCache) get(p_ctx context.Context, p_kid string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.keys == nil || time.Since(c.fetchedAt) > c.ttl {
+		if err := c.refresh(p_ctx); err != nil && c.keys == nil {
+			return nil, err
+		}
+	}
+
+	if key, ok := c.keys[p_kid]; ok {
+		return key, nil
+	}
+
+	if time.Since(c.fetchedAt) > minRefreshInterval {
+		if err := c.refresh(p_ctx); err != nil {
+			return nil, err
+		}
+		if key, ok := c.keys[p_kid]; ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w - %q", ErrUnknownKey, p_kid)
+}
+
+// refresh downloads the key set. Keys that cannot be decoded are skipped. On failure the
+// previous keys are kept.
+func (c *keyCache) refresh(p_ctx context.Context) error {
+	c.fetchedAt = time.Now()
+
+	req, err := http.NewRequestWithContext(p_ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch keys, status %d", res.StatusCode)
+	}
+
+	var set jwks.Set
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for i := range set.Keys {
+		if set.Keys[i].Use != "" && set.Keys[i].Use != "sig" {
+			continue
+		}
+		pub, err := set.Keys[i].PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[set.Keys[i].Kid] = pub
+	}
+	if len(keys) == 0 {
+		return errors.New("the key set holds no usable key")
+	}
+
+	c.keys = keys
+	return nil
+}
+
+// endregion Private
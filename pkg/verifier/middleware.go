@@ -0,0 +1,95 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// claimsKey is the context key under which Middleware stores the verified claims.
+type claimsKey struct{}
+
+// region Public
+
+// Middleware is an HTTP middleware that only lets through requests carrying a valid
+// "Authorization: Bearer <token>" header. The verified claims are stored in the request
+// context and can be read with ClaimsFromContext.
+//
+// Rejected requests are answered with 401 and a WWW-Authenticate header, or with 503 when
+// Cerberus cannot be reached.
+//
+// Parameters:
+//   - p_next: The next http.Handler in the middleware chain.
+//
+// Returns:
+//   - http.Handler: The middleware function.
+func (v *Verifier) Middleware(p_next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tkn, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || tkn == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer`)
+			http.Error(w, "Missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		claims, err := v.Verify(r.Context(), tkn)
+		if errors.Is(err, ErrInvalidToken) {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			return
+		} else if err != nil {
+			http.Error(w, "Token verification unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		p_next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsKey{}, claims)))
+	})
+}
+
+// RequireScopes returns an HTTP middleware that only lets through tokens granted every one
+// of the given scopes. It must run after Middleware.
+//
+// Parameters:
+//   - p_scopes: The required scopes.
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware function.
+func RequireScopes(p_scopes ...string) func(http.Handler) http.Handler {
+	return func(p_next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := ClaimsFromContext(r.Context())
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer`)
+				http.Error(w, "Missing bearer token", http.StatusUnauthorized)
+				return
+			}
+
+			for _, scope := range p_scopes {
+				if !claims.HasScope(scope) {
+					w.Header().Set("WWW-Authenticate",
+						`Bearer error="insufficient_scope", scope="`+strings.Join(p_scopes, " ")+`"`)
+					http.Error(w, "Insufficient scope", http.StatusForbidden)
+					return
+				}
+			}
+
+			p_next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClaimsFromContext returns the claims stored by Middleware.
+//
+// Parameters:
+//   - p_ctx: The request context.
+//
+// Returns:
+//   - *Claims: The verified claims.
+//   - bool: False if the request did not go through Middleware.
+func ClaimsFromContext(p_ctx context.Context) (*Claims, bool) {
+	claims, ok := p_ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
+
+// endregion Public
@@ -0,0 +1,199 @@
+// Package verifier verifies Cerberus access tokens in downstream Go services.
+//
+// Tokens are verified locally with the public keys published on the Cerberus JWKS endpoint,
+// remotely through the Cerberus validate endpoint, or both:
+//
+//	v, err := verifier.New(verifier.Config{
+//		Issuer:   "cerberus",
+//		Audience: "orders",
+//		JWKSURL:  "https://auth.example.com/.well-known/jwks.json",
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	mux.Handle("/orders", v.Middleware(verifier.RequireScopes("orders:read")(ordersHandler)))
+//
+// Handlers read the verified claims with ClaimsFromContext.
+package verifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultCacheTTL time.Duration = 5 * time.Minute
+	defaultLeeway   time.Duration = 30 * time.Second
+	defaultTimeout  time.Duration = 10 * time.Second
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid verifier configuration")
+	ErrInvalidToken  = errors.New("invalid token")
+	ErrUnknownKey    = errors.New("unknown signing key")
+)
+
+// defaultAlgorithms lists the asymmetric algorithms Cerberus can sign with.
+var defaultAlgorithms = []string{"RS256", "ES256", "ES384", "ES512", "EdDSA"}
+
+// Config holds the settings of a Verifier. At least one of JWKSURL and IntrospectionURL must be set.
+//
+// Fields:
+//   - Issuer: The expected "iss" claim, the JWT_ISSUER of the Cerberus instance.
+//   - Audience: The audience of the calling service; tokens must list it in their "aud" claim.
+//   - JWKSURL: The Cerberus JWKS endpoint, enabling local verification.
+//   - IntrospectionURL: The Cerberus validate endpoint ("/session/validate"), used when JWKSURL
+//     is empty or AlwaysIntrospect is set.
+//   - AlwaysIntrospect: Also check locally verified tokens remotely, so that revoked tokens are
+//     rejected immediately at the cost of one request per verification.
+//   - HTTPClient: The client used to reach Cerberus, a client with a 10s timeout by default.
+//   - CacheTTL: How long the fetched keys are used before being refreshed, 5 minutes by default.
+//   - Leeway: The clock skew tolerated on the time based claims, 30 seconds by default.
+//   - Algorithms: The accepted signing algorithms, every asymmetric algorithm by default.
+type Config struct {
+	Issuer           string
+	Audience         string
+	JWKSURL          string
+	IntrospectionURL string
+	AlwaysIntrospect bool
+	HTTPClient       *http.Client
+	CacheTTL         time.Duration
+	Leeway           time.Duration
+	Algorithms       []string
+}
+
+// Claims holds the verified claims of a Cerberus access token.
+//
+// Fields:
+//   - UserID: The ID of the user the token was issued to.
+//   - Scope: The space separated scopes granted to the token.
+//   - Act: The admin acting as the user, set on impersonation tokens.
+type Claims struct {
+	UserID string `json:"user_id"`
+	Scope  string `json:"scope,omitempty"`
+	Act    *Actor `json:"act,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Actor identifies the party acting on behalf of the subject of a token ("act" claim).
+//
+// Fields:
+//   - Subject: The ID of the acting user.
+//   - Act: The actor the acting user itself acts for, nil if none.
+type Actor struct {
+	Subject string `json:"sub"`
+	Act     *Actor `json:"act,omitempty"`
+}
+
+// Scopes returns the scopes granted to the token.
+func (c *Claims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+// HasScope checks whether a scope was granted to the token.
+func (c *Claims) HasScope(p_scope string) bool {
+	return slices.Contains(c.Scopes(), p_scope)
+}
+
+// Verifier verifies Cerberus access tokens. It is safe for concurrent use.
+type Verifier struct {
+	cfg    Config
+	keys   *keyCache
+	parser *jwt.Parser
+}
+
+// region Public
+
+// New creates a Verifier from a configuration, applying the defaults of the unset fields.
+//
+// Parameters:
+//   - p_cfg: The verifier settings.
+//
+// Returns:
+//   - *Verifier: The verifier.
+//   - error: ErrInvalidConfig if a required setting is missing.
+func New(p_cfg Config) (*Verifier, error) {
+	if p_cfg.Issuer == "" || p_cfg.Audience == "" {
+		return nil, errors.Join(ErrInvalidConfig, errors.New("issuer and audience are required"))
+	}
+	if p_cfg.JWKSURL == "" && p_cfg.IntrospectionURL == "" {
+		return nil, errors.Join(ErrInvalidConfig, errors.New("a JWKS or an introspection URL is required"))
+	}
+	if p_cfg.AlwaysIntrospect && p_cfg.IntrospectionURL == "" {
+		return nil, errors.Join(ErrInvalidConfig, errors.New("AlwaysIntrospect requires an introspection URL"))
+	}
+
+	if p_cfg.HTTPClient == nil {
+		p_cfg.HTTPClient = &http.Client{Timeout: defaultTimeout}
+	}
+	if p_cfg.CacheTTL <= 0 {
+		p_cfg.CacheTTL = defaultCacheTTL
+	}
+	if p_cfg.Leeway <= 0 {
+		p_cfg.Leeway = defaultLeeway
+	}
+	if len(p_cfg.Algorithms) == 0 {
+		p_cfg.Algorithms = defaultAlgorithms
+	}
+
+	v := &Verifier{
+		cfg: p_cfg,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(p_cfg.Algorithms),
+			jwt.WithIssuer(p_cfg.Issuer),
+			jwt.WithAudience(p_cfg.Audience),
+			jwt.WithLeeway(p_cfg.Leeway),
+			jwt.WithIssuedAt(),
+			jwt.WithExpirationRequired(),
+		),
+	}
+	if p_cfg.JWKSURL != "" {
+		v.keys = newKeyCache(p_cfg.JWKSURL, p_cfg.HTTPClient, p_cfg.CacheTTL)
+	}
+
+	return v, nil
+}
+
+// Verify checks an access token and returns its claims.
+//
+// Parameters:
+//   - p_ctx: The context bounding the requests sent to Cerberus.
+//   - p_token: The raw access token, without the "Bearer " prefix.
+//
+// Returns:
+//   - *Claims: The verified claims.
+//   - error: An error wrapping ErrInvalidToken if the token is rejected, or the error of a
+//     request sent to Cerberus.
+func (v *Verifier) Verify(p_ctx context.Context, p_token string) (*Claims, error) {
+	if v.keys == nil {
+		return v.introspect(p_ctx, p_token)
+	}
+
+	claims := &Claims{}
+	_, err := v.parser.ParseWithClaims(p_token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.get(p_ctx, kid)
+	})
+	if err != nil {
+		return nil, errors.Join(ErrInvalidToken, err)
+	}
+	if claims.ID == "" {
+		return nil, errors.Join(ErrInvalidToken, errors.New("missing jti"))
+	}
+
+	if v.cfg.AlwaysIntrospect {
+		if _, err := v.introspect(p_ctx, p_token); err != nil {
+			return nil, err
+		}
+	}
+	return claims, nil
+}
+
+// endregion Public